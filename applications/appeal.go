@@ -0,0 +1,464 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// how long invites for accepted appeals are valid for
+const appealInviteAge = 7 * 24 * time.Hour
+
+// appealCase returns the decision the given user can currently appeal, or nil if there is none.
+// Bans take precedence over denied applications.
+func (bot *Bot) appealCase(s *state.State, userID discord.UserID) (*db.Appeal, error) {
+	entries, err := bot.DB.ModLogFor(bot.DB.BotConfig.GuildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.ActionType == "unban" {
+			break
+		}
+		if e.ActionType != "ban" {
+			continue
+		}
+
+		// the user might have been unbanned outside of the bot
+		if _, err := s.GetBan(bot.DB.BotConfig.GuildID, userID); err != nil {
+			break
+		}
+
+		id := e.ID
+		return &db.Appeal{UserID: userID, Kind: db.BanAppeal, ModLogID: &id}, nil
+	}
+
+	apps, err := bot.DB.AllUserApplications(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the user's latest application can be appealed
+	if len(apps) > 0 && apps[0].Verified != nil && !*apps[0].Verified {
+		id := apps[0].ID
+		return &db.Appeal{UserID: userID, Kind: db.DenyAppeal, ApplicationID: &id}, nil
+	}
+	return nil, nil
+}
+
+// appealableCase returns the decision the given user can appeal, or a message explaining why they can't appeal.
+func (bot *Bot) appealableCase(s *state.State, userID discord.UserID) (*db.Appeal, string, error) {
+	if !bot.DB.Config.Get("appeals_channel").ToChannelID().IsValid() {
+		return nil, "Appeals aren't enabled on this server.", nil
+	}
+
+	pending, err := bot.DB.PendingAppeal(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if pending != nil {
+		return nil, "You already have a pending appeal! Please wait for staff to review it.", nil
+	}
+
+	c, err := bot.appealCase(s, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if c == nil {
+		return nil, "You don't have anything to appeal.", nil
+	}
+
+	appealed, err := bot.DB.CaseAppealed(c.ApplicationID, c.ModLogID)
+	if err != nil {
+		return nil, "", err
+	}
+	if appealed {
+		return nil, "You've already appealed this decision.", nil
+	}
+
+	return c, "", nil
+}
+
+// createAppeal files an appeal for the given user and posts it for review.
+// The returned string is a message to show to the user.
+func (bot *Bot) createAppeal(s *state.State, u discord.User, content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "You must explain why the decision should be reconsidered!", nil
+	}
+	if len(content) > 3000 {
+		return fmt.Sprintf("Your appeal is too long (%v > 3000 characters).", len(content)), nil
+	}
+
+	c, msg, err := bot.appealableCase(s, u.ID)
+	if err != nil || c == nil {
+		return msg, err
+	}
+
+	c.Content = content
+	a, err := bot.DB.CreateAppeal(*c)
+	if err != nil {
+		return "", err
+	}
+
+	chID := bot.DB.Config.Get("appeals_channel").ToChannelID()
+	m, err := s.SendMessageComplex(chID, api.SendMessageData{
+		Embeds:     []discord.Embed{bot.appealEmbed(s, a)},
+		Components: appealReviewButtons(a.ID),
+	})
+	if err != nil {
+		bot.SendError("Error posting appeal #%v from %v: %v", a.ID, u.Mention(), err)
+		return "Your appeal was saved, but I couldn't send it to staff! Please contact a staff member directly.", nil
+	}
+
+	err = bot.DB.SetAppealMessage(a.ID, m.ChannelID, m.ID)
+	if err != nil {
+		common.Log.Errorf("Error setting message for appeal %v: %v", a.ID, err)
+	}
+
+	return "Your appeal was submitted! Staff will review it, and I'll message you once a decision has been made.", nil
+}
+
+func appealReviewButtons(id int64) discord.ContainerComponents {
+	return discord.Components(
+		&discord.ButtonComponent{
+			Label:    "Accept",
+			Style:    discord.SuccessButtonStyle(),
+			CustomID: discord.ComponentID(fmt.Sprintf("appeal-accept:%v", id)),
+		},
+		&discord.ButtonComponent{
+			Label:    "Reject",
+			Style:    discord.DangerButtonStyle(),
+			CustomID: discord.ComponentID(fmt.Sprintf("appeal-reject:%v", id)),
+		},
+	)
+}
+
+// appealEmbed returns the embed shown to staff for the given appeal.
+func (bot *Bot) appealEmbed(s *state.State, a db.Appeal) discord.Embed {
+	e := discord.Embed{
+		Title:       fmt.Sprintf("Appeal #%v", a.ID),
+		Description: a.Content,
+		Color:       bot.Colour,
+		Footer:      &discord.EmbedFooter{Text: "User ID: " + a.UserID.String()},
+		Timestamp:   discord.NewTimestamp(a.Created),
+	}
+
+	if u, err := s.User(a.UserID); err == nil {
+		e.Author = &discord.EmbedAuthor{
+			Name: u.Tag(),
+			Icon: u.AvatarURL(),
+		}
+	}
+
+	e.Fields = append(e.Fields, discord.EmbedField{
+		Name:  "User",
+		Value: a.UserID.Mention(),
+	}, bot.appealCaseField(a))
+
+	switch a.Status {
+	case db.AppealAccepted, db.AppealRejected:
+		e.Color = bcr.ColourGreen
+		if a.Status == db.AppealRejected {
+			e.Color = bcr.ColourRed
+		}
+
+		value := strings.Title(a.Status)
+		if a.Moderator != nil {
+			value += " by " + a.Moderator.Mention()
+		}
+		if a.Decided != nil {
+			value += fmt.Sprintf(" <t:%v:R>", a.Decided.Unix())
+		}
+		if a.Reason != nil {
+			value += "\n" + *a.Reason
+		}
+
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Decision",
+			Value: value,
+		})
+	}
+
+	return e
+}
+
+// appealCaseField returns a field describing the decision that was appealed.
+func (bot *Bot) appealCaseField(a db.Appeal) discord.EmbedField {
+	guildID := bot.DB.BotConfig.GuildID
+
+	if a.Kind == db.BanAppeal {
+		if a.ModLogID == nil {
+			return discord.EmbedField{Name: "Appealing ban", Value: "Unknown case (deleted?)"}
+		}
+
+		entry, err := bot.DB.ModLogEntry(*a.ModLogID)
+		if err != nil {
+			return discord.EmbedField{Name: "Appealing ban", Value: fmt.Sprintf("Case %v (not found)", *a.ModLogID)}
+		}
+
+		value := fmt.Sprintf("Case %v, banned by %v <t:%v>\n**Reason:** %v", entry.ID, entry.ModID.Mention(), entry.Time.Unix(), entry.Reason)
+		if entry.ChannelID != nil && entry.MessageID != nil {
+			value += fmt.Sprintf("\n[Jump to log](https://discord.com/channels/%v/%v/%v)", guildID, *entry.ChannelID, *entry.MessageID)
+		}
+		return discord.EmbedField{Name: "Appealing ban", Value: truncate(value, 1024)}
+	}
+
+	if a.ApplicationID == nil {
+		return discord.EmbedField{Name: "Appealing denial", Value: "Unknown application (deleted?)"}
+	}
+
+	app, err := bot.DB.Application(*a.ApplicationID)
+	if err != nil {
+		return discord.EmbedField{Name: "Appealing denial", Value: fmt.Sprintf("Application `%v` (not found)", *a.ApplicationID)}
+	}
+
+	value := fmt.Sprintf("Application `%v`", app.ID)
+	if app.Moderator != nil {
+		value += ", denied by " + app.Moderator.Mention()
+	}
+	if app.DenyReason != nil {
+		value += "\n**Reason:** " + *app.DenyReason
+	}
+	if app.TranscriptChannel != nil && app.TranscriptMessage != nil {
+		value += fmt.Sprintf("\n[Jump to transcript](https://discord.com/channels/%v/%v/%v)", guildID, *app.TranscriptChannel, *app.TranscriptMessage)
+	}
+	return discord.EmbedField{Name: "Appealing denial", Value: truncate(value, 1024)}
+}
+
+// updateAppealMessage updates the review message for a decided appeal, and removes its buttons.
+func (bot *Bot) updateAppealMessage(s *state.State, a db.Appeal) {
+	if a.ChannelID == nil || a.MessageID == nil {
+		return
+	}
+
+	_, err := s.EditMessageComplex(*a.ChannelID, *a.MessageID, api.EditMessageData{
+		Embeds:     &[]discord.Embed{bot.appealEmbed(s, a)},
+		Components: &discord.ContainerComponents{},
+	})
+	if err != nil {
+		common.Log.Errorf("Error updating message for appeal %v: %v", a.ID, err)
+	}
+}
+
+// dmAppealResult tells the user that their appeal was decided.
+func (bot *Bot) dmAppealResult(s *state.State, a db.Appeal, desc string) error {
+	g, err := s.Guild(bot.DB.BotConfig.GuildID)
+	if err != nil {
+		return err
+	}
+
+	e := discord.Embed{
+		Title:       "Your appeal was " + a.Status,
+		Description: strings.ReplaceAll(desc, "{guild}", g.Name),
+		Color:       bot.Colour,
+		Timestamp:   discord.NowTimestamp(),
+	}
+	if a.Reason != nil {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Reason",
+			Value: *a.Reason,
+		})
+	}
+
+	ch, err := s.CreatePrivateChannel(a.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.SendEmbeds(ch.ID, e)
+	return err
+}
+
+// appealInvite creates a single-use invite for a user whose appeal was accepted,
+// or returns an empty string if that isn't possible.
+func (bot *Bot) appealInvite(s *state.State, a db.Appeal) string {
+	chID := bot.DB.Config.Get("invite_channel").ToChannelID()
+	if !bot.DB.Config.Get("appeal_invite").ToBool() || !chID.IsValid() {
+		return ""
+	}
+
+	inv, err := s.CreateInvite(chID, api.CreateInviteData{
+		MaxAge:         option.NewUint(uint(appealInviteAge / time.Second)),
+		MaxUses:        1,
+		Unique:         true,
+		AuditLogReason: api.AuditLogReason(fmt.Sprintf("Invite for accepted appeal #%v", a.ID)),
+	})
+	if err != nil {
+		common.Log.Errorf("Error creating invite for appeal %v: %v", a.ID, err)
+		return ""
+	}
+
+	err = bot.DB.SetInviteName(inv.Code, fmt.Sprintf("Appeal #%v", a.ID))
+	if err != nil {
+		common.Log.Errorf("Error setting invite name for appeal %v: %v", a.ID, err)
+	}
+
+	return "https://discord.gg/" + inv.Code
+}
+
+// acceptBanAppeal unbans the user.
+func (bot *Bot) acceptBanAppeal(s *state.State, a db.Appeal, mod discord.User) (string, error) {
+	reason := fmt.Sprintf("Appeal #%v accepted", a.ID)
+
+	err := s.Unban(bot.DB.BotConfig.GuildID, a.UserID, api.AuditLogReason(mod.Tag()+": "+reason))
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := bot.DB.InsertModLog(context.Background(), db.ModLogEntry{
+		GuildID:    bot.DB.BotConfig.GuildID,
+		UserID:     a.UserID,
+		ModID:      mod.ID,
+		ActionType: "unban",
+		Reason:     reason,
+	})
+	if err != nil {
+		bot.SendError("Error adding mod log entry for appeal #%v: %v", a.ID, err)
+	} else if logCh := bot.DB.Config.Get("mod_log").ToChannelID(); logCh.IsValid() {
+		log, err := s.SendMessage(logCh, "", entry.Embed(s))
+		if err != nil {
+			common.Log.Errorf("error sending mod log message: %v", err)
+		} else {
+			_, err = bot.DB.UpdateModLogMessage(entry.ID, logCh, log.ID)
+			if err != nil {
+				common.Log.Errorf("error updating mod log message in db: %v", err)
+			}
+		}
+	}
+
+	desc := "Your ban appeal in {guild} was accepted, and you were unbanned."
+	if inv := bot.appealInvite(s, a); inv != "" {
+		desc += "\nYou can rejoin the server with this invite: " + inv
+	}
+
+	err = bot.dmAppealResult(s, a, desc)
+	if err != nil {
+		return "Appeal accepted and the user was unbanned, but I couldn't DM them.", nil
+	}
+	return "Appeal accepted and the user was unbanned.", nil
+}
+
+// acceptDenyAppeal removes the user's reapplication cooldown, and opens a new application if they're still in the server.
+func (bot *Bot) acceptDenyAppeal(s *state.State, a db.Appeal) (string, error) {
+	err := bot.DB.ClearAppCooldown(a.UserID)
+	if err != nil && err != pgx.ErrNoRows {
+		return "", err
+	}
+
+	bot.deniedMu.Lock()
+	delete(bot.denied, a.UserID)
+	bot.deniedMu.Unlock()
+
+	var desc, reply string
+
+	m, err := s.Member(bot.DB.BotConfig.GuildID, a.UserID)
+	if err == nil {
+		ch, existing, err := bot.reopenApplication(s, *m)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case existing:
+			desc = "Your appeal in {guild} was accepted! You already have an open application in " + ch.Mention() + "."
+			reply = "Appeal accepted. The user already has an open application in " + ch.Mention() + ", so no new one was opened."
+		case ch == nil:
+			desc = "Your appeal in {guild} was accepted! You can apply again with the application button in the server."
+			reply = "Appeal accepted. The user was told they can apply again."
+		default:
+			desc = "Your appeal in {guild} was accepted! A new application was opened for you in " + ch.Mention() + "."
+			reply = "Appeal accepted, a new application was opened in " + ch.Mention() + "."
+		}
+	} else {
+		desc = "Your appeal in {guild} was accepted! You may rejoin the server and apply again."
+		if inv := bot.appealInvite(s, a); inv != "" {
+			desc += "\nYou can rejoin the server with this invite: " + inv
+		}
+		reply = "Appeal accepted. The user isn't in the server, so they were told they can rejoin and apply again."
+	}
+
+	err = bot.dmAppealResult(s, a, desc)
+	if err != nil {
+		reply += "\nNote: I couldn't DM the user."
+	}
+	return reply, nil
+}
+
+// reopenApplication opens a new application for the given member, the same way the open application button does.
+// If the member already has an open application, that application's channel is returned and existing is true.
+// If applicants have to choose a track before their application is opened, no application is opened and ch is nil.
+func (bot *Bot) reopenApplication(s *state.State, m discord.Member) (ch *discord.Channel, existing bool, err error) {
+	open, err := bot.DB.UserApplication(m.User.ID)
+	if err == nil {
+		ch, err := s.Channel(open.ChannelID)
+		if err == nil {
+			return ch, true, nil
+		}
+
+		// no channel, app should've been closed
+		err = bot.DB.CloseApplication(open.ID)
+		if err != nil {
+			bot.SendError("Error closing existing app: %v", err)
+		}
+	} else if err != pgx.ErrNoRows {
+		return nil, false, err
+	}
+
+	tracks, err := bot.DB.ApplicationTracks()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the track prompt and modals can only be shown in response to the user's own interaction
+	if bot.chooseTrackFirst(tracks) {
+		return nil, false, nil
+	}
+
+	ch, err = bot.newApplicationChannel(m)
+	if err != nil {
+		return nil, false, err
+	}
+
+	app, err := bot.DB.CreateApplication(m.User.ID, ch.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = bot.sendInitialMessage(ch.ID, m)
+	if err != nil {
+		bot.SendError("Error sending initial message: %v", err)
+	}
+
+	bot.announceRiskFlags(m, ch.ID)
+
+	_, err = scheduleTimeout(bot.Bot, &app, timeoutReminder)
+	if err != nil {
+		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
+	}
+
+	return ch, false, nil
+}
+
+func (bot *Bot) appeal(ctx *bcr.Context) (err error) {
+	if ctx.Message.GuildID.IsValid() {
+		return ctx.SendX("Please use this command in my DMs!")
+	}
+
+	msg, err := bot.createAppeal(ctx.State, ctx.Author, ctx.RawArgs)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	return ctx.SendX(msg)
+}
@@ -0,0 +1,177 @@
+package applications
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// openAppeal shows the appeal form to a denied or banned user.
+func (bot *Bot) openAppeal(ctx *bcr.ButtonContext) (err error) {
+	_, msg, err := bot.appealableCase(ctx.State, ctx.User.ID)
+	if err != nil {
+		bot.SendError("Error checking if %v can appeal: %v", ctx.User.Mention(), err)
+		return ctx.ReplyEphemeral("Internal error occurred! Please try again later.")
+	}
+	if msg != "" {
+		return ctx.ReplyEphemeral(msg)
+	}
+
+	return bcr.ModalResponse(
+		ctx.InteractionID, ctx.InteractionToken, ctx.State,
+		"appeal-modal", "Appeal",
+		&discord.TextInputComponent{
+			CustomID:    "appeal",
+			Style:       discord.TextInputParagraphStyle,
+			Label:       "Why should we reconsider this decision?",
+			ValueLimits: [2]int{1, 3000},
+			Required:    true,
+		},
+	)
+}
+
+// submitAppeal files the appeal from the appeal form.
+func (bot *Bot) submitAppeal(ctx *bcr.ModalContext) (err error) {
+	if err = ctx.DeferEphemeral(); err != nil {
+		return err
+	}
+
+	var content string
+	for _, input := range ctx.TextInputs {
+		if input.CustomID == "appeal" {
+			content = input.Value.Val
+		}
+	}
+
+	msg, err := bot.createAppeal(ctx.State, ctx.User, content)
+	if err != nil {
+		bot.SendError("Error creating appeal for %v: %v", ctx.User.Mention(), err)
+		return ctx.ReplyEphemeral("Internal error occurred! Please try again later.")
+	}
+	return ctx.ReplyEphemeral(msg)
+}
+
+// reviewAppeal returns the appeal referenced by the custom ID, if the member is allowed to review it.
+// Ban appeals require permission to use `unban`, deny appeals require permission to use `deny`.
+func (bot *Bot) reviewAppeal(ctx *bcr.Context, id discord.ComponentID, prefix string) (*db.Appeal, error) {
+	appealID, err := strconv.ParseInt(strings.TrimPrefix(string(id), prefix), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := bot.DB.Appeal(appealID)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, ctx.ReplyEphemeral("That appeal doesn't exist.")
+		}
+		return nil, err
+	}
+
+	cmd := "deny"
+	if a.Kind == db.BanAppeal {
+		cmd = "unban"
+	}
+
+	if ctx.Member == nil || bot.DB.Perms.Level(ctx.Member) < bot.DB.Overrides.For(cmd) {
+		return nil, ctx.ReplyEphemeral("You're not allowed to review this appeal.")
+	}
+	if a.Status != db.AppealPending {
+		return nil, ctx.ReplyEphemeral("This appeal was already " + a.Status + ".")
+	}
+	return a, nil
+}
+
+// acceptAppeal accepts an appeal, unbanning the user or letting them apply again.
+func (bot *Bot) acceptAppeal(ctx *bcr.ButtonContext) (err error) {
+	a, err := bot.reviewAppeal(ctx.Context, ctx.CustomID, "appeal-accept:")
+	if a == nil {
+		return err
+	}
+
+	if err = ctx.DeferEphemeral(); err != nil {
+		return err
+	}
+
+	a, err = bot.DB.DecideAppeal(a.ID, ctx.User.ID, true, nil)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return ctx.ReplyEphemeral("This appeal was already reviewed.")
+		}
+		return err
+	}
+	bot.updateAppealMessage(ctx.State, *a)
+
+	var msg string
+	if a.Kind == db.BanAppeal {
+		msg, err = bot.acceptBanAppeal(ctx.State, *a, ctx.User)
+	} else {
+		msg, err = bot.acceptDenyAppeal(ctx.State, *a)
+	}
+	if err != nil {
+		bot.SendError("Error accepting appeal #%v: %v", a.ID, err)
+		return ctx.ReplyEphemeral(fmt.Sprintf("The appeal was marked as accepted, but I couldn't carry it out, please do so manually:\n> %v", err))
+	}
+	return ctx.ReplyEphemeral(msg)
+}
+
+// rejectAppeal shows the form for rejecting an appeal.
+func (bot *Bot) rejectAppeal(ctx *bcr.ButtonContext) (err error) {
+	a, err := bot.reviewAppeal(ctx.Context, ctx.CustomID, "appeal-reject:")
+	if a == nil {
+		return err
+	}
+
+	return bcr.ModalResponse(
+		ctx.InteractionID, ctx.InteractionToken, ctx.State,
+		fmt.Sprintf("appeal-reject-modal:%v", a.ID), fmt.Sprintf("Reject appeal #%v", a.ID),
+		&discord.TextInputComponent{
+			CustomID:    "reason",
+			Style:       discord.TextInputParagraphStyle,
+			Label:       "Reason (shown to the user)",
+			ValueLimits: [2]int{0, 1000},
+			Required:    false,
+			Placeholder: option.NewNullableString("No reason given."),
+		},
+	)
+}
+
+// submitRejectAppeal rejects an appeal and lets the user know.
+func (bot *Bot) submitRejectAppeal(ctx *bcr.ModalContext) (err error) {
+	a, err := bot.reviewAppeal(ctx.Context, ctx.CustomID, "appeal-reject-modal:")
+	if a == nil {
+		return err
+	}
+
+	if err = ctx.DeferEphemeral(); err != nil {
+		return err
+	}
+
+	var reason *string
+	for _, input := range ctx.TextInputs {
+		if input.CustomID == "reason" && strings.TrimSpace(input.Value.Val) != "" {
+			reason = &input.Value.Val
+		}
+	}
+
+	a, err = bot.DB.DecideAppeal(a.ID, ctx.User.ID, false, reason)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return ctx.ReplyEphemeral("This appeal was already reviewed.")
+		}
+		return err
+	}
+	bot.updateAppealMessage(ctx.State, *a)
+
+	err = bot.dmAppealResult(ctx.State, *a, "Your appeal in {guild} was rejected.")
+	if err != nil {
+		return ctx.ReplyEphemeral("Appeal rejected, but I couldn't DM the user.")
+	}
+	return ctx.ReplyEphemeral("Appeal rejected.")
+}
@@ -6,12 +6,11 @@ import (
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
-	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/mozillazg/go-unidecode"
 	"github.com/starshine-sys/oodles/db"
 )
 
-func (bot *Bot) completeApp(app *db.Application, m *gateway.MessageCreateEvent) error {
+func (bot *Bot) completeApp(app *db.Application, u discord.User) error {
 	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
 
 	err := bot.DB.CompleteApp(app.ID)
@@ -22,6 +21,19 @@ func (bot *Bot) completeApp(app *db.Application, m *gateway.MessageCreateEvent)
 
 	msg := bot.DB.Config.Get("application_finished_message").ToString()
 
+	var reviewers []discord.RoleID
+	if app.TrackID != nil {
+		track, err := bot.DB.ApplicationTrack(*app.TrackID)
+		if err == nil {
+			if track.FinishedMessage != nil {
+				msg = *track.FinishedMessage
+			}
+			reviewers = track.ReviewerRoleIDs()
+		} else {
+			bot.SendError("Error getting track %v for app %v: %v", *app.TrackID, app.ID, err)
+		}
+	}
+
 	// give time for pk to proxy
 	time.Sleep(waitTime)
 	err = bot.sendInterviewMessage(app, msg)
@@ -32,7 +44,22 @@ func (bot *Bot) completeApp(app *db.Application, m *gateway.MessageCreateEvent)
 
 	discussion := bot.DB.Config.Get("discussion_channel").ToChannelID()
 	if discussion.IsValid() {
-		msg, err := s.SendMessage(discussion, fmt.Sprintf("%v (%v) has finished their application! What do you think?", app.UserID.Mention(), app.ChannelID.Mention()))
+		var flags string
+		if m, err := s.Member(bot.DB.BotConfig.GuildID, app.UserID); err == nil {
+			flags = riskFlagString(bot.riskFlags(*m))
+		}
+
+		var pings string
+		for _, r := range reviewers {
+			pings += r.Mention() + " "
+		}
+
+		msg, err := s.SendMessageComplex(discussion, api.SendMessageData{
+			Content: fmt.Sprintf("%v%v (%v) has finished their application! What do you think?%v", pings, app.UserID.Mention(), app.ChannelID.Mention(), flags),
+			AllowedMentions: &api.AllowedMentions{
+				Roles: reviewers,
+			},
+		})
 		if err == nil {
 			go func() {
 				for _, e := range []discord.APIEmoji{"✅", "❌", "🤔"} {
@@ -43,7 +70,7 @@ func (bot *Bot) completeApp(app *db.Application, m *gateway.MessageCreateEvent)
 	}
 
 	return s.ModifyChannel(app.ChannelID, api.ModifyChannelData{
-		Name:           "✅-app-" + unidecode.Unidecode(m.Author.Username),
+		Name:           "✅-app-" + unidecode.Unidecode(u.Username),
 		AuditLogReason: "Completed application, waiting for followup",
 	})
 }
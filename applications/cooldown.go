@@ -0,0 +1,60 @@
+package applications
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// cooldownMessage returns the message shown to a user who tries to apply while on cooldown.
+func cooldownMessage(c db.AppCooldown) string {
+	if c.Permanent() {
+		return "You may not reapply to this server. If you think this is a mistake, please contact a staff member."
+	}
+
+	return fmt.Sprintf("You can't open a new application yet! You may try again <t:%v:R> (<t:%v>).", c.Expires.Unix(), c.Expires.Unix())
+}
+
+// setCooldown sets a cooldown for the given application's user, with the length taken from the given config key.
+// If permanent is true, the user may never reapply.
+func (bot *Bot) setCooldown(app *db.Application, key, reason string, mod *discord.UserID, permanent bool) (*db.AppCooldown, error) {
+	c := db.AppCooldown{
+		UserID:        app.UserID,
+		Reason:        reason,
+		Moderator:     mod,
+		ApplicationID: &app.ID,
+	}
+
+	if !permanent {
+		hours := bot.DB.Config.Get(key).ToInt()
+		if hours <= 0 {
+			return nil, nil
+		}
+
+		t := time.Now().UTC().Add(time.Duration(hours) * time.Hour)
+		c.Expires = &t
+	}
+
+	return &c, bot.DB.SetAppCooldown(c)
+}
+
+func (bot *Bot) allowReapply(ctx *bcr.Context) (err error) {
+	u, err := ctx.ParseUser(ctx.RawArgs)
+	if err != nil {
+		return ctx.SendX("User not found.")
+	}
+
+	err = bot.DB.ClearAppCooldown(u.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ctx.SendfX("%v can already apply.", u.Tag())
+		}
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("%v can now open a new application.", u.Tag())
+}
@@ -27,6 +27,8 @@ func (bot *Bot) deny(ctx *bcr.Context) (err error) {
 		appChannelID = ch.ID
 	}
 
+	_, permanent := params.Match("--permanent", "-p", "--no-reapply")
+
 	app, err := bot.DB.ChannelApplication(appChannelID)
 	if err != nil {
 		return ctx.SendX("That isn't an application channel!")
@@ -93,7 +95,24 @@ func (bot *Bot) deny(ctx *bcr.Context) (err error) {
 		}
 	}
 
+	cooldown, err := bot.setCooldown(app, "deny_cooldown", reason, &ctx.Author.ID, permanent)
+	if err != nil {
+		bot.SendError("Error setting reapplication cooldown for %v: %v", app.UserID, err)
+		_ = ctx.SendX("Note: I wasn't able to set a reapplication cooldown for the user.")
+	}
+
 	if dm {
+		fields := []discord.EmbedField{{
+			Name:  "Reason",
+			Value: reason,
+		}}
+		if cooldown != nil {
+			fields = append(fields, discord.EmbedField{
+				Name:  "Reapplying",
+				Value: cooldownMessage(*cooldown),
+			})
+		}
+
 		ch, err := ctx.State.CreatePrivateChannel(m.User.ID)
 		if err != nil {
 			err := ctx.SendX("Note: I wasn't able to DM the user about their denial.")
@@ -101,16 +120,20 @@ func (bot *Bot) deny(ctx *bcr.Context) (err error) {
 				common.Log.Errorf("Error sending message: %v", err)
 			}
 		} else {
-			_, err = ctx.State.SendEmbeds(ch.ID, discord.Embed{
-				Title:       "You were denied",
-				Description: "Your application in " + ctx.Guild.Name + " was denied.",
-				Fields: []discord.EmbedField{{
-					Name:  "Reason",
-					Value: reason,
+			data := api.SendMessageData{
+				Embeds: []discord.Embed{{
+					Title:       "You were denied",
+					Description: "Your application in " + ctx.Guild.Name + " was denied.",
+					Fields:      fields,
+					Color:       bot.Colour,
+					Timestamp:   discord.NowTimestamp(),
 				}},
-				Color:     bot.Colour,
-				Timestamp: discord.NowTimestamp(),
-			})
+			}
+			if bot.DB.Config.Get("appeals_channel").ToChannelID().IsValid() {
+				data.Components = common.AppealButton()
+			}
+
+			_, err = ctx.State.SendMessageComplex(ch.ID, data)
 			if err != nil {
 				err := ctx.SendX("Note: I wasn't able to DM the user about their denial.")
 				if err != nil {
@@ -158,7 +181,7 @@ func (bot *Bot) deny(ctx *bcr.Context) (err error) {
 		}
 	}
 
-	_, err = bot.createTranscript(ctx.State, app)
+	_, err = createTranscript(bot.Bot, ctx.State, app)
 	if err != nil {
 		return ctx.SendfX("There was an error saving a transcript:\n> %v", err)
 	}
@@ -176,6 +199,10 @@ func (bot *Bot) deny(ctx *bcr.Context) (err error) {
 	}
 
 	// edit channel
+	if ch, err := ctx.State.Channel(app.ChannelID); err == nil && isThread(ch) {
+		return bot.finishThread(ctx.State, app, "🔒-app-"+unidecode.Unidecode(m.User.Username), "Application completed, user denied", true)
+	}
+
 	newCat := discord.ChannelID(bot.DB.Config.Get("finished_application_category").ToSnowflake())
 	if !newCat.IsValid() {
 		newCat = ctx.Channel.ParentID
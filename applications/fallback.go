@@ -1,8 +1,6 @@
 package applications
 
 import (
-	"time"
-
 	"github.com/jackc/pgx/v4"
 	"github.com/starshine-sys/bcr"
 	"github.com/starshine-sys/oodles/common"
@@ -51,14 +49,10 @@ func (bot *Bot) fallbackCreate(ctx *bcr.Context) (err error) {
 		return ctx.SendX("I couldn't send the initial message!")
 	}
 
-	eventID, err := bot.Scheduler.Add(
-		time.Now().Add(24*time.Hour), &timeout{ChannelID: ch.ID, UserID: m.User.ID},
-	)
-	if err == nil {
-		if err := bot.DB.SetEventID(app.ID, eventID); err != nil {
-			common.Log.Errorf("error setting event id for app %v: %v", app.ID, err)
-		}
-	} else {
+	bot.announceRiskFlags(*m, ch.ID)
+
+	_, err = scheduleTimeout(bot.Bot, &app, timeoutReminder)
+	if err != nil {
 		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
 	}
 
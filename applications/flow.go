@@ -0,0 +1,311 @@
+package applications
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/dustin/go-humanize/english"
+	"github.com/mozillazg/go-unidecode"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// Application modes
+const (
+	modeChannel = "channel"
+	modeThread  = "thread"
+	modeModal   = "modal"
+)
+
+const errInvalidThreadChannel = errors.Sentinel("invalid application thread channel ID")
+
+// trackMode returns the application mode used for the given track.
+func (bot *Bot) trackMode(t db.ApplicationTrack) string {
+	if t.Mode != nil {
+		return *t.Mode
+	}
+	return bot.DB.Config.Get("application_mode").ToString()
+}
+
+// chooseTrackFirst returns true if the user has to choose a track before their application is opened,
+// which is the case if any track doesn't use a channel.
+func (bot *Bot) chooseTrackFirst(tracks []db.ApplicationTrack) bool {
+	for _, t := range tracks {
+		if bot.trackMode(t) != modeChannel {
+			return true
+		}
+	}
+	return false
+}
+
+func (bot *Bot) sendTrackPrompt(ctx *bcr.ButtonContext, tracks []db.ApplicationTrack) error {
+	if len(tracks) == 0 {
+		bot.SendError("There are no application tracks, can't open an application for %v!", ctx.User.Mention())
+		return ctx.ReplyEphemeral("There are no application tracks set up! Please ping staff for assistance.")
+	}
+
+	str := "Are you "
+
+	var descs []string
+	var buttons discord.ActionRowComponent
+
+	for _, t := range tracks {
+		descs = append(descs, fmt.Sprintf("%v (%s)", t.Description, t.Emoji()))
+		buttons = append(buttons, &discord.ButtonComponent{
+			Label:    t.Name,
+			CustomID: discord.ComponentID("app-open:" + strconv.FormatInt(t.ID, 10)),
+			Style:    discord.SecondaryButtonStyle(),
+			Emoji: &discord.ComponentEmoji{
+				Name:     t.Emoji().Name,
+				ID:       t.Emoji().ID,
+				Animated: t.Emoji().Animated,
+			},
+		})
+	}
+	str += english.OxfordWordSeries(descs, "or") + "?"
+
+	return ctx.ReplyComplex(api.InteractionResponseData{
+		Content:    option.NewNullableString(str),
+		Components: &discord.ContainerComponents{&buttons},
+		Flags:      api.EphemeralResponse,
+	})
+}
+
+// openTrack opens an application with a track that was chosen up front.
+func (bot *Bot) openTrack(ctx *bcr.ButtonContext) (err error) {
+	if ctx.Member == nil {
+		return ctx.ReplyEphemeral("This event didn't have a member associated with it! This is a bug, please report it to the developer (such as by DMing me!)")
+	}
+
+	trackID, err := strconv.ParseInt(strings.TrimPrefix(string(ctx.CustomID), "app-open:"), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	track, err := bot.DB.ApplicationTrack(trackID)
+	if err != nil {
+		return ctx.ReplyEphemeral("That application track doesn't exist anymore! Please try again.")
+	}
+
+	mode := bot.trackMode(*track)
+
+	// modals have to be the first response to the interaction, so we can't defer here
+	if mode == modeModal {
+		if ok, err := bot.checkCanApply(ctx.Context); !ok {
+			return err
+		}
+
+		qs, err := bot.DB.Questions(track.ID)
+		if err != nil || len(qs) == 0 {
+			bot.SendError("No questions for track ID %v, can't start application!", track.ID)
+			return ctx.ReplyEphemeral("This application track doesn't have any questions! Please ping staff for assistance.")
+		}
+
+		return bot.sendAppModal(ctx.Context, track, qs, 0)
+	}
+
+	if err = ctx.DeferEphemeral(); err != nil {
+		return err
+	}
+
+	if ok, err := bot.checkCanApply(ctx.Context); !ok {
+		return err
+	}
+
+	var ch *discord.Channel
+	if mode == modeThread {
+		ch, err = bot.newApplicationThread(*ctx.Member)
+	} else {
+		ch, err = bot.newApplicationChannel(*ctx.Member)
+	}
+	if err != nil {
+		bot.SendError("Error creating application %v: %v", mode, err)
+		return ctx.ReplyEphemeral("I couldn't create an application " + mode + "!")
+	}
+
+	app, err := bot.DB.CreateApplication(ctx.User.ID, ch.ID)
+	if err != nil {
+		bot.SendError("Error registering application in DB: %v", err)
+		return ctx.ReplyEphemeral("I couldn't save the newly opened application!")
+	}
+
+	err = bot.sendOpenMessage(ch.ID, *ctx.Member)
+	if err != nil {
+		bot.SendError("Error sending initial message: %v", err)
+		return ctx.ReplyEphemeral("I couldn't send the initial message!")
+	}
+
+	bot.announceRiskFlags(*ctx.Member, ch.ID)
+
+	_, err = scheduleTimeout(bot.Bot, &app, timeoutReminder)
+	if err != nil {
+		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
+	}
+
+	err = bot.startTrack(ctx.State, &app, track, false)
+	if err != nil {
+		bot.SendError("Error starting application %v: %v", app.ID, err)
+	}
+
+	return ctx.ReplyEphemeral("Application opened in " + ch.Mention() + "!")
+}
+
+// startTrack sets the application's track and sends the first question.
+func (bot *Bot) startTrack(s *state.State, app *db.Application, track *db.ApplicationTrack, canRestart bool) (err error) {
+	err = bot.DB.SetTrack(app.ID, track.ID)
+	if err != nil {
+		bot.SendError("Error setting application track in %v: %v", app.ChannelID.Mention(), err)
+		_, err = s.SendMessage(app.ChannelID, "Something went wrong! Please ask a mod for assistance.")
+		return
+	}
+
+	msg := fmt.Sprintf("Starting **%v** application!", track.Name)
+	if canRestart {
+		msg += "\nIf you picked the wrong type, or want to restart your application for any other reason, please press the \"restart\" button above!"
+	}
+
+	_, err = s.SendMessage(app.ChannelID, msg)
+	if err != nil {
+		bot.SendError("error sending message: %v", err)
+	}
+
+	if track.CategoryID != nil && bot.trackMode(*track) == modeChannel {
+		err = s.ModifyChannel(app.ChannelID, api.ModifyChannelData{
+			CategoryID:     *track.CategoryID,
+			AuditLogReason: api.AuditLogReason("Move application to " + track.Name + " category"),
+		})
+		if err != nil {
+			bot.SendError("Error moving application %v to category %v: %v", app.ChannelID.Mention(), *track.CategoryID, err)
+		}
+	}
+
+	bot.sendIntroMessage(s, app, track)
+
+	qs, err := bot.DB.Questions(track.ID)
+	if err != nil {
+		bot.SendError("Error getting questions: %v", err)
+		return
+	}
+
+	if len(qs) == 0 {
+		bot.SendError("No questions for track ID %v, can't start application!", track.ID)
+		return
+	}
+
+	err = bot.sendInterviewMessage(app, qs[0].Question)
+	if err != nil {
+		common.Log.Errorf("Error sending message in app %v: %v", app.ChannelID, err)
+		return
+	}
+
+	// set question index to 1 (first user message will start question loop)
+	err = bot.DB.SetQuestionIndex(app.ID, 1)
+	if err != nil {
+		return errors.Wrap(err, "setting question index")
+	}
+
+	return
+}
+
+// sendIntroMessage sends the track's intro message, if it has one.
+func (bot *Bot) sendIntroMessage(s *state.State, app *db.Application, track *db.ApplicationTrack) {
+	if track.IntroMessage == nil {
+		return
+	}
+
+	g, err := s.Guild(bot.DB.BotConfig.GuildID)
+	if err == nil {
+		err = bot.sendInterviewMessage(app, strings.ReplaceAll(*track.IntroMessage, "{guild}", g.Name))
+	}
+	if err != nil {
+		bot.SendError("Error sending track intro message in %v: %v", app.ChannelID.Mention(), err)
+	}
+}
+
+func (bot *Bot) newApplicationThread(m discord.Member) (*discord.Channel, error) {
+	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
+
+	parentID := bot.DB.Config.Get("application_thread_channel").ToChannelID()
+	if !parentID.IsValid() {
+		return nil, errInvalidThreadChannel
+	}
+
+	ch, err := s.StartThreadWithoutMessage(parentID, api.StartThreadData{
+		Name:                "app-" + unidecode.Unidecode(m.User.Username),
+		AutoArchiveDuration: discord.SevenDaysArchive,
+		Type:                discord.GuildPrivateThread,
+		Invitable:           false,
+		AuditLogReason:      "Create application thread",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.AddThreadMember(ch.ID, m.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "adding user to thread")
+	}
+	return ch, nil
+}
+
+// sendOpenMessage sends the open_application_message, without the track selection buttons.
+func (bot *Bot) sendOpenMessage(ch discord.ChannelID, m discord.Member) error {
+	name := m.Nick
+	if name == "" {
+		name = m.User.Username
+	}
+
+	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
+
+	g, err := s.Guild(bot.DB.BotConfig.GuildID)
+	if err != nil {
+		return err
+	}
+
+	tmpl := bot.DB.Config.Get("open_application_message").ToString()
+
+	_, err = s.SendMessageComplex(ch, api.SendMessageData{
+		Content: m.Mention(),
+		Embeds: []discord.Embed{{
+			Title: "Started application for " + name,
+			Thumbnail: &discord.EmbedThumbnail{
+				URL: bot.Router.Bot.AvatarURL() + "?size=512",
+			},
+			Color:       bot.Colour,
+			Description: strings.ReplaceAll(tmpl, "{guild}", g.Name),
+		}},
+		AllowedMentions: &api.AllowedMentions{
+			Users: []discord.UserID{m.User.ID},
+		},
+	})
+	return err
+}
+
+// finishThread renames and locks a finished application thread, optionally removing the applicant from it.
+// Threads can't be moved between categories, so this is used instead of moving the channel.
+func (bot *Bot) finishThread(s *state.State, app *db.Application, name, reason string, removeUser bool) error {
+	if removeUser {
+		err := s.RemoveThreadMember(app.ChannelID, app.UserID)
+		if err != nil {
+			common.Log.Errorf("Error removing %v from application thread: %v", app.UserID, err)
+		}
+	}
+
+	return s.ModifyChannel(app.ChannelID, api.ModifyChannelData{
+		Name:           name,
+		Locked:         option.True,
+		AuditLogReason: api.AuditLogReason(reason),
+	})
+}
+
+// isThread returns true if the given channel is a thread, rather than a normal application channel.
+func isThread(ch *discord.Channel) bool {
+	return ch.Type == discord.GuildPrivateThread || ch.Type == discord.GuildPublicThread
+}
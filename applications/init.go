@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
@@ -29,24 +28,18 @@ func (bot *Bot) createInterview(ctx *bcr.ButtonContext) (err error) {
 		return err
 	}
 
-	// check application
-	existing, err := bot.DB.UserApplication(ctx.User.ID)
-	if err == nil {
-		ch, err := ctx.State.Channel(existing.ChannelID)
-		if err == nil {
-			return ctx.ReplyEphemeral(fmt.Sprintf("You already have an open application, in %v!", ch.Mention()))
-		}
+	if ok, err := bot.checkCanApply(ctx.Context); !ok {
+		return err
+	}
 
-		// no channel, app should've been closed
-		err = bot.DB.CloseApplication(existing.ID)
-		if err != nil {
-			bot.SendError("Error closing existing app: %v", err)
-		}
+	tracks, err := bot.DB.ApplicationTracks()
+	if err != nil {
+		bot.SendError("Error getting app tracks: %v", err)
+		return ctx.ReplyEphemeral("There was an unknown error fetching application tracks!")
 	}
 
-	if err != nil && err != pgx.ErrNoRows {
-		bot.SendError("Unknown error fetching app: %v", err)
-		return ctx.ReplyEphemeral("There was an unknown error fetching an existing app!")
+	if bot.chooseTrackFirst(tracks) {
+		return bot.sendTrackPrompt(ctx, tracks)
 	}
 
 	ch, err := bot.newApplicationChannel(*ctx.Member)
@@ -67,20 +60,49 @@ func (bot *Bot) createInterview(ctx *bcr.ButtonContext) (err error) {
 		return ctx.ReplyEphemeral("I couldn't send the initial message!")
 	}
 
-	eventID, err := bot.Scheduler.Add(
-		time.Now().Add(24*time.Hour), &timeout{ChannelID: ch.ID, UserID: ctx.User.ID},
-	)
-	if err == nil {
-		if err := bot.DB.SetEventID(app.ID, eventID); err != nil {
-			common.Log.Errorf("error setting event id for app %v: %v", app.ID, err)
-		}
-	} else {
+	bot.announceRiskFlags(*ctx.Member, ch.ID)
+
+	_, err = scheduleTimeout(bot.Bot, &app, timeoutReminder)
+	if err != nil {
 		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
 	}
 
 	return ctx.ReplyEphemeral("Application opened in " + ch.Mention() + "!")
 }
 
+// checkCanApply checks if the user can open a new application, and replies to the interaction if not.
+func (bot *Bot) checkCanApply(ctx *bcr.Context) (ok bool, err error) {
+	existing, err := bot.DB.UserApplication(ctx.User.ID)
+	if err == nil {
+		ch, err := ctx.State.Channel(existing.ChannelID)
+		if err == nil {
+			return false, ctx.ReplyEphemeral(fmt.Sprintf("You already have an open application, in %v!", ch.Mention()))
+		}
+
+		// no channel, app should've been closed
+		err = bot.DB.CloseApplication(existing.ID)
+		if err != nil {
+			bot.SendError("Error closing existing app: %v", err)
+		}
+	}
+
+	if err != nil && err != pgx.ErrNoRows {
+		bot.SendError("Unknown error fetching app: %v", err)
+		return false, ctx.ReplyEphemeral("There was an unknown error fetching an existing app!")
+	}
+
+	cooldown, err := bot.DB.AppCooldown(ctx.User.ID)
+	if err != nil {
+		bot.SendError("Error fetching app cooldown for %v: %v", ctx.User.ID, err)
+		return false, ctx.ReplyEphemeral("There was an unknown error checking if you can apply!")
+	}
+	if cooldown != nil {
+		return false, ctx.ReplyEphemeral(cooldownMessage(*cooldown))
+	}
+
+	return true, nil
+}
+
 func (bot *Bot) chooseAppTrack(ctx *bcr.ButtonContext) (err error) {
 	return bot.chooseAppTrackInner(ctx, false)
 }
@@ -187,42 +209,7 @@ func (bot *Bot) chooseAppTrackInner(ctx *bcr.ButtonContext, isRestart bool) (err
 		common.Log.Errorf("Error responding to interaction: %v", err)
 	}
 
-	err = bot.DB.SetTrack(app.ID, track.ID)
-	if err != nil {
-		bot.SendError("Error setting application track in %v: %v", app.ChannelID.Mention(), err)
-		_, err = ctx.State.SendMessage(app.ChannelID, "Something went wrong! Please ask a mod for assistance.")
-		return
-	}
-
-	_, err = ctx.State.SendMessage(app.ChannelID, fmt.Sprintf("Starting **%v** application!\nIf you picked the wrong type, or want to restart your application for any other reason, please press the \"restart\" button above!", track.Name))
-	if err != nil {
-		bot.SendError("error sending message: %v", err)
-	}
-
-	qs, err := bot.DB.Questions(track.ID)
-	if err != nil {
-		bot.SendError("Error getting questions: %v", err)
-		return
-	}
-
-	if len(qs) == 0 {
-		bot.SendError("No questions for track ID %v, can't start application!", track.ID)
-		return
-	}
-
-	err = bot.sendInterviewMessage(app, qs[0].Question)
-	if err != nil {
-		common.Log.Errorf("Error sending message in app %v: %v", app.ChannelID, err)
-		return
-	}
-
-	// set question index to 1 (first user message will start question loop)
-	err = bot.DB.SetQuestionIndex(app.ID, 1)
-	if err != nil {
-		return errors.Wrap(err, "setting question index")
-	}
-
-	return
+	return bot.startTrack(ctx.State, app, track, true)
 }
 
 func (bot *Bot) restartAppInteraction(ctx *bcr.ButtonContext) error {
@@ -35,6 +35,10 @@ func (bot *Bot) guildMemberRemove(ev *gateway.GuildMemberRemoveEvent) {
 		return
 	}
 
+	if err := bot.DB.SetMemberLeft(ev.User.ID); err != nil {
+		common.Log.Errorf("Error saving leave time for %v: %v", ev.User.ID, err)
+	}
+
 	app, err := bot.DB.UserApplication(ev.User.ID)
 	if err != nil {
 		// no app
@@ -58,25 +62,32 @@ func (bot *Bot) guildMemberRemove(ev *gateway.GuildMemberRemoveEvent) {
 		common.Log.Errorf("Error sending message: %v", err)
 	}
 
-	newCat := discord.ChannelID(bot.DB.Config.Get("finished_application_category").ToSnowflake())
-	if !newCat.IsValid() {
-		newCat = discord.ChannelID(bot.DB.Config.Get("application_category").ToSnowflake())
-	}
+	if ch, err := s.Channel(app.ChannelID); err == nil && isThread(ch) {
+		err = bot.finishThread(s, app, "📤-app-"+unidecode.Unidecode(ev.User.Username), "User left server before application was completed", false)
+		if err != nil {
+			common.Log.Errorf("Error updating thread title: %v", err)
+		}
+	} else {
+		newCat := discord.ChannelID(bot.DB.Config.Get("finished_application_category").ToSnowflake())
+		if !newCat.IsValid() {
+			newCat = discord.ChannelID(bot.DB.Config.Get("application_category").ToSnowflake())
+		}
 
-	var overwrites *[]discord.Overwrite
-	cat, err := s.Channel(newCat)
-	if err == nil {
-		overwrites = &cat.Overwrites
-	}
+		var overwrites *[]discord.Overwrite
+		cat, err := s.Channel(newCat)
+		if err == nil {
+			overwrites = &cat.Overwrites
+		}
 
-	err = s.ModifyChannel(app.ChannelID, api.ModifyChannelData{
-		CategoryID:     newCat,
-		Overwrites:     overwrites,
-		Name:           "📤-app-" + unidecode.Unidecode(ev.User.Username),
-		AuditLogReason: "User left server before application was completed",
-	})
-	if err != nil {
-		common.Log.Errorf("Error updating channel title: %v", err)
+		err = s.ModifyChannel(app.ChannelID, api.ModifyChannelData{
+			CategoryID:     newCat,
+			Overwrites:     overwrites,
+			Name:           "📤-app-" + unidecode.Unidecode(ev.User.Username),
+			AuditLogReason: "User left server before application was completed",
+		})
+		if err != nil {
+			common.Log.Errorf("Error updating channel title: %v", err)
+		}
 	}
 
 	if app.ScheduledEventID != nil {
@@ -86,7 +97,7 @@ func (bot *Bot) guildMemberRemove(ev *gateway.GuildMemberRemoveEvent) {
 		}
 	}
 
-	_, err = bot.createTranscript(s, app)
+	_, err = createTranscript(bot.Bot, s, app)
 	if common.IsOodlesError(err) {
 		_, err = s.SendMessage(app.ChannelID, fmt.Sprintf("❌ %v", err))
 		if err != nil {
@@ -121,6 +121,14 @@ func (bot *Bot) appEmbed(ctx *bcr.Context, m map[discord.UserID]*discord.User, i
 		Inline: true,
 	})
 
+	if app.TimedOut {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:   "Timed out",
+			Value:  "true",
+			Inline: true,
+		})
+	}
+
 	if app.Verified != nil {
 		e.Fields = append(e.Fields, discord.EmbedField{
 			Name:   "Verified",
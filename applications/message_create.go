@@ -30,8 +30,19 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 	if m.Author.Bot && m.Author.ID != bot.Router.Bot.ID {
 		return
 	}
+	var modal bool
+	if app.TrackID != nil {
+		if track, err := bot.DB.ApplicationTrack(*app.TrackID); err == nil {
+			modal = bot.trackMode(*track) == modeModal
+		}
+	}
+
 	// save the message
-	bot.saveMessage(app, m)
+	// in modal applications, the bot's posts are the applicant's answers,
+	// which are saved in full when the form is submitted
+	if !modal || m.Author.ID != bot.Router.Bot.ID {
+		bot.saveMessage(app, m)
+	}
 
 	// the rest only triggers for the application user
 	if m.Author.ID != app.UserID {
@@ -42,6 +53,11 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 		return
 	}
 
+	// modal applications are answered through forms, so don't treat messages as answers
+	if modal {
+		return
+	}
+
 	qs, err := bot.DB.Questions(*app.TrackID)
 	if err != nil {
 		bot.SendError("Error fetching questions for app %v/track %v: %v", app.ID, *app.TrackID, err)
@@ -56,7 +72,7 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 			}
 		}
 
-		err = bot.completeApp(app, m)
+		err = bot.completeApp(app, m.Author)
 		if err != nil {
 			bot.SendError("Error completing app %v: %v", app.ID, err)
 		}
@@ -88,12 +104,7 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 		bot.SendError("Error incrementing question index for app %v: %v", app.ID, err)
 	}
 
-	if app.ScheduledEventID != nil {
-		err = bot.Scheduler.Reschedule(*app.ScheduledEventID, 24*time.Hour)
-		if err != nil {
-			bot.SendError("Error removing schedled timeout message for app %v: %v", app.ID, err)
-		}
-	}
+	bot.resetTimeout(app)
 }
 
 func (bot *Bot) saveMessage(app *db.Application, m *gateway.MessageCreateEvent) {
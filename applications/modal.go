@@ -0,0 +1,303 @@
+package applications
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// Discord allows at most 5 text inputs per modal
+const modalPageSize = 5
+
+// modalPage returns the questions on the given page, and the index of the first question after it.
+func modalPage(qs []db.AppQuestion, page int) ([]db.AppQuestion, int) {
+	start := page * modalPageSize
+	if start >= len(qs) {
+		return nil, len(qs)
+	}
+
+	end := start + modalPageSize
+	if end > len(qs) {
+		end = len(qs)
+	}
+	return qs[start:end], end
+}
+
+func truncate(s string, max int) string {
+	if len([]rune(s)) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-3]) + "..."
+}
+
+// parseModalID parses a custom ID in the form prefix:track:page.
+func parseModalID(id discord.ComponentID, prefix string) (trackID int64, page int, err error) {
+	parts := strings.Split(strings.TrimPrefix(string(id), prefix), ":")
+	if len(parts) != 2 {
+		return 0, 0, common.Error("invalid custom ID %q", id)
+	}
+
+	trackID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	page, err = strconv.Atoi(parts[1])
+	return trackID, page, err
+}
+
+// sendAppModal responds to the interaction with the given page of the track's questions.
+func (bot *Bot) sendAppModal(ctx *bcr.Context, track *db.ApplicationTrack, qs []db.AppQuestion, page int) error {
+	pageQs, _ := modalPage(qs, page)
+
+	components := make([]discord.InteractiveComponent, 0, len(pageQs))
+	for _, q := range pageQs {
+		style := discord.TextInputShortStyle
+		if q.LongAnswer {
+			style = discord.TextInputParagraphStyle
+		}
+
+		components = append(components, &discord.TextInputComponent{
+			CustomID:    discord.ComponentID("q:" + strconv.FormatInt(q.ID, 10)),
+			Style:       style,
+			Label:       truncate(q.Question, 45),
+			Required:    true,
+			Placeholder: option.NewNullableString(truncate(q.Question, 100)),
+		})
+	}
+
+	title := track.Name + " application"
+	if pages := (len(qs) + modalPageSize - 1) / modalPageSize; pages > 1 {
+		title = fmt.Sprintf("%v (%v/%v)", track.Name, page+1, pages)
+	}
+
+	return bcr.ModalResponse(
+		ctx.InteractionID, ctx.InteractionToken, ctx.State,
+		fmt.Sprintf("app-modal:%v:%v", track.ID, page), truncate(title, 45),
+		components...,
+	)
+}
+
+// nextAppModal opens the next page of questions for a modal application.
+func (bot *Bot) nextAppModal(ctx *bcr.ButtonContext) (err error) {
+	trackID, page, err := parseModalID(ctx.CustomID, "app-modal-next:")
+	if err != nil {
+		return err
+	}
+
+	app, err := bot.DB.UserApplication(ctx.User.ID)
+	if err != nil || app.TrackID == nil || *app.TrackID != trackID || app.Completed {
+		return ctx.ReplyEphemeral("This form is out of date! If you think this is a mistake, please ping staff for assistance.")
+	}
+
+	track, err := bot.DB.ApplicationTrack(trackID)
+	if err != nil {
+		return ctx.ReplyEphemeral("That application track doesn't exist anymore! Please ping staff for assistance.")
+	}
+
+	qs, err := bot.DB.Questions(track.ID)
+	if err != nil {
+		bot.SendError("Error getting questions: %v", err)
+		return ctx.ReplyEphemeral("Internal error occurred! Please ping staff for assistance.")
+	}
+
+	if app.Question != page*modalPageSize || page*modalPageSize >= len(qs) {
+		return ctx.ReplyEphemeral("This form is out of date! If you think this is a mistake, please ping staff for assistance.")
+	}
+
+	return bot.sendAppModal(ctx.Context, track, qs, page)
+}
+
+// submitAppModal saves the answers from a modal, and either prompts for the next page or completes the application.
+func (bot *Bot) submitAppModal(ctx *bcr.ModalContext) (err error) {
+	if ctx.Member == nil {
+		return ctx.ReplyEphemeral("This event didn't have a member associated with it! This is a bug, please report it to the developer (such as by DMing me!)")
+	}
+
+	trackID, page, err := parseModalID(ctx.CustomID, "app-modal:")
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.DeferEphemeral(); err != nil {
+		return err
+	}
+
+	track, err := bot.DB.ApplicationTrack(trackID)
+	if err != nil {
+		return ctx.ReplyEphemeral("That application track doesn't exist anymore! Please ping staff for assistance.")
+	}
+
+	qs, err := bot.DB.Questions(track.ID)
+	if err != nil {
+		bot.SendError("Error getting questions: %v", err)
+		return ctx.ReplyEphemeral("Internal error occurred! Please ping staff for assistance.")
+	}
+
+	var app *db.Application
+	if page == 0 {
+		if ok, err := bot.checkCanApply(ctx.Context); !ok {
+			return err
+		}
+
+		app, err = bot.openModalApplication(ctx, track)
+		if err != nil {
+			bot.SendError("Error opening modal application for %v: %v", ctx.User.Mention(), err)
+			return ctx.ReplyEphemeral("I couldn't open your application! Please ping staff for assistance.")
+		}
+	} else {
+		app, err = bot.DB.UserApplication(ctx.User.ID)
+		if err != nil || app.TrackID == nil || *app.TrackID != track.ID || app.Question != page*modalPageSize {
+			return ctx.ReplyEphemeral("This form is out of date! If you think this is a mistake, please ping staff for assistance.")
+		}
+	}
+
+	pageQs, next := modalPage(qs, page)
+	for _, q := range pageQs {
+		var answer string
+		for _, input := range ctx.TextInputs {
+			if input.CustomID == discord.ComponentID("q:"+strconv.FormatInt(q.ID, 10)) {
+				answer = input.Value.Val
+			}
+		}
+
+		err = bot.saveModalAnswer(ctx, app, q, answer)
+		if err != nil {
+			bot.SendError("Error saving answer in %v: %v", app.ChannelID.Mention(), err)
+			return ctx.ReplyEphemeral("I couldn't save your answers! Please ping staff for assistance.")
+		}
+	}
+
+	err = bot.DB.SetQuestionIndex(app.ID, next)
+	if err != nil {
+		bot.SendError("Error setting question index for app %v: %v", app.ID, err)
+	}
+
+	// refetch the app to get its current scheduled event
+	chID := app.ChannelID
+	app, err = bot.DB.ChannelApplication(chID)
+	if err != nil {
+		bot.SendError("Error refetching app in %v: %v", chID.Mention(), err)
+		return ctx.ReplyEphemeral("Internal error occurred! Please ping staff for assistance.")
+	}
+
+	if next < len(qs) {
+		bot.resetTimeout(app)
+
+		return ctx.ReplyComplex(api.InteractionResponseData{
+			Content: option.NewNullableString(fmt.Sprintf("Your answers were saved! Press the button below to continue with part %v of your application.", page+2)),
+			Components: discord.ComponentsPtr(&discord.ButtonComponent{
+				Label:    "Continue",
+				Style:    discord.PrimaryButtonStyle(),
+				CustomID: discord.ComponentID(fmt.Sprintf("app-modal-next:%v:%v", track.ID, page+1)),
+			}),
+			Flags: api.EphemeralResponse,
+		})
+	}
+
+	if app.ScheduledEventID != nil {
+		err = bot.Scheduler.Remove(*app.ScheduledEventID)
+		if err != nil {
+			bot.SendError("Error removing schedled timeout message for app %v: %v", app.ID, err)
+		}
+	}
+
+	err = bot.completeApp(app, ctx.User)
+	if err != nil {
+		bot.SendError("Error completing app %v: %v", app.ID, err)
+	}
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Your application was submitted! You can follow up with staff in %v.", app.ChannelID.Mention()))
+}
+
+// openModalApplication creates the thread and database entry for a new modal application.
+func (bot *Bot) openModalApplication(ctx *bcr.ModalContext, track *db.ApplicationTrack) (*db.Application, error) {
+	ch, err := bot.newApplicationThread(*ctx.Member)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := bot.DB.CreateApplication(ctx.User.ID, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bot.sendOpenMessage(ch.ID, *ctx.Member)
+	if err != nil {
+		bot.SendError("Error sending initial message: %v", err)
+	}
+
+	err = bot.DB.SetTrack(app.ID, track.ID)
+	if err != nil {
+		return nil, err
+	}
+	app.TrackID = &track.ID
+
+	bot.sendIntroMessage(ctx.State, &app, track)
+	bot.announceRiskFlags(*ctx.Member, ch.ID)
+
+	_, err = scheduleTimeout(bot.Bot, &app, timeoutReminder)
+	if err != nil {
+		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
+	}
+
+	return &app, nil
+}
+
+// splitMessage splits s into chunks of at most max characters, breaking at newlines where possible.
+func splitMessage(s string, max int) (chunks []string) {
+	r := []rune(s)
+	for len(r) > max {
+		end := max
+		for i := max; i > max/2; i-- {
+			if r[i] == '\n' {
+				end = i
+				break
+			}
+		}
+
+		chunks = append(chunks, string(r[:end]))
+		r = r[end:]
+		if len(r) > 0 && r[0] == '\n' {
+			r = r[1:]
+		}
+	}
+	return append(chunks, string(r))
+}
+
+// saveModalAnswer posts a question and its answer in the application thread, and saves it as the applicant's response.
+// Long answers are split over multiple messages, but saved in full.
+func (bot *Bot) saveModalAnswer(ctx *bcr.ModalContext, app *db.Application, q db.AppQuestion, answer string) error {
+	content := fmt.Sprintf("**%v. %v**\n%v", q.Index, q.Question, answer)
+
+	var first discord.MessageID
+	for _, chunk := range splitMessage(content, 2000) {
+		msg, err := ctx.State.SendMessageComplex(app.ChannelID, api.SendMessageData{
+			Content: chunk,
+			AllowedMentions: &api.AllowedMentions{
+				Parse: []api.AllowedMentionType{},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if !first.IsValid() {
+			first = msg.ID
+		}
+	}
+
+	return bot.DB.SetResponse(app.ID, db.AppResponse{
+		MessageID:     first,
+		UserID:        ctx.User.ID,
+		Username:      ctx.User.Username,
+		Discriminator: ctx.User.Discriminator,
+		Content:       content,
+	})
+}
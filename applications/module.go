@@ -29,6 +29,14 @@ func Init(bot *bot.Bot) {
 	b.Interactions.Button("restart-app").Exec(b.restartAppInteraction)
 	b.Interactions.Button("app-track:*").Exec(b.chooseAppTrack)
 	b.Interactions.Button("app-track-restart:*").Exec(b.chooseAppTrackAlreadyRestarted)
+	b.Interactions.Button("app-open:*").Exec(b.openTrack)
+	b.Interactions.Button("app-modal-next:*").Exec(b.nextAppModal)
+	b.Interactions.Modal("app-modal:*").Exec(b.submitAppModal)
+	b.Interactions.Button(common.OpenAppeal).Exec(b.openAppeal)
+	b.Interactions.Modal("appeal-modal").Exec(b.submitAppeal)
+	b.Interactions.Button("appeal-accept:*").Exec(b.acceptAppeal)
+	b.Interactions.Button("appeal-reject:*").Exec(b.rejectAppeal)
+	b.Interactions.Modal("appeal-reject-modal:*").Exec(b.submitRejectAppeal)
 
 	b.Router.AddHandler(b.messageCreate)
 	b.Router.AddHandler(b.guildMemberAdd)
@@ -67,11 +75,30 @@ func Init(bot *bot.Bot) {
 	b.Router.AddCommand(&bcr.Command{
 		Name:              "deny",
 		Summary:           "Deny the current application",
-		Usage:             "[reason...]",
+		Usage:             "[channel] [--permanent] [reason...]",
 		CustomPermissions: b.Checker,
 		Command:           b.deny,
 	})
 
+	b.Router.AddCommand(&bcr.Command{
+		Name:              "allowreapply",
+		Summary:           "Remove a user's reapplication cooldown",
+		Usage:             "<user>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           b.allowReapply,
+	})
+
+	b.Router.AddCommand(&bcr.Command{
+		Name:              "appeal",
+		Summary:           "Appeal your application denial or ban",
+		Description:       "Appeal your application denial or ban. This command only works in DMs.",
+		Usage:             "<reason...>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           b.appeal,
+	})
+
 	b.Router.AddCommand(&bcr.Command{
 		Name:              "open",
 		Aliases:           []string{"create"},
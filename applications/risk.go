@@ -0,0 +1,101 @@
+package applications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// riskFlags returns a list of reasons the given member's application might need a closer look.
+func (bot *Bot) riskFlags(m discord.Member) (flags []string) {
+	if hours := bot.DB.Config.Get("risk_account_age").ToInt(); hours > 0 {
+		created := m.User.ID.Time()
+		if time.Since(created) < time.Duration(hours)*time.Hour {
+			flags = append(flags, fmt.Sprintf("Account is new (created <t:%v:R>)", created.Unix()))
+		}
+	}
+
+	if bot.DB.Config.Get("risk_no_avatar").ToBool() && m.User.Avatar == "" {
+		flags = append(flags, "No avatar")
+	}
+
+	if min := bot.DB.Config.Get("risk_denied_applications").ToInt(); min > 0 {
+		apps, err := bot.DB.AllUserApplications(m.User.ID)
+		if err != nil {
+			common.Log.Errorf("Error getting applications for %v: %v", m.User.ID, err)
+		}
+
+		var count int64
+		for _, app := range apps {
+			if app.Verified != nil && !*app.Verified {
+				count++
+			}
+		}
+		if count >= min {
+			flags = append(flags, fmt.Sprintf("Denied %v time(s) before", count))
+		}
+	}
+
+	if min := bot.DB.Config.Get("risk_mod_logs").ToInt(); min > 0 {
+		entries, err := bot.DB.ModLogFor(bot.DB.BotConfig.GuildID, m.User.ID)
+		if err != nil {
+			common.Log.Errorf("Error getting mod logs for %v: %v", m.User.ID, err)
+		}
+
+		if int64(len(entries)) >= min {
+			flags = append(flags, fmt.Sprintf("%v prior mod log entries", len(entries)))
+		}
+	}
+
+	if hours := bot.DB.Config.Get("risk_rejoin_time").ToInt(); hours > 0 && m.Joined.IsValid() {
+		left, err := bot.DB.LastLeave(m.User.ID)
+		if err != nil {
+			common.Log.Errorf("Error getting last leave for %v: %v", m.User.ID, err)
+		}
+
+		if left != nil && left.Before(m.Joined.Time()) && m.Joined.Time().Sub(*left) < time.Duration(hours)*time.Hour {
+			flags = append(flags, fmt.Sprintf("Rejoined %v after leaving", bcr.HumanizeDuration(bcr.DurationPrecisionMinutes, m.Joined.Time().Sub(*left))))
+		}
+	}
+
+	return flags
+}
+
+// announceRiskFlags posts the member's risk flags in the discussion channel, if they have any.
+func (bot *Bot) announceRiskFlags(m discord.Member, chID discord.ChannelID) {
+	discussion := bot.DB.Config.Get("discussion_channel").ToChannelID()
+	if !discussion.IsValid() {
+		return
+	}
+
+	flags := bot.riskFlags(m)
+	if len(flags) == 0 {
+		return
+	}
+
+	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
+
+	_, err := s.SendMessageComplex(discussion, api.SendMessageData{
+		Content: fmt.Sprintf("%v (%v) opened an application.%v", m.Mention(), chID.Mention(), riskFlagString(flags)),
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		common.Log.Errorf("Error sending message: %v", err)
+	}
+}
+
+// riskFlagString formats the given flags for use in a message, or returns an empty string if there are none.
+func riskFlagString(flags []string) string {
+	if len(flags) == 0 {
+		return ""
+	}
+
+	return "\n\n⚠️ **Risk flags:**\n- " + strings.Join(flags, "\n- ")
+}
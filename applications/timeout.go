@@ -3,31 +3,219 @@ package applications
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/starshine-sys/oodles/bot"
 	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// Stages of the timeout escalation ladder
+const (
+	timeoutReminder = iota
+	timeoutWarning
+	timeoutClose = db.TimeoutCloseStage
 )
 
 type timeout struct {
 	ChannelID discord.ChannelID `json:"channel_id"`
 	UserID    discord.UserID    `json:"user_id"`
+	Stage     int               `json:"stage"`
+}
+
+// timeoutDelay returns how long after the previous stage the given stage fires.
+// A zero duration means the stage is disabled.
+func timeoutDelay(b *bot.Bot, stage int) time.Duration {
+	var key string
+	switch stage {
+	case timeoutReminder:
+		key = "app_timeout_time"
+	case timeoutWarning:
+		key = "app_warning_time"
+	case timeoutClose:
+		key = "app_auto_close_time"
+	default:
+		return 0
+	}
+
+	hours := b.DB.Config.Get(key).ToInt()
+	if hours <= 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// scheduleTimeout schedules the first enabled stage at or after the given stage, and saves its ID.
+// Returns false if there are no more stages to schedule, or if timeouts are disabled entirely.
+func scheduleTimeout(b *bot.Bot, app *db.Application, stage int) (bool, error) {
+	// app_timeout_time is the base timeout, the later stages only apply on top of it
+	if timeoutDelay(b, timeoutReminder) == 0 {
+		return false, nil
+	}
+
+	for ; stage <= timeoutClose; stage++ {
+		delay := timeoutDelay(b, stage)
+		if delay == 0 {
+			continue
+		}
+
+		eventID, err := b.Scheduler.Add(
+			time.Now().Add(delay), &timeout{ChannelID: app.ChannelID, UserID: app.UserID, Stage: stage},
+		)
+		if err != nil {
+			return false, err
+		}
+		return true, b.DB.SetEventID(app.ID, eventID)
+	}
+	return false, nil
+}
+
+// resetTimeout cancels the application's pending timeout event and starts the ladder over.
+func (bot *Bot) resetTimeout(app *db.Application) {
+	if app.ScheduledEventID != nil {
+		err := bot.Scheduler.Remove(*app.ScheduledEventID)
+		if err != nil {
+			bot.SendError("Error removing scheduled timeout message for app %v: %v", app.ID, err)
+		}
+	}
+
+	_, err := scheduleTimeout(bot.Bot, app, timeoutReminder)
+	if err != nil {
+		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
+	}
 }
 
 func (dat *timeout) Execute(ctx context.Context, id int64, bot *bot.Bot) error {
-	common.Log.Infof("app in channel %v timed out, sending timeout message", dat.ChannelID)
+	common.Log.Infof("app in channel %v timed out, running stage %v", dat.ChannelID, dat.Stage)
+
+	app, err := bot.DB.ChannelApplication(dat.ChannelID)
+	if err != nil {
+		// application was already closed
+		return nil
+	}
+
+	if app.Completed || app.Verified != nil || app.Closed {
+		return nil
+	}
 
 	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
 
-	chID := bot.DB.Config.Get("discussion_channel").ToChannelID()
-	if !chID.IsValid() {
+	switch dat.Stage {
+	case timeoutReminder:
+		err = dat.ping(s, bot.DB.Config.Get("app_reminder_message").ToString())
+		if err != nil {
+			common.Log.Errorf("Error sending reminder in %v: %v", dat.ChannelID, err)
+		}
+
+		chID := bot.DB.Config.Get("discussion_channel").ToChannelID()
+		if chID.IsValid() {
+			_, err = s.SendMessage(chID,
+				fmt.Sprintf("%v (%v)'s application timed out!", dat.UserID.Mention(), dat.ChannelID.Mention()))
+			if err != nil {
+				common.Log.Errorf("Error sending message: %v", err)
+			}
+		}
+	case timeoutWarning:
+		err = dat.ping(s, bot.DB.Config.Get("app_warning_message").ToString())
+		if err != nil {
+			common.Log.Errorf("Error sending warning in %v: %v", dat.ChannelID, err)
+		}
+	case timeoutClose:
+		return dat.autoClose(ctx, bot, app)
+	}
+
+	_, err = scheduleTimeout(bot, app, dat.Stage+1)
+	if err != nil {
+		common.Log.Errorf("error adding timeout event for app %v: %v", app.ID, err)
+	}
+	return nil
+}
+
+// ping sends the given message in the application channel, pinging the applicant.
+func (dat *timeout) ping(s *state.State, tmpl string) error {
+	if tmpl == "" {
 		return nil
 	}
 
-	_, err := s.SendMessage(chID,
-		fmt.Sprintf("%v (%v)'s application timed out!", dat.UserID.Mention(), dat.ChannelID.Mention()))
+	_, err := s.SendMessageComplex(dat.ChannelID, api.SendMessageData{
+		Content: strings.NewReplacer("{mention}", dat.UserID.Mention()).Replace(tmpl),
+		AllowedMentions: &api.AllowedMentions{
+			Users: []discord.UserID{dat.UserID},
+		},
+	})
 	return err
 }
 
+func (dat *timeout) setCooldown(bot *bot.Bot, app *db.Application) {
+	hours := bot.DB.Config.Get("timeout_cooldown").ToInt()
+	if hours <= 0 {
+		return
+	}
+
+	expires := time.Now().UTC().Add(time.Duration(hours) * time.Hour)
+	err := bot.DB.SetAppCooldown(db.AppCooldown{
+		UserID:        dat.UserID,
+		Expires:       &expires,
+		Reason:        "Application timed out",
+		ApplicationID: &app.ID,
+	})
+	if err != nil {
+		common.Log.Errorf("Error setting timeout cooldown for %v: %v", dat.UserID, err)
+	}
+}
+
+// autoClose saves a transcript, optionally kicks the applicant, and deletes the application channel.
+func (dat *timeout) autoClose(ctx context.Context, b *bot.Bot, app *db.Application) error {
+	s, _ := b.Router.StateFromGuildID(b.DB.BotConfig.GuildID)
+
+	kick := b.DB.Config.Get("app_auto_close_action").ToString() == "kick"
+
+	// timed out applications aren't denials, so they don't count against the user
+	app.TimedOut = true
+	err := b.DB.SetTimedOut(app.ID)
+	if err != nil {
+		b.SendError("Error setting application %v to timed out: %v", app.ID, err)
+	}
+
+	_, err = createTranscript(b, s, app)
+	if err != nil {
+		b.SendError("Error saving transcript for timed out application %v: %v", app.ID, err)
+		// don't delete the channel if we couldn't save a transcript
+		return nil
+	}
+
+	// this closes the application before kicking, so the leave handler doesn't touch it
+	err = (&scheduledClose{ChannelID: app.ChannelID}).Execute(ctx, 0, b)
+	if err != nil {
+		return err
+	}
+
+	// the application is only closed now, so this is the earliest the cooldown can start
+	dat.setCooldown(b, app)
+
+	action := "closed"
+	if kick {
+		err = s.Kick(b.DB.BotConfig.GuildID, app.UserID, "Application timed out")
+		if err != nil {
+			b.SendError("Error kicking %v after their application timed out: %v", app.UserID.Mention(), err)
+		} else {
+			action = "closed and the user was kicked"
+		}
+	}
+
+	chID := b.DB.Config.Get("discussion_channel").ToChannelID()
+	if chID.IsValid() {
+		_, err = s.SendMessage(chID,
+			fmt.Sprintf("%v's application was automatically %v after timing out.", dat.UserID.Mention(), action))
+		if err != nil {
+			common.Log.Errorf("Error sending message: %v", err)
+		}
+	}
+	return nil
+}
+
 func (dat *timeout) Offset() time.Duration { return time.Minute }
@@ -10,13 +10,18 @@ import (
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/diamondburned/arikawa/v3/utils/sendpart"
 	"github.com/starshine-sys/dischtml"
+	"github.com/starshine-sys/oodles/bot"
 	"github.com/starshine-sys/oodles/common"
 	"github.com/starshine-sys/oodles/db"
 )
 
-func (bot *Bot) createTranscript(s *state.State, app *db.Application) (*discord.Message, error) {
+// createTranscript saves a transcript of the application channel in the transcript channel.
+// It's a plain function so scheduled events, which only get a *bot.Bot, can use it too.
+func createTranscript(bot *bot.Bot, s *state.State, app *db.Application) (*discord.Message, error) {
 	outcome := "User left the server"
-	if app.Verified != nil {
+	if app.TimedOut {
+		outcome = "Application timed out"
+	} else if app.Verified != nil {
 		if *app.Verified {
 			outcome = "User was verified"
 		} else {
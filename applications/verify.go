@@ -74,6 +74,15 @@ func (bot *Bot) verify(ctx *bcr.Context) (err error) {
 		}
 	}
 
+	if app.TrackID != nil {
+		track, err := bot.DB.ApplicationTrack(*app.TrackID)
+		if err == nil {
+			toAdd = append(toAdd, track.VerifyRoleIDs()...)
+		} else {
+			bot.SendError("Error getting track %v for app %v: %v", *app.TrackID, app.ID, err)
+		}
+	}
+
 	setRoles := m.RoleIDs
 	for _, add := range toAdd {
 		hasRole := false
@@ -126,7 +135,7 @@ func (bot *Bot) verify(ctx *bcr.Context) (err error) {
 	}
 
 	// save transcript
-	_, err = bot.createTranscript(ctx.State, app)
+	_, err = createTranscript(bot.Bot, ctx.State, app)
 	if err != nil {
 		return ctx.SendfX("There was an error saving a transcript:\n> %v", err)
 	}
@@ -143,7 +152,18 @@ func (bot *Bot) verify(ctx *bcr.Context) (err error) {
 		common.Log.Errorf("error adding scheduled close for app %v: %v", app.ID, err)
 	}
 
+	if app.ScheduledEventID != nil {
+		err = bot.Scheduler.Remove(*app.ScheduledEventID)
+		if err != nil {
+			bot.SendError("Error removing schedled timeout message for app %v: %v", app.ID, err)
+		}
+	}
+
 	// edit channel
+	if isThread(ctx.Channel) {
+		return bot.finishThread(ctx.State, app, "🔒-app-"+unidecode.Unidecode(m.User.Username), "Application completed, user verified", true)
+	}
+
 	newCat := discord.ChannelID(bot.DB.Config.Get("finished_application_category").ToSnowflake())
 	if !newCat.IsValid() {
 		newCat = ctx.Channel.ParentID
@@ -154,13 +174,6 @@ func (bot *Bot) verify(ctx *bcr.Context) (err error) {
 		return ctx.SendfX("Couldn't get this channel's category.")
 	}
 
-	if app.ScheduledEventID != nil {
-		err = bot.Scheduler.Remove(*app.ScheduledEventID)
-		if err != nil {
-			bot.SendError("Error removing schedled timeout message for app %v: %v", app.ID, err)
-		}
-	}
-
 	return ctx.State.ModifyChannel(app.ChannelID, api.ModifyChannelData{
 		Name:           "🔒-app-" + unidecode.Unidecode(m.User.Username),
 		CategoryID:     newCat,
@@ -0,0 +1,187 @@
+package meta
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
+	botpkg "github.com/starshine-sys/oodles/bot"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
+)
+
+// how often the pinned queue dashboard is updated
+const queueUpdateInterval = 5 * time.Minute
+
+func (bot *Bot) appQueue(ctx *bcr.Context) (err error) {
+	lines, err := queueLines(bot.Bot)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(lines) == 0 {
+		return ctx.SendX("There are no open applications.")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator(fmt.Sprintf("Open applications (%v)", len(lines)), bot.Colour, lines, 10), 15*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) appQueuePin(ctx *bcr.Context) (err error) {
+	e, err := queueEmbed(bot.Bot)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	msg, err := ctx.State.SendEmbeds(ctx.Message.ChannelID, e)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	err = ctx.State.PinMessage(msg.ChannelID, msg.ID, "Pin application queue")
+	if err != nil {
+		_ = ctx.SendX("I couldn't pin the queue message, but it will still be updated.")
+	}
+
+	err = bot.DB.Config.Set("app_queue_channel", discord.Snowflake(msg.ChannelID))
+	if err == nil {
+		err = bot.DB.Config.Set("app_queue_message", discord.Snowflake(msg.ID))
+	}
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	err = bot.DB.SyncConfig()
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	// only ever keep one update event around
+	_, err = bot.DB.Exec(context.Background(), "delete from scheduled_events where event_type = 'meta.queueUpdate'")
+	if err != nil {
+		common.Log.Errorf("Error removing old queue update events: %v", err)
+	}
+
+	_, err = bot.Scheduler.Add(time.Now().Add(queueUpdateInterval), &queueUpdate{})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.State.DeleteMessage(ctx.Message.ChannelID, ctx.Message.ID, "")
+}
+
+// queueUpdate periodically updates the pinned application queue message.
+type queueUpdate struct{}
+
+func (dat *queueUpdate) Execute(ctx context.Context, id int64, bot *botpkg.Bot) error {
+	chID := bot.DB.Config.Get("app_queue_channel").ToChannelID()
+	msgID := discord.MessageID(bot.DB.Config.Get("app_queue_message").ToSnowflake())
+	if !chID.IsValid() || !msgID.IsValid() {
+		return nil
+	}
+
+	e, err := queueEmbed(bot)
+	if err != nil {
+		common.Log.Errorf("Error getting application queue: %v", err)
+		return botpkg.Reschedule
+	}
+
+	s, _ := bot.Router.StateFromGuildID(bot.DB.BotConfig.GuildID)
+
+	_, err = s.EditMessageComplex(chID, msgID, api.EditMessageData{
+		Embeds: &[]discord.Embed{e},
+	})
+	if err != nil {
+		// the message was most likely deleted, so stop updating it
+		common.Log.Errorf("Error updating application queue message: %v", err)
+		return nil
+	}
+
+	return botpkg.Reschedule
+}
+
+func (dat *queueUpdate) Offset() time.Duration { return queueUpdateInterval }
+
+func queueEmbed(bot *botpkg.Bot) (discord.Embed, error) {
+	lines, err := queueLines(bot)
+	if err != nil {
+		return discord.Embed{}, err
+	}
+
+	e := discord.Embed{
+		Title:     fmt.Sprintf("Open applications (%v)", len(lines)),
+		Color:     bot.Colour,
+		Footer:    &discord.EmbedFooter{Text: "Last updated"},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if len(lines) == 0 {
+		e.Description = "There are no open applications."
+		return e, nil
+	}
+
+	for i, l := range lines {
+		if len(e.Description)+len(l) > 4000 {
+			e.Description += fmt.Sprintf("...and %v more", len(lines)-i)
+			break
+		}
+		e.Description += l + "\n"
+	}
+	return e, nil
+}
+
+func queueLines(bot *botpkg.Bot) ([]string, error) {
+	apps, err := bot.DB.OpenApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(apps))
+	for _, app := range apps {
+		lines = append(lines, queueLine(app))
+	}
+	return lines, nil
+}
+
+func queueLine(app db.QueuedApplication) string {
+	track := "no track"
+	if app.TrackName != nil {
+		track = *app.TrackName
+	} else if app.TrackID != nil {
+		track = "unknown track"
+	}
+
+	var state string
+	switch {
+	case app.Verified != nil && *app.Verified:
+		state = "✅ verified"
+	case app.Verified != nil:
+		state = "❌ denied"
+	case app.Completed:
+		state = "📝 waiting for review"
+	case app.TrackID == nil:
+		state = "choosing track"
+	default:
+		state = fmt.Sprintf("question %v/%v", app.Question, app.QuestionCount)
+	}
+
+	lastActive := app.ID.Time()
+	if app.LastMessage != nil {
+		lastActive = app.LastMessage.Time()
+	}
+
+	s := fmt.Sprintf("%v in %v: **%v**, %v, idle %v",
+		app.UserID.Mention(), app.ChannelID.Mention(), track, state,
+		bcr.HumanizeDuration(bcr.DurationPrecisionMinutes, time.Since(lastActive)),
+	)
+
+	if app.CloseTime != nil {
+		s += fmt.Sprintf(", closing <t:%v:R>", app.CloseTime.Unix())
+	}
+	return s
+}
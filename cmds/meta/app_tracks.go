@@ -35,12 +35,116 @@ func (bot *Bot) listAppTracks(ctx *bcr.Context) (err error) {
 
 		for _, t := range tracks {
 			e.Description += fmt.Sprintf("%d. %s (%s)\n", t.ID, t.Name, t.Emoji())
+			if o := trackOverrides(t); len(o) > 0 {
+				e.Description += "> Overrides: " + strings.Join(o, ", ") + "\n"
+			}
 		}
 
 		return ctx.SendX("", e)
 	}
 }
 
+func trackOverrides(t db.ApplicationTrack) (o []string) {
+	if t.IntroMessage != nil {
+		o = append(o, "intro message")
+	}
+	if t.FinishedMessage != nil {
+		o = append(o, "finished message")
+	}
+	if t.CategoryID != nil {
+		o = append(o, "category "+t.CategoryID.Mention())
+	}
+	if len(t.VerifyRoles) > 0 {
+		o = append(o, fmt.Sprintf("%v verify role(s)", len(t.VerifyRoles)))
+	}
+	if len(t.ReviewerRoles) > 0 {
+		o = append(o, fmt.Sprintf("%v reviewer role(s)", len(t.ReviewerRoles)))
+	}
+	if t.Mode != nil {
+		o = append(o, *t.Mode+" mode")
+	}
+	return o
+}
+
+func (bot *Bot) setTrackOverride(ctx *bcr.Context) (err error) {
+	trackID, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil {
+		return ctx.SendfX("%v is not a valid number.", ctx.Args[0])
+	}
+
+	t, err := bot.DB.ApplicationTrack(trackID)
+	if err != nil {
+		return ctx.SendfX("Couldn't find a track with that ID.")
+	}
+
+	value := strings.TrimSpace(strings.Join(ctx.Args[2:], " "))
+
+	switch strings.ToLower(ctx.Args[1]) {
+	case "intro", "intro_message":
+		t.IntroMessage = nil
+		if value != "" {
+			t.IntroMessage = &value
+		}
+	case "finished", "finish", "finished_message":
+		t.FinishedMessage = nil
+		if value != "" {
+			t.FinishedMessage = &value
+		}
+	case "category":
+		t.CategoryID = nil
+		if value != "" {
+			ch, err := ctx.ParseChannel(value)
+			if err != nil || ch.Type != discord.GuildCategory {
+				return ctx.SendfX("``%v`` is not a category.", bcr.EscapeBackticks(value))
+			}
+			t.CategoryID = &ch.ID
+		}
+	case "roles", "verify_roles":
+		t.VerifyRoles, err = parseRoleList(ctx, ctx.Args[2:])
+		if err != nil {
+			return ctx.SendX(err.Error())
+		}
+	case "reviewers", "reviewer_roles":
+		t.ReviewerRoles, err = parseRoleList(ctx, ctx.Args[2:])
+		if err != nil {
+			return ctx.SendX(err.Error())
+		}
+	case "mode":
+		t.Mode = nil
+		if value != "" {
+			value = strings.ToLower(value)
+			if value != "channel" && value != "thread" && value != "modal" {
+				return ctx.SendX("Valid modes are: `channel`, `thread`, `modal`")
+			}
+			t.Mode = &value
+		}
+	default:
+		return ctx.SendfX("Unknown override ``%v``. Valid overrides are: `intro`, `finished`, `category`, `roles`, `reviewers`, `mode`", bcr.EscapeBackticks(ctx.Args[1]))
+	}
+
+	err = bot.DB.SetTrackOverrides(*t)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if value == "" {
+		return ctx.SendfX("Reset ``%v`` for track **%v**.", bcr.EscapeBackticks(ctx.Args[1]), t.Name)
+	}
+	return ctx.SendfX("Updated ``%v`` for track **%v**.", bcr.EscapeBackticks(ctx.Args[1]), t.Name)
+}
+
+func parseRoleList(ctx *bcr.Context, args []string) ([]uint64, error) {
+	roles := []uint64{}
+	for _, arg := range args {
+		r, err := ctx.ParseRole(arg)
+		if err != nil {
+			return nil, common.Error("Couldn't find a role named ``%v``.", bcr.EscapeBackticks(arg))
+		}
+		roles = append(roles, uint64(r.ID))
+	}
+	return roles, nil
+}
+
 func (bot *Bot) createAppTrack(ctx *bcr.Context) (err error) {
 	name := ctx.Args[0]
 	desc := ctx.Args[1]
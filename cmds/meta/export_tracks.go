@@ -12,6 +12,13 @@ type exportTrack struct {
 	Emoji       string   `yaml:"emoji"`
 	Description string   `yaml:"description,flow"`
 	Questions   []string `yaml:"questions,omitempty"`
+
+	IntroMessage    string   `yaml:"intro_message,omitempty"`
+	FinishedMessage string   `yaml:"finished_message,omitempty"`
+	Category        uint64   `yaml:"category,omitempty"`
+	VerifyRoles     []uint64 `yaml:"verify_roles,omitempty,flow"`
+	ReviewerRoles   []uint64 `yaml:"reviewer_roles,omitempty,flow"`
+	Mode            string   `yaml:"mode,omitempty"`
 }
 
 func (bot *Bot) exportTracks(ctx *bcr.Context) (err error) {
@@ -33,11 +40,27 @@ func (bot *Bot) exportTracks(ctx *bcr.Context) (err error) {
 			s = append(s, q.Question)
 		}
 
-		export[t.Name] = exportTrack{
-			Emoji:       t.RawEmoji,
-			Description: t.Description,
-			Questions:   s,
+		et := exportTrack{
+			Emoji:         t.RawEmoji,
+			Description:   t.Description,
+			Questions:     s,
+			VerifyRoles:   t.VerifyRoles,
+			ReviewerRoles: t.ReviewerRoles,
+		}
+		if t.IntroMessage != nil {
+			et.IntroMessage = *t.IntroMessage
+		}
+		if t.FinishedMessage != nil {
+			et.FinishedMessage = *t.FinishedMessage
 		}
+		if t.CategoryID != nil {
+			et.Category = uint64(*t.CategoryID)
+		}
+		if t.Mode != nil {
+			et.Mode = *t.Mode
+		}
+
+		export[t.Name] = et
 	}
 
 	b, err := yaml.Marshal(export)
@@ -77,6 +77,39 @@ func (bot *Bot) importTracks(ctx *bcr.Context) (err error) {
 			trackID = t.ID
 		}
 
+		// set overrides
+		var intro, finished *string
+		if track.IntroMessage != "" {
+			intro = &track.IntroMessage
+		}
+		if track.FinishedMessage != "" {
+			finished = &track.FinishedMessage
+		}
+		var mode *string
+		if track.Mode != "" {
+			if track.Mode != "channel" && track.Mode != "thread" && track.Mode != "modal" {
+				return ctx.SendfX("Track %v has an invalid mode ``%v``. Valid modes are: `channel`, `thread`, `modal`", name, bcr.EscapeBackticks(track.Mode))
+			}
+			mode = &track.Mode
+		}
+		var category *uint64
+		if track.Category != 0 {
+			category = &track.Category
+		}
+		if track.VerifyRoles == nil {
+			track.VerifyRoles = []uint64{}
+		}
+		if track.ReviewerRoles == nil {
+			track.ReviewerRoles = []uint64{}
+		}
+
+		_, err = tx.Exec(context.Background(), `update application_tracks set
+		intro_message = $1, finished_message = $2, category_id = $3, verify_roles = $4, reviewer_roles = $5, mode = $6
+		where id = $7`, intro, finished, category, track.VerifyRoles, track.ReviewerRoles, mode, trackID)
+		if err != nil {
+			return bot.Report(ctx, errors.Wrap(err, "set track overrides"))
+		}
+
 		// clear existing questions
 		_, err = tx.Exec(context.Background(), "delete from app_questions where track_id = $1", trackID)
 		if err != nil {
@@ -29,6 +29,8 @@ func Init(bot *bot.Bot) {
 
 	b.Router.AddHandler(b.pointlessStats)
 
+	b.Scheduler.AddType(&queueUpdate{})
+
 	b.Router.AddCommand(&bcr.Command{
 		Name:              "stats",
 		Summary:           "Show a couple of useless statistics",
@@ -173,6 +175,20 @@ func appCommands(b *Bot) {
 		Command:           b.importTracks,
 	})
 
+	queue := app.AddSubcommand(&bcr.Command{
+		Name:              "queue",
+		Summary:           "List all open applications",
+		CustomPermissions: b.Checker,
+		Command:           b.appQueue,
+	})
+
+	queue.AddSubcommand(&bcr.Command{
+		Name:              "pin",
+		Summary:           "Post a self-updating application queue in the current channel",
+		CustomPermissions: b.Checker,
+		Command:           b.appQueuePin,
+	})
+
 	app.AddSubcommand(&bcr.Command{
 		Name:              "setup",
 		Summary:           "Send the application trigger message in the current channel.",
@@ -188,6 +204,16 @@ func appCommands(b *Bot) {
 		Command:           b.listAppTracks,
 	})
 
+	track.AddSubcommand(&bcr.Command{
+		Name:              "set",
+		Summary:           "Override a setting for the given application track",
+		Description:       "Override a setting for the given application track. Leave the value empty to reset it.\nValid settings are: `intro` (message sent when the track is chosen), `finished` (replaces `application_finished_message`), `category` (application channels are moved here when the track is chosen), `roles` (extra roles given on verification), `reviewers` (roles pinged when an application is finished), `mode` (replaces `application_mode`)",
+		Usage:             "<id> <setting> [value...]",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           b.setTrackOverride,
+	})
+
 	track.AddSubcommand(&bcr.Command{
 		Name:              "create",
 		Summary:           "Create an application track",
@@ -57,7 +57,18 @@ func (bot *Bot) ban(ctx *bcr.Context) (err error) {
 	}
 
 	if isMember {
-		_, err = ctx.NewDM(target.ID).Content(fmt.Sprintf("You were banned from %v.\nReason: %v", ctx.Guild.Name, reason)).Send()
+		data := api.SendMessageData{
+			Content: fmt.Sprintf("You were banned from %v.\nReason: %v", ctx.Guild.Name, reason),
+		}
+		if bot.DB.Config.Get("appeals_channel").ToChannelID().IsValid() {
+			data.Content += "\nIf you think this was a mistake, you can appeal your ban with the button below."
+			data.Components = common.AppealButton()
+		}
+
+		ch, err := ctx.State.CreatePrivateChannel(target.ID)
+		if err == nil {
+			_, err = ctx.State.SendMessageComplex(ch.ID, data)
+		}
 		if err != nil {
 			_, _ = ctx.Send("We were unable to DM the user about their ban!")
 		}
@@ -4,6 +4,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/diamondburned/arikawa/v3/discord"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -46,3 +48,15 @@ func init() {
 
 // OpenApplication is the custom ID used for the "open application" button
 const OpenApplication = "open_application"
+
+// OpenAppeal is the custom ID used for the "appeal" button sent to denied and banned users
+const OpenAppeal = "open_appeal"
+
+// AppealButton returns the "appeal" button added to deny and ban DMs.
+func AppealButton() discord.ContainerComponents {
+	return discord.Components(&discord.ButtonComponent{
+		Label:    "Appeal",
+		Style:    discord.SecondaryButtonStyle(),
+		CustomID: OpenAppeal,
+	})
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/xid"
+)
+
+// AppCooldown is a period in which a user may not open a new application.
+type AppCooldown struct {
+	UserID discord.UserID
+	// Null if the user may never reapply
+	Expires *time.Time
+	Reason  string
+	// Null if the cooldown was set automatically (for example, on timeout)
+	Moderator *discord.UserID
+
+	ApplicationID *xid.ID
+}
+
+// Permanent returns true if the user may never reapply.
+func (c AppCooldown) Permanent() bool {
+	return c.Expires == nil
+}
+
+// AppCooldown returns the active cooldown for the given user, or nil if they may apply.
+func (db *DB) AppCooldown(userID discord.UserID) (*AppCooldown, error) {
+	var c AppCooldown
+	err := pgxscan.Get(context.Background(), db, &c, "select * from app_cooldowns where user_id = $1 and (expires is null or expires > $2)", userID, time.Now().UTC())
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Cause(err)
+	}
+	return &c, nil
+}
+
+// SetAppCooldown sets the given user's cooldown, overwriting any existing cooldown.
+// A permanent block is never overwritten by a temporary cooldown.
+func (db *DB) SetAppCooldown(c AppCooldown) error {
+	var expires *time.Time
+	if c.Expires != nil {
+		t := c.Expires.UTC()
+		expires = &t
+	}
+
+	_, err := db.Exec(context.Background(), `insert into app_cooldowns
+	(user_id, expires, reason, moderator, application_id)
+	values ($1, $2, $3, $4, $5)
+	on conflict (user_id) do update
+	set expires = $2, reason = $3, moderator = $4, application_id = $5
+	where app_cooldowns.expires is not null or $2::timestamp is null`, c.UserID, expires, c.Reason, c.Moderator, c.ApplicationID)
+	return err
+}
+
+// ClearAppCooldown removes the given user's cooldown. Returns pgx.ErrNoRows if the user had none.
+func (db *DB) ClearAppCooldown(userID discord.UserID) error {
+	ct, err := db.Exec(context.Background(), "delete from app_cooldowns where user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+)
+
+// QueuedApplication is an open application along with some extra information for the queue overview.
+type QueuedApplication struct {
+	Application
+
+	// Null if the track was deleted or no track was chosen yet
+	TrackName *string
+	// The number of questions in the application's track
+	QuestionCount int
+	// The last message the applicant sent in their channel, if any
+	LastMessage *discord.MessageID
+	// When the application is scheduled to be closed, if it is
+	CloseTime *time.Time
+}
+
+// TimeoutCloseStage is the stage of an application timeout event that closes the application.
+const TimeoutCloseStage = 2
+
+// OpenApplications returns all open applications, oldest first.
+func (db *DB) OpenApplications() (as []QueuedApplication, err error) {
+	err = pgxscan.Select(context.Background(), db, &as, `select a.*,
+	t.name as track_name,
+	(select count(*) from app_questions q where q.track_id = a.track_id) as question_count,
+	(select max(r.message_id) from app_responses r where r.application_id = a.id and r.user_id = a.user_id) as last_message,
+	(select min(e.expires) from scheduled_events e where e.id = a.scheduled_close_id
+		or (e.id = a.scheduled_event_id and e.event_type = 'applications.timeout' and (e.data->>'stage')::int = $1)) as close_time
+	from applications a
+	left join application_tracks t on t.id = a.track_id
+	where a.closed = false
+	order by a.id asc`, TimeoutCloseStage)
+	return as, errors.Cause(err)
+}
@@ -15,6 +15,35 @@ type ApplicationTrack struct {
 	Description string
 	// RawEmoji is stored as a?:name:id
 	RawEmoji string `db:"emoji"`
+
+	// Overrides for the global configuration, null or empty if not set
+	IntroMessage    *string
+	FinishedMessage *string
+	CategoryID      *discord.ChannelID
+	// Added in addition to verified_role and adult_role/minor_role
+	VerifyRoles []uint64
+	// Pinged when an application in this track is finished
+	ReviewerRoles []uint64
+	// One of "channel", "thread" or "modal"
+	Mode *string
+}
+
+// VerifyRoleIDs returns the track's additional verification roles.
+func (t ApplicationTrack) VerifyRoleIDs() []discord.RoleID {
+	return roleIDs(t.VerifyRoles)
+}
+
+// ReviewerRoleIDs returns the track's reviewer roles.
+func (t ApplicationTrack) ReviewerRoleIDs() []discord.RoleID {
+	return roleIDs(t.ReviewerRoles)
+}
+
+func roleIDs(ids []uint64) []discord.RoleID {
+	out := make([]discord.RoleID, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, discord.RoleID(id))
+	}
+	return out
 }
 
 // Emoji returns the emoji struct version of the stored emoji.
@@ -79,6 +108,21 @@ func (db *DB) UpdateApplicationTrack(t ApplicationTrack) error {
 	return err
 }
 
+// SetTrackOverrides updates the given application track's overrides.
+func (db *DB) SetTrackOverrides(t ApplicationTrack) error {
+	if t.VerifyRoles == nil {
+		t.VerifyRoles = []uint64{}
+	}
+	if t.ReviewerRoles == nil {
+		t.ReviewerRoles = []uint64{}
+	}
+
+	_, err := db.Exec(context.Background(), `update application_tracks set
+	intro_message = $1, finished_message = $2, category_id = $3, verify_roles = $4, reviewer_roles = $5, mode = $6
+	where id = $7`, t.IntroMessage, t.FinishedMessage, t.CategoryID, t.VerifyRoles, t.ReviewerRoles, t.Mode, t.ID)
+	return err
+}
+
 // AppQuestion is a single application question.
 type AppQuestion struct {
 	Index      int64
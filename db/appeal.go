@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/xid"
+)
+
+// Appeal kinds
+const (
+	DenyAppeal = "deny"
+	BanAppeal  = "ban"
+)
+
+// Appeal statuses
+const (
+	AppealPending  = "pending"
+	AppealAccepted = "accepted"
+	AppealRejected = "rejected"
+)
+
+// Appeal is a request by a denied or banned user to have that decision reconsidered.
+type Appeal struct {
+	ID     int64
+	UserID discord.UserID
+	Kind   string
+
+	// Set for deny appeals
+	ApplicationID *xid.ID
+	// Set for ban appeals
+	ModLogID *int64
+
+	Content string
+
+	Status    string
+	Moderator *discord.UserID
+	Reason    *string
+
+	Created time.Time
+	Decided *time.Time
+
+	ChannelID *discord.ChannelID
+	MessageID *discord.MessageID
+}
+
+// CreateAppeal creates a new pending appeal.
+func (db *DB) CreateAppeal(a Appeal) (Appeal, error) {
+	err := pgxscan.Get(context.Background(), db, &a, `insert into appeals
+	(user_id, kind, application_id, mod_log_id, content, created)
+	values ($1, $2, $3, $4, $5, $6)
+	returning *`, a.UserID, a.Kind, a.ApplicationID, a.ModLogID, a.Content, time.Now().UTC())
+	return a, errors.Cause(err)
+}
+
+// Appeal returns the appeal with the given ID.
+func (db *DB) Appeal(id int64) (*Appeal, error) {
+	var a Appeal
+	err := pgxscan.Get(context.Background(), db, &a, "select * from appeals where id = $1", id)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	return &a, nil
+}
+
+// PendingAppeal returns the given user's pending appeal, or nil if they have none.
+func (db *DB) PendingAppeal(userID discord.UserID) (*Appeal, error) {
+	var a Appeal
+	err := pgxscan.Get(context.Background(), db, &a, "select * from appeals where user_id = $1 and status = $2 order by id desc limit 1", userID, AppealPending)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Cause(err)
+	}
+	return &a, nil
+}
+
+// CaseAppealed returns true if the given application or mod log entry has already been appealed.
+func (db *DB) CaseAppealed(appID *xid.ID, modLogID *int64) (appealed bool, err error) {
+	err = db.QueryRow(context.Background(), "select exists(select * from appeals where application_id = $1 or mod_log_id = $2)", appID, modLogID).Scan(&appealed)
+	return appealed, errors.Cause(err)
+}
+
+// SetAppealMessage sets the review message for the given appeal.
+func (db *DB) SetAppealMessage(id int64, chID discord.ChannelID, msgID discord.MessageID) error {
+	_, err := db.Exec(context.Background(), "update appeals set channel_id = $1, message_id = $2 where id = $3", chID, msgID, id)
+	return err
+}
+
+// DecideAppeal accepts or rejects the given appeal. Returns pgx.ErrNoRows if the appeal isn't pending.
+func (db *DB) DecideAppeal(id int64, mod discord.UserID, accepted bool, reason *string) (*Appeal, error) {
+	status := AppealRejected
+	if accepted {
+		status = AppealAccepted
+	}
+
+	var a Appeal
+	err := pgxscan.Get(context.Background(), db, &a, `update appeals
+	set status = $1, moderator = $2, reason = $3, decided = $4
+	where id = $5 and status = $6
+	returning *`, status, mod, reason, time.Now().UTC(), id, AppealPending)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	return &a, nil
+}
@@ -29,6 +29,9 @@ type Application struct {
 	DenyReason *string
 	// Moderator who verified or denied the user
 	Moderator *discord.UserID
+	// Whether the application was automatically closed after timing out.
+	// Timed out applications are neither verified nor denied.
+	TimedOut bool
 
 	// Whether the interview has been closed (channel deleted)
 	Closed     bool
@@ -51,6 +54,16 @@ func (db *DB) AllUserApplications(userID discord.UserID) (as []Application, err
 	return as, nil
 }
 
+// Application returns the application with the given ID.
+func (db *DB) Application(id xid.ID) (*Application, error) {
+	var a Application
+	err := pgxscan.Get(context.Background(), db, &a, "select * from applications where id = $1", id)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	return &a, nil
+}
+
 // UserApplication returns an open application for the given user.
 func (db *DB) UserApplication(userID discord.UserID) (*Application, error) {
 	var a Application
@@ -71,6 +84,12 @@ func (db *DB) ChannelApplication(chID discord.ChannelID) (*Application, error) {
 	return &a, nil
 }
 
+// SetTimedOut marks the application as timed out.
+func (db *DB) SetTimedOut(id xid.ID) error {
+	_, err := db.Exec(context.Background(), "update applications set timed_out = true where id = $1", id)
+	return err
+}
+
 // CloseApplication closes the given application.
 func (db *DB) CloseApplication(id xid.ID) error {
 	_, err := db.Exec(context.Background(), "update applications set closed = true, closed_time = $2 where id = $1", id, time.Now().UTC())
@@ -141,6 +160,13 @@ type AppResponse struct {
 	FromStaff bool
 }
 
+// SetResponse adds a response to an application, overwriting any existing response with the same message ID.
+// Used for responses the bot posts on behalf of the applicant.
+func (db *DB) SetResponse(appID xid.ID, resp AppResponse) error {
+	_, err := db.Exec(context.Background(), "insert into app_responses (application_id, message_id, user_id, username, discriminator, content, from_bot, from_staff) values ($1, $2, $3, $4, $5, $6, $7, $8) on conflict (message_id) do update set user_id = $3, username = $4, discriminator = $5, content = $6, from_bot = $7, from_staff = $8", appID, resp.MessageID, resp.UserID, resp.Username, resp.Discriminator, resp.Content, resp.FromBot, resp.FromStaff)
+	return err
+}
+
 // AddResponse adds or updates a response to an application.
 func (db *DB) AddResponse(appID xid.ID, resp AppResponse) error {
 	_, err := db.Exec(context.Background(), "insert into app_responses (application_id, message_id, user_id, username, discriminator, content, from_bot, from_staff) values ($1, $2, $3, $4, $5, $6, $7, $8) on conflict (message_id) do update set content = $6", appID, resp.MessageID, resp.UserID, resp.Username, resp.Discriminator, resp.Content, resp.FromBot, resp.FromStaff)
@@ -84,6 +84,17 @@ var ConfigOptions = map[string]ConfigOption{
 		Type:         StringOptionType,
 		DefaultValue: "Application finished! Please continue to add proof if it included more than one screenshot, and otherwise, please be patient until a mod can review your answers.",
 	},
+	"application_mode": {
+		Description:  "How applications are run, unless overridden by the application track. Valid options are:\n- `channel`: a new channel is created for each application\n- `thread`: a private thread is created in `application_thread_channel` for each application\n- `modal`: questions are answered in pop-up forms, and the answers are posted in a private thread in `application_thread_channel`. Question labels are limited to 45 characters, so this works best for short tracks.",
+		Type:         StringOptionType,
+		DefaultValue: "channel",
+		ValidValues:  []interface{}{"channel", "thread", "modal"},
+	},
+	"application_thread_channel": {
+		Description:  "The channel where private application threads are created, for the `thread` and `modal` application modes. Staff need the Manage Threads permission in this channel to see application threads.",
+		Type:         SnowflakeOptionType,
+		DefaultValue: 0,
+	},
 	"long_answer_minimum": {
 		Description:  "Minimum number of words needed to advance questions where long answers are required.",
 		Type:         IntOptionType,
@@ -131,6 +142,94 @@ var ConfigOptions = map[string]ConfigOption{
 		Type:         BoolOptionType,
 		DefaultValue: false,
 	},
+	"deny_cooldown": {
+		Description:  "The number of hours a denied user has to wait before opening a new application. Set to 0 to let denied users reapply immediately.\nTo stop a user from reapplying at all, deny them with `{prefix}deny --permanent`.",
+		Type:         IntOptionType,
+		DefaultValue: 0,
+	},
+	"appeals_channel": {
+		Description:  "The channel to post appeals from denied and banned users in. Set to 0 to disable appeals.",
+		Type:         SnowflakeOptionType,
+		DefaultValue: 0,
+	},
+	"appeal_invite": {
+		Description:  "Whether to create a single-use invite (in the `invite_channel`) for users whose appeal was accepted, if they aren't in the server.",
+		Type:         BoolOptionType,
+		DefaultValue: true,
+	},
+	"app_queue_channel": {
+		Description:  "The channel the pinned application queue is in. Set automatically by `{prefix}app queue pin`.",
+		Type:         SnowflakeOptionType,
+		DefaultValue: 0,
+	},
+	"app_queue_message": {
+		Description:  "The pinned application queue message. Set automatically by `{prefix}app queue pin`; set to 0 to stop updating it.",
+		Type:         SnowflakeOptionType,
+		DefaultValue: 0,
+	},
+	"app_timeout_time": {
+		Description:  "The number of hours an applicant can go without answering before they are reminded and their application is announced as timed out in `discussion_channel`. Set to 0 to disable timeouts entirely.",
+		Type:         IntOptionType,
+		DefaultValue: 24,
+	},
+	"app_reminder_message": {
+		Description:  "The message sent in the application channel when an application times out. `{mention}` is replaced with a mention of the applicant. If empty, no reminder is sent.",
+		Type:         StringOptionType,
+		DefaultValue: "{mention}, are you still there? Please answer the question above to continue your application!",
+	},
+	"app_warning_time": {
+		Description:  "The number of hours after the reminder before the applicant is sent a final warning. Set to 0 to skip the warning.",
+		Type:         IntOptionType,
+		DefaultValue: 0,
+	},
+	"app_warning_message": {
+		Description:  "The final warning sent in the application channel. `{mention}` is replaced with a mention of the applicant.",
+		Type:         StringOptionType,
+		DefaultValue: "{mention}, this is your final reminder! If you don't continue your application soon, it will be closed automatically.",
+	},
+	"app_auto_close_time": {
+		Description:  "The number of hours after the last reminder or warning before a stalled application is closed automatically, with a transcript. Set to 0 to never close applications automatically.",
+		Type:         IntOptionType,
+		DefaultValue: 0,
+	},
+	"app_auto_close_action": {
+		Description:  "What to do when a stalled application is closed automatically. Valid options are: `close`, `kick`",
+		Type:         StringOptionType,
+		DefaultValue: "close",
+		ValidValues:  []interface{}{"close", "kick"},
+	},
+	"timeout_cooldown": {
+		Description:  "The number of hours a user has to wait before opening a new application after their previous application timed out (see `app_timeout_time`). Set to 0 to disable.",
+		Type:         IntOptionType,
+		DefaultValue: 0,
+	},
+
+	// application risk flags
+	"risk_account_age": {
+		Description:  "Flag applicants whose account is younger than this many hours. Set to 0 to disable.",
+		Type:         IntOptionType,
+		DefaultValue: 168,
+	},
+	"risk_no_avatar": {
+		Description:  "Whether to flag applicants who don't have an avatar.",
+		Type:         BoolOptionType,
+		DefaultValue: true,
+	},
+	"risk_denied_applications": {
+		Description:  "Flag applicants who were denied at least this many times before. Set to 0 to disable.",
+		Type:         IntOptionType,
+		DefaultValue: 1,
+	},
+	"risk_mod_logs": {
+		Description:  "Flag applicants with at least this many mod log entries. Set to 0 to disable.",
+		Type:         IntOptionType,
+		DefaultValue: 1,
+	},
+	"risk_rejoin_time": {
+		Description:  "Flag applicants who rejoined within this many hours of leaving the server. Set to 0 to disable.",
+		Type:         IntOptionType,
+		DefaultValue: 24,
+	},
 
 	// logging configuration
 	"join_leave_log": {
@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+)
+
+// SetMemberLeft records that the given user left the server just now.
+func (db *DB) SetMemberLeft(userID discord.UserID) error {
+	_, err := db.Exec(context.Background(), `insert into member_leaves (user_id, left_at) values ($1, $2)
+	on conflict (user_id) do update set left_at = $2`, userID, time.Now().UTC())
+	return err
+}
+
+// LastLeave returns when the given user last left the server, or nil if they never did.
+func (db *DB) LastLeave(userID discord.UserID) (*time.Time, error) {
+	var t time.Time
+	err := db.QueryRow(context.Background(), "select left_at from member_leaves where user_id = $1", userID).Scan(&t)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/Masterminds/squirrel"
@@ -33,12 +34,44 @@ func (db *DB) InsertMessage(m Message) (err error) {
 		m.Content = "None"
 	}
 
-	_, err = db.Exec(context.Background(), `insert into messages
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = tx.Exec(context.Background(), `insert into messages
 (id, user_id, channel_id, server_id, content, username, member, system) values
 ($1, $2, $3, $4, $5, $6, $7, $8)
 on conflict (id) do update
 set content = $5`, m.ID, m.UserID, m.ChannelID, m.ServerID, m.Content, m.Username, m.Member, m.System)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// only add a revision if the content actually changed
+	_, err = tx.Exec(context.Background(), `insert into message_revisions (message_id, content)
+select $1::bigint, $2::text where $2::text is distinct from
+(select content from message_revisions where message_id = $1 order by id desc limit 1)`, m.ID, m.Content)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// MessageRevision is a single version of a message's content.
+type MessageRevision struct {
+	ID        int64
+	MessageID discord.MessageID
+	Content   string
+	Created   time.Time
+}
+
+// MessageRevisions returns all versions of the given message, oldest first.
+func (db *DB) MessageRevisions(id discord.MessageID) (revs []MessageRevision, err error) {
+	err = pgxscan.Select(context.Background(), db, &revs, "select * from message_revisions where message_id = $1 order by id", id)
+	return revs, err
 }
 
 // UpdatePKInfo updates the PluralKit info for the given message, if it exists in the database.
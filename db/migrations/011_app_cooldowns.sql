@@ -0,0 +1,14 @@
+-- 2026-10-19
+-- Add reapplication cooldowns
+
+-- +migrate Up
+
+create table app_cooldowns (
+    user_id     bigint      primary key,
+    -- null if the user may never reapply
+    expires     timestamp,
+    reason      text        not null,
+    moderator   bigint,
+
+    application_id  text    references applications (id) on delete set null
+);
@@ -0,0 +1,9 @@
+-- 2026-10-19
+-- Store when members last left, for fast rejoin detection
+
+-- +migrate Up
+
+create table member_leaves (
+    user_id bigint      primary key,
+    left_at timestamp   not null
+);
@@ -0,0 +1,10 @@
+-- 2026-10-19
+-- Add per-track overrides
+
+-- +migrate Up
+
+alter table application_tracks add column intro_message text;
+alter table application_tracks add column finished_message text;
+alter table application_tracks add column category_id bigint;
+alter table application_tracks add column verify_roles bigint[] not null default array[]::bigint[];
+alter table application_tracks add column reviewer_roles bigint[] not null default array[]::bigint[];
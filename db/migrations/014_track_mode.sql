@@ -0,0 +1,7 @@
+-- 2026-10-19
+-- Add per-track application mode
+
+-- +migrate Up
+
+-- null to use the global application_mode
+alter table application_tracks add column mode text;
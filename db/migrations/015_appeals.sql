@@ -0,0 +1,31 @@
+-- 2026-10-19
+-- Add appeals for denied and banned users
+
+-- +migrate Up
+
+create table appeals (
+    id          serial  primary key,
+    user_id     bigint  not null,
+    -- either 'deny' or 'ban'
+    kind        text    not null,
+
+    -- the denied application, for deny appeals
+    application_id  text    references applications (id) on delete set null,
+    -- the ban's mod log entry, for ban appeals
+    mod_log_id      integer references mod_log (id) on delete set null,
+
+    content     text    not null,
+
+    -- 'pending', 'accepted', or 'rejected'
+    status      text    not null    default 'pending',
+    moderator   bigint,
+    reason      text,
+
+    created     timestamp   not null    default (current_timestamp at time zone 'utc'),
+    decided     timestamp,
+
+    channel_id  bigint,
+    message_id  bigint
+);
+
+create index appeals_user_idx on appeals (user_id);
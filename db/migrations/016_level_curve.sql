@@ -0,0 +1,11 @@
+-- 2026-10-19
+-- Add configurable XP per message and level curves
+
+-- +migrate Up
+
+alter table level_config add column min_xp bigint not null default 15;
+alter table level_config add column max_xp bigint not null default 26;
+
+-- one of 'default', 'mee6', 'linear', 'polynomial'
+alter table level_config add column level_curve text not null default 'default';
+alter table level_config add column curve_coefficients double precision[] not null default array[]::double precision[];
@@ -0,0 +1,29 @@
+-- 2026-10-19
+-- Add XP multipliers and timed XP events
+
+-- +migrate Up
+
+create table level_multipliers (
+    guild_id    bigint  not null,
+    target_id   bigint  not null,
+    -- one of 'channel', 'category', 'role'
+    target_type text    not null,
+    multiplier  double precision    not null,
+
+    primary key (guild_id, target_id)
+);
+
+create table xp_events (
+    id          serial  primary key,
+    guild_id    bigint  not null,
+    name        text    not null,
+    multiplier  double precision    not null,
+
+    starts  timestamp   not null,
+    ends    timestamp   not null,
+    -- set by the scheduled start event, the row is deleted by the scheduled end event
+    active  boolean     not null    default false,
+
+    start_event_id  bigint,
+    end_event_id    bigint
+);
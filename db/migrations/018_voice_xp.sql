@@ -0,0 +1,10 @@
+-- 2026-10-19
+-- Add voice XP
+
+-- +migrate Up
+
+-- XP given per minute in voice channels, 0 to disable
+alter table level_config add column voice_xp bigint not null default 0;
+
+-- the part of a user's XP that was gained in voice channels
+alter table levels add column voice_xp bigint not null default 0;
@@ -0,0 +1,21 @@
+-- 2026-10-19
+-- Add daily XP history, for periodic leaderboards
+
+-- +migrate Up
+
+create table xp_history (
+    guild_id    bigint  not null,
+    user_id     bigint  not null,
+    day         date    not null,
+
+    xp          bigint  not null    default 0,
+    -- the part of xp gained in voice channels
+    voice_xp    bigint  not null    default 0,
+
+    primary key (guild_id, user_id, day)
+);
+
+create index xp_history_day_idx on xp_history (guild_id, day);
+
+-- the channel to post the weekly leaderboard in
+alter table level_config add column leaderboard_channel bigint not null default 0;
@@ -0,0 +1,7 @@
+-- 2026-10-19
+-- Add choice between stacking level rewards and keeping only the highest
+
+-- +migrate Up
+
+-- if false, members only keep the role for the highest level reward they've reached
+alter table level_config add column stack_rewards boolean not null default true;
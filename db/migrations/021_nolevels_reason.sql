@@ -0,0 +1,8 @@
+-- 2026-10-19
+-- Add reason and moderator to nolevels, and track the scheduled expiry
+
+-- +migrate Up
+
+alter table nolevels add column reason text not null default '';
+alter table nolevels add column moderator bigint not null default 0;
+alter table nolevels add column event_id integer;
@@ -0,0 +1,19 @@
+-- 2026-10-19
+-- Add rank card themes and layouts, and user-uploaded backgrounds
+
+-- +migrate Up
+
+-- backgrounds with an owner were uploaded by that user, and can only be used by them
+alter table level_backgrounds add column owner_id bigint;
+-- pending backgrounds are waiting for staff approval
+alter table level_backgrounds add column status text not null default 'approved';
+alter table level_backgrounds add column uploaded timestamp not null default (current_timestamp at time zone 'utc');
+
+alter table levels add column card_theme text not null default 'dark';
+alter table levels add column card_layout text not null default 'classic';
+
+-- uploading a background requires reaching custom_bg_level *or* having custom_bg_role, if either is set
+alter table level_config add column custom_bg_level bigint not null default 0;
+alter table level_config add column custom_bg_role bigint not null default 0;
+-- uploads are disabled if this isn't set
+alter table level_config add column bg_approval_channel bigint not null default 0;
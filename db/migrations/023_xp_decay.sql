@@ -0,0 +1,24 @@
+-- 2026-10-19
+-- Add XP decay for inactive members
+
+-- +migrate Up
+
+-- decay is disabled if decay_days is 0
+alter table level_config add column decay_days bigint not null default 0;
+-- if decay_percent is true, decay_amount is a percentage of the member's XP, otherwise it's a fixed amount of XP
+alter table level_config add column decay_percent boolean not null default true;
+alter table level_config add column decay_amount double precision not null default 0;
+-- members don't decay below this amount of XP
+alter table level_config add column decay_floor bigint not null default 0;
+
+create table xp_decay_log (
+    id          serial      primary key,
+    guild_id    bigint      not null,
+    user_id     bigint      not null,
+    xp_before   bigint      not null,
+    xp_after    bigint      not null,
+    decayed_at  timestamp   not null    default (current_timestamp at time zone 'utc'),
+    reversed    boolean     not null    default false
+);
+
+create index xp_decay_log_user_idx on xp_decay_log (guild_id, user_id);
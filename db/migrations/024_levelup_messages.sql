@@ -0,0 +1,19 @@
+-- 2026-10-19
+-- Add level-up announcements
+
+-- +migrate Up
+
+-- one of 'off', 'current' (the channel the member levelled up in), 'channel' (levelup_channel), or 'dm'
+alter table level_config add column levelup_mode text not null default 'off';
+alter table level_config add column levelup_channel bigint not null default 0;
+-- the default level-up message, a built-in message is used if this is empty
+alter table level_config add column levelup_message text not null default '';
+
+-- custom messages for specific levels, these override levelup_message
+create table level_messages (
+    guild_id    bigint  not null,
+    lvl         bigint  not null,
+    message     text    not null,
+
+    primary key (guild_id, lvl)
+);
@@ -0,0 +1,68 @@
+-- 2026-10-19
+-- Allow multiple starboards per server
+
+-- +migrate Up
+
+create table starboards (
+    id              serial  primary key,
+    guild_id        bigint  not null,
+    name            text    not null,
+    channel_id      bigint  not null,
+    emoji           text    not null    default '⭐',
+    reaction_limit  int     not null    default 3,
+    allow_self_star boolean not null    default true,
+    -- if not empty, only messages in these channels or categories are posted to this board
+    channels        bigint[]    not null    default array[]::bigint[]
+);
+
+create unique index starboards_name_idx on starboards (guild_id, lower(name));
+
+insert into starboards (guild_id, name, channel_id, emoji, reaction_limit, allow_self_star)
+select guild_id, 'starboard', channel_id, emoji, reaction_limit, allow_self_star from starboard;
+
+-- overrides are now per board
+alter table starboard_overrides add column board_id int references starboards (id) on delete cascade;
+update starboard_overrides o set board_id = s.id from starboards s where s.guild_id = o.guild_id;
+delete from starboard_overrides where board_id is null;
+alter table starboard_overrides alter column board_id set not null;
+alter table starboard_overrides drop constraint starboard_overrides_pkey;
+alter table starboard_overrides add primary key (board_id, channel_id);
+-- null if the override doesn't change whether the board is enabled, so a category's setting is inherited
+alter table starboard_overrides alter column disabled drop not null;
+alter table starboard_overrides alter column disabled drop default;
+
+-- a message can be posted to multiple boards, so store which board (and channel) each post is in
+alter table starboard_messages add column board_id int references starboards (id) on delete cascade;
+alter table starboard_messages add column starboard_channel_id bigint not null default 0;
+update starboard_messages m set board_id = s.id, starboard_channel_id = coalesce(
+    (select o.starboard from starboard_overrides o where o.board_id = s.id and o.channel_id = m.channel_id),
+    s.channel_id
+) from starboards s where s.guild_id = m.guild_id;
+delete from starboard_messages where board_id is null;
+alter table starboard_messages alter column board_id set not null;
+alter table starboard_messages drop constraint starboard_messages_pkey;
+alter table starboard_messages add primary key (message_id, board_id);
+create index starboard_messages_starboard_idx on starboard_messages (starboard_id);
+
+-- boards can use different emoji, so reactions are counted per emoji
+alter table starboard_reactions add column emoji text not null default '';
+-- reactions were stored under the emoji in effect for the message's channel, which can come from an override
+update starboard_reactions r set emoji = coalesce(o.emoji, s.emoji)
+from starboard_messages m join starboards s on s.id = m.board_id
+left outer join starboard_overrides o on o.board_id = s.id and o.channel_id = m.channel_id
+where m.message_id = r.message_id;
+-- reactions on messages that were never posted can still be matched through the message log
+update starboard_reactions r set emoji = coalesce(o.emoji, s.emoji)
+from messages l join starboards s on s.guild_id = l.server_id
+left outer join starboard_overrides o on o.board_id = s.id and o.channel_id = l.channel_id
+where r.emoji = '' and l.id = r.message_id;
+-- anything else can't be matched to a guild, so only fill it in if there's just one guild, using one emoji
+update starboard_reactions set emoji = (select min(emoji) from starboards)
+where emoji = '' and (select count(*) from starboards) = 1
+and not exists (select 1 from starboard_overrides o join starboards s on s.id = o.board_id where o.emoji <> s.emoji);
+delete from starboard_reactions where emoji = '';
+alter table starboard_reactions alter column emoji drop default;
+alter table starboard_reactions drop constraint starboard_reactions_pkey;
+alter table starboard_reactions add primary key (user_id, message_id, emoji);
+
+drop table starboard;
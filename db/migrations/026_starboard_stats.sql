@@ -0,0 +1,25 @@
+-- 2026-10-19
+-- Track starboard post authors and star counts for statistics
+
+-- +migrate Up
+
+alter table starboard_messages add column author_id bigint not null default 0;
+alter table starboard_messages add column star_count int not null default 0;
+
+-- fill in authors from the message log where we can
+update starboard_messages m set author_id = l.user_id from messages l where l.id = m.message_id;
+
+update starboard_messages m set star_count = (
+    select count(*) from starboard_reactions r
+    join starboards s on s.id = m.board_id
+    left outer join starboard_overrides o on o.board_id = s.id and o.channel_id = m.channel_id
+    where r.message_id = m.message_id and r.emoji = coalesce(o.emoji, s.emoji)
+);
+
+create index starboard_messages_guild_idx on starboard_messages (guild_id, star_count);
+
+-- reactions need a guild to be counted for star-giver statistics
+alter table starboard_reactions add column guild_id bigint not null default 0;
+update starboard_reactions r set guild_id = m.guild_id from starboard_messages m where m.message_id = r.message_id;
+
+create index starboard_reactions_guild_idx on starboard_reactions (guild_id, user_id);
@@ -0,0 +1,29 @@
+-- 2026-10-19
+-- Add starboard lock, trash, and blacklist
+
+-- +migrate Up
+
+-- locked entries keep their current post and count
+alter table starboard_messages add column locked boolean not null default false;
+
+-- trashed messages are never posted to any starboard
+create table starboard_trash (
+    message_id      bigint  primary key,
+    guild_id        bigint  not null,
+    moderator_id    bigint  not null,
+    reason          text    not null    default '',
+    created         timestamp   not null    default (current_timestamp at time zone 'utc')
+);
+
+create index starboard_trash_guild_idx on starboard_trash (guild_id);
+
+-- blacklisted users' messages can't be starred, and their stars don't count
+create table starboard_blacklist (
+    guild_id        bigint  not null,
+    user_id         bigint  not null,
+    moderator_id    bigint  not null,
+    reason          text    not null    default '',
+    created         timestamp   not null    default (current_timestamp at time zone 'utc'),
+
+    primary key (guild_id, user_id)
+);
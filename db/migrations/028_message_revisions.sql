@@ -0,0 +1,18 @@
+-- 2026-10-19
+-- Store every version of logged messages
+
+-- +migrate Up
+
+create table message_revisions (
+    id          serial  primary key,
+    message_id  bigint  not null    references messages (id) on delete cascade,
+    content     text    not null,
+    created     timestamp   not null    default (current_timestamp at time zone 'utc')
+);
+
+create index message_revisions_message_idx on message_revisions (message_id);
+
+-- the current content is the only version we have for existing messages,
+-- so pretend it was sent as-is (message IDs are snowflakes, so they contain the creation time)
+insert into message_revisions (message_id, content, created)
+select id, content, to_timestamp(((id >> 22) + 1420070400000) / 1000.0) at time zone 'utc' from messages order by id;
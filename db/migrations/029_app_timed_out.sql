@@ -0,0 +1,6 @@
+-- 2026-10-19
+-- Store timed out applications separately from denials
+
+-- +migrate Up
+
+alter table applications add column timed_out boolean not null default false;
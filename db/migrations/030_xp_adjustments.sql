@@ -0,0 +1,16 @@
+-- 2026-10-19
+-- Log XP changes that aren't gains or decay, for the XP chart
+
+-- +migrate Up
+
+create table xp_adjustments (
+    id          serial      primary key,
+    guild_id    bigint      not null,
+    user_id     bigint      not null,
+    -- the change in XP, negative if XP was taken away
+    xp          bigint      not null,
+    reason      text        not null,
+    created     timestamp   not null    default (current_timestamp at time zone 'utc')
+);
+
+create index xp_adjustments_user_idx on xp_adjustments (guild_id, user_id);
@@ -95,6 +95,12 @@ func (db *DB) UpdateModLogMessage(id int64, chID discord.ChannelID, msgID discor
 	return e, errors.Cause(err)
 }
 
+// ModLogEntry returns the mod log entry with the given ID.
+func (db *DB) ModLogEntry(id int64) (e ModLogEntry, err error) {
+	err = pgxscan.Get(context.Background(), db, &e, "select * from mod_log where id = $1", id)
+	return e, errors.Cause(err)
+}
+
 func (db *DB) ModLogFor(guildID discord.GuildID, userID discord.UserID) (es []ModLogEntry, err error) {
 	err = pgxscan.Select(context.Background(), db, &es, "select * from mod_log where guild_id = $1 and user_id = $2 order by time desc", guildID, userID)
 	return es, errors.Cause(err)
@@ -6,4 +6,5 @@ pggen gen go \
 --go-type 'int8=int64' \
 --go-type 'text=string' \
 --go-type 'int4=int' \
---go-type 'boolean=bool'
\ No newline at end of file
+--go-type 'boolean=bool' \
+--go-type '_int8=[]int64'
\ No newline at end of file
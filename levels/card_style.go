@@ -0,0 +1,199 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
+)
+
+// Card layouts
+const (
+	LayoutClassic  = "classic"
+	LayoutMirrored = "mirrored"
+)
+
+// cardTheme is the set of colours used to draw a rank card.
+// All colours are 6-digit hex colours, or 8-digit if they include alpha.
+type cardTheme struct {
+	Name        string
+	Description string
+
+	// drawn over the background, behind everything else
+	Overlay string
+	Text    string
+	// the empty part of the progress bar
+	Bar string
+	// the line under the name, and the outline of the progress bar
+	Divider string
+}
+
+var cardThemes = map[string]cardTheme{
+	"dark": {
+		Name:        "dark",
+		Description: "White text on a dark overlay (the default)",
+		Overlay:     "#00000088",
+		Text:        "#ffffff",
+		Bar:         "#686868",
+		Divider:     "#b5b5b5",
+	},
+	"light": {
+		Name:        "light",
+		Description: "Dark text on a light overlay",
+		Overlay:     "#ffffffbb",
+		Text:        "#202225",
+		Bar:         "#c8c8c8",
+		Divider:     "#4f545c",
+	},
+	"clear": {
+		Name:        "clear",
+		Description: "White text on a barely-there overlay, to show off your background",
+		Overlay:     "#00000033",
+		Text:        "#ffffff",
+		Bar:         "#68686888",
+		Divider:     "#e0e0e0",
+	},
+	"midnight": {
+		Name:        "midnight",
+		Description: "Light text on a dark blue overlay",
+		Overlay:     "#0d1b2acc",
+		Text:        "#e0e1dd",
+		Bar:         "#415a77",
+		Divider:     "#778da9",
+	},
+}
+
+var cardLayouts = map[string]string{
+	LayoutClassic:  "Avatar on the left (the default)",
+	LayoutMirrored: "Avatar on the right",
+}
+
+// cardStyle is a user's rank card theme and layout.
+type cardStyle struct {
+	Theme  string
+	Layout string
+}
+
+func (s cardStyle) theme() cardTheme {
+	if t, ok := cardThemes[s.Theme]; ok {
+		return t
+	}
+	return cardThemes["dark"]
+}
+
+func sortedKeys(m map[string]string) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (bot *Bot) setColour(ctx *bcr.Context) (err error) {
+	// make sure the user has a row
+	_, err = bot.getUser(ctx.Message.GuildID, ctx.Author.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(ctx.Args) == 0 {
+		return ctx.SendfX("Use `%vlevel colour <hex code>` to set your rank card colour, or `%vlevel colour reset` to use your role colour.", bot.Prefix(), bot.Prefix())
+	}
+
+	if strings.EqualFold(ctx.Args[0], "reset") || strings.EqualFold(ctx.Args[0], "clear") {
+		_, err = bot.DB.Exec(context.Background(), "update levels set colour = 0 where guild_id = $1 and user_id = $2", ctx.Message.GuildID, ctx.Author.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		return ctx.SendX("Your rank card colour was reset! It'll now use your role colour.")
+	}
+
+	hex := strings.TrimPrefix(ctx.Args[0], "#")
+	c, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid hex colour (for example, `#ff80c0`).", ctx.Args[0])
+		return err
+	}
+	// 0 means "use the role colour", so use the closest colour for black
+	if c == 0 {
+		c = 1
+	}
+
+	_, err = bot.DB.Exec(context.Background(), "update levels set colour = $3 where guild_id = $1 and user_id = $2", ctx.Message.GuildID, ctx.Author.ID, c)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendX("", discord.Embed{
+		Description: fmt.Sprintf("Your rank card colour was set to `#%06x`!", c),
+		Color:       discord.Color(c),
+	})
+}
+
+func (bot *Bot) setTheme(ctx *bcr.Context) (err error) {
+	if len(ctx.Args) == 0 {
+		var s string
+		for _, name := range []string{"dark", "light", "clear", "midnight"} {
+			s += fmt.Sprintf("`%v`: %v\n", name, cardThemes[name].Description)
+		}
+		return ctx.SendX("", discord.Embed{
+			Title:       "Rank card themes",
+			Description: s,
+			Color:       bot.Colour,
+			Footer:      &discord.EmbedFooter{Text: fmt.Sprintf("Use %vlevel theme <name> to choose one.", bot.Prefix())},
+		})
+	}
+
+	t, ok := cardThemes[strings.ToLower(ctx.Args[0])]
+	if !ok {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` isn't a theme. Use `%vlevel theme` to see all themes.", ctx.Args[0], bot.Prefix())
+		return err
+	}
+
+	_, err = bot.getUser(ctx.Message.GuildID, ctx.Author.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = bot.DB.Exec(context.Background(), "update levels set card_theme = $3 where guild_id = $1 and user_id = $2", ctx.Message.GuildID, ctx.Author.ID, t.Name)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	return ctx.SendfX("Your rank card theme was set to **%v**!", t.Name)
+}
+
+func (bot *Bot) setLayout(ctx *bcr.Context) (err error) {
+	if len(ctx.Args) == 0 {
+		var s string
+		for _, name := range sortedKeys(cardLayouts) {
+			s += fmt.Sprintf("`%v`: %v\n", name, cardLayouts[name])
+		}
+		return ctx.SendX("", discord.Embed{
+			Title:       "Rank card layouts",
+			Description: s,
+			Color:       bot.Colour,
+			Footer:      &discord.EmbedFooter{Text: fmt.Sprintf("Use %vlevel layout <name> to choose one.", bot.Prefix())},
+		})
+	}
+
+	layout := strings.ToLower(ctx.Args[0])
+	if _, ok := cardLayouts[layout]; !ok {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` isn't a layout. Use `%vlevel layout` to see all layouts.", ctx.Args[0], bot.Prefix())
+		return err
+	}
+
+	_, err = bot.getUser(ctx.Message.GuildID, ctx.Author.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = bot.DB.Exec(context.Background(), "update levels set card_layout = $3 where guild_id = $1 and user_id = $2", ctx.Message.GuildID, ctx.Author.ID, layout)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	return ctx.SendfX("Your rank card layout was set to **%v**!", layout)
+}
@@ -0,0 +1,163 @@
+package levels
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Level curves
+const (
+	CurveDefault    = "default"
+	CurveMEE6       = "mee6"
+	CurveLinear     = "linear"
+	CurvePolynomial = "polynomial"
+)
+
+// the highest level that can be reached, to keep lookups bounded
+const maxLevel = 100000
+
+// Curve maps levels to the total XP needed to reach them.
+type Curve struct {
+	Type string
+	// For linear curves, the XP per level.
+	// For polynomial curves, the coefficients from the constant term upwards.
+	Coefficients []float64
+}
+
+// XP returns the total XP needed for the given level.
+func (c Curve) XP(level int64) int64 {
+	if level <= 0 {
+		return 0
+	}
+
+	switch c.Type {
+	case CurveMEE6:
+		// MEE6 needs 5L² + 50L + 100 XP to go from level L to level L+1
+		l := float64(level)
+		return clampXP(math.Round(5.0 / 6.0 * l * (2*l*l + 27*l + 91)))
+	case CurveLinear:
+		if len(c.Coefficients) == 0 {
+			return XPFromLevel(level)
+		}
+		return clampXP(c.Coefficients[0] * float64(level))
+	case CurvePolynomial:
+		if len(c.Coefficients) == 0 {
+			return XPFromLevel(level)
+		}
+
+		var xp float64
+		for i, coeff := range c.Coefficients {
+			xp += coeff * math.Pow(float64(level), float64(i))
+		}
+		return clampXP(xp)
+	default:
+		return XPFromLevel(level)
+	}
+}
+
+// clampXP converts the given XP to an integer, so very high levels don't overflow.
+func clampXP(xp float64) int64 {
+	if xp >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(xp)
+}
+
+// Level returns the level reached with the given XP.
+func (c Curve) Level(xp int64) int64 {
+	if c.Type == CurveDefault || c.Type == "" {
+		return LevelFromXP(xp)
+	}
+
+	// curves are validated to be increasing, so we can search for the first level we don't have enough XP for
+	lvl := sort.Search(maxLevel, func(i int) bool {
+		return c.XP(int64(i+1)) > xp
+	})
+	return int64(lvl)
+}
+
+// String returns a human-readable description of the curve.
+func (c Curve) String() string {
+	switch c.Type {
+	case CurveMEE6:
+		return "MEE6-compatible"
+	case CurveLinear:
+		if len(c.Coefficients) > 0 {
+			return fmt.Sprintf("linear, %v XP per level", c.Coefficients[0])
+		}
+	case CurvePolynomial:
+		var terms []string
+		for i := len(c.Coefficients) - 1; i >= 0; i-- {
+			coeff := c.Coefficients[i]
+			if coeff == 0 {
+				continue
+			}
+
+			s := strconv.FormatFloat(coeff, 'f', -1, 64)
+			switch i {
+			case 0:
+			case 1:
+				s += "L"
+			default:
+				s += "L^" + strconv.Itoa(i)
+			}
+			terms = append(terms, s)
+		}
+		if len(terms) > 0 {
+			return "polynomial, " + strings.Join(terms, " + ")
+		}
+	}
+	return "default"
+}
+
+// ParseCurve parses a curve type and its coefficients, and checks that it's valid.
+func ParseCurve(typ string, args []string) (c Curve, err error) {
+	c.Type = strings.ToLower(typ)
+
+	for _, arg := range args {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return c, fmt.Errorf("`%v` is not a valid number", arg)
+		}
+		c.Coefficients = append(c.Coefficients, f)
+	}
+
+	switch c.Type {
+	case CurveDefault, CurveMEE6:
+		if len(c.Coefficients) != 0 {
+			return c, fmt.Errorf("the %v curve doesn't take any arguments", c.Type)
+		}
+		return c, nil
+	case CurveLinear:
+		if len(c.Coefficients) != 1 || c.Coefficients[0] < 1 {
+			return c, fmt.Errorf("the linear curve takes exactly one argument, the XP per level (at least 1)")
+		}
+		return c, nil
+	case CurvePolynomial:
+		if len(c.Coefficients) < 2 || len(c.Coefficients) > 5 {
+			return c, fmt.Errorf("the polynomial curve takes between 2 and 5 coefficients, starting with the constant term")
+		}
+		// non-negative coefficients keep the curve increasing past the levels checked below
+		for _, coeff := range c.Coefficients[1:] {
+			if coeff < 0 {
+				return c, fmt.Errorf("only the constant term of a polynomial curve can be negative")
+			}
+		}
+	default:
+		return c, fmt.Errorf("`%v` is not a valid curve (valid curves are: default, mee6, linear, polynomial)", typ)
+	}
+
+	// make sure every level needs more XP than the last one
+	prev := c.XP(0)
+	for lvl := int64(1); lvl <= 1000; lvl++ {
+		xp := c.XP(lvl)
+		if xp <= prev {
+			return c, fmt.Errorf("the curve must need more XP for every level (level %v needs %v XP, level %v needs %v XP)", lvl-1, prev, lvl, xp)
+		}
+		prev = xp
+	}
+	return c, nil
+}
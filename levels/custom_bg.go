@@ -0,0 +1,298 @@
+package levels
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/disintegration/imaging"
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// Background statuses
+const (
+	BackgroundPending  = "pending"
+	BackgroundApproved = "approved"
+)
+
+const (
+	// the largest image that can be uploaded as a background
+	maxBackgroundSize = 8 * 1024 * 1024
+	// the largest dimensions an uploaded image can have, so huge images can't eat all our memory when decoded
+	maxBackgroundDimension = 4096
+)
+
+// canUploadBackground returns true if the member meets the server's requirements for uploading a background.
+// If both a level and a role are required, either is enough.
+func canUploadBackground(gc GuildConfig, lvl int64, m *discord.Member) bool {
+	if gc.CustomBgLevel <= 0 && !gc.CustomBgRole.IsValid() {
+		return true
+	}
+
+	if gc.CustomBgLevel > 0 && lvl >= gc.CustomBgLevel {
+		return true
+	}
+
+	if gc.CustomBgRole.IsValid() && m != nil {
+		for _, r := range m.RoleIDs {
+			if r == gc.CustomBgRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func uploadRequirement(gc GuildConfig) string {
+	switch {
+	case gc.CustomBgLevel > 0 && gc.CustomBgRole.IsValid():
+		return fmt.Sprintf("reach level %v or have the %v role", gc.CustomBgLevel, gc.CustomBgRole.Mention())
+	case gc.CustomBgLevel > 0:
+		return fmt.Sprintf("reach level %v", gc.CustomBgLevel)
+	default:
+		return fmt.Sprintf("have the %v role", gc.CustomBgRole.Mention())
+	}
+}
+
+// processBackground validates an uploaded image, and crops and resizes it to fit a rank card.
+func processBackground(r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxBackgroundSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxBackgroundSize {
+		return nil, fmt.Errorf("the image is too big (maximum %v)", humanize.Bytes(maxBackgroundSize))
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, fmt.Errorf("the image must be a PNG or JPEG file")
+	}
+
+	if cfg.Width > maxBackgroundDimension || cfg.Height > maxBackgroundDimension {
+		return nil, fmt.Errorf("the image is too large (maximum %vx%v pixels)", maxBackgroundDimension, maxBackgroundDimension)
+	}
+	if cfg.Width < width/2 || cfg.Height < height/2 {
+		return nil, fmt.Errorf("the image is too small (minimum %vx%v pixels, ideally %vx%v)", width/2, height/2, width, height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("the image couldn't be read")
+	}
+
+	img = imaging.Fill(img, width, height, imaging.Center, imaging.Lanczos)
+
+	buf := new(bytes.Buffer)
+	err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 90})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (bot *Bot) uploadBackground(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Message.GuildID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if !gc.BgApprovalChannel.IsValid() {
+		return ctx.SendX("Custom backgrounds aren't enabled in this server.")
+	}
+
+	uc, err := bot.getUser(ctx.Message.GuildID, ctx.Author.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if !canUploadBackground(gc, gc.Curve().Level(uc.XP), ctx.Member) {
+		return ctx.SendfX("You need to %v to upload your own background.", uploadRequirement(gc))
+	}
+
+	if len(ctx.Message.Attachments) == 0 {
+		return ctx.SendfX("You must attach an image to use as your background! It'll be cropped to %vx%v pixels.", width, height)
+	}
+
+	att := ctx.Message.Attachments[0]
+	if att.Size > maxBackgroundSize {
+		return ctx.SendfX("That image is too big (maximum %v).", humanize.Bytes(maxBackgroundSize))
+	}
+
+	resp, err := http.Get(att.URL)
+	if err != nil {
+		bot.SendError("Error downloading background: %v", err)
+		return bot.Report(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	blob, err := processBackground(resp.Body)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "I couldn't use that image: %v", err)
+		return err
+	}
+
+	// only keep one pending background per user
+	_, err = bot.DB.Exec(context.Background(), "delete from level_backgrounds where owner_id = $1 and status = $2", ctx.Author.ID, BackgroundPending)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	var id int64
+	err = bot.DB.QueryRow(context.Background(), `insert into level_backgrounds
+	(name, source, blob, emoji_name, owner_id, status) values ($1, $2, $3, $4, $5, $6) returning id`,
+		fmt.Sprintf("custom-%v-%v", ctx.Author.ID, time.Now().Unix()), "Uploaded by "+ctx.Author.Tag(), blob, "🖼️", ctx.Author.ID, BackgroundPending,
+	).Scan(&id)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessageComplex(gc.BgApprovalChannel, api.SendMessageData{
+		Embeds: []discord.Embed{{
+			Title:       fmt.Sprintf("Background #%v", id),
+			Description: fmt.Sprintf("%v (%v) uploaded a rank card background.", ctx.Author.Mention(), ctx.Author.Tag()),
+			Image:       &discord.EmbedImage{URL: "attachment://background.jpg"},
+			Color:       bot.Colour,
+			Timestamp:   discord.NowTimestamp(),
+		}},
+		Files: []sendpart.File{{
+			Name:   "background.jpg",
+			Reader: bytes.NewReader(blob),
+		}},
+		Components: discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Label:    "Approve",
+					Style:    discord.SuccessButtonStyle(),
+					CustomID: discord.ComponentID(fmt.Sprintf("levelbg-approve:%v", id)),
+				},
+				&discord.ButtonComponent{
+					Label:    "Reject",
+					Style:    discord.DangerButtonStyle(),
+					CustomID: discord.ComponentID(fmt.Sprintf("levelbg-reject:%v", id)),
+				},
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		bot.SendError("Error sending background to approval queue: %v", err)
+		return ctx.SendX("I couldn't send your background to the staff for approval, please let them know!")
+	}
+
+	return ctx.SendX("Your background was sent to the staff for approval! It'll be used as soon as it's approved.")
+}
+
+// reviewBackground handles the approve and reject buttons in the background approval queue.
+func (bot *Bot) reviewBackground(ev *gateway.InteractionCreateEvent) {
+	data, ok := ev.Data.(*discord.ButtonInteraction)
+	if !ok {
+		return
+	}
+
+	var approve bool
+	var idStr string
+	switch {
+	case strings.HasPrefix(string(data.CustomID), "levelbg-approve:"):
+		approve = true
+		idStr = strings.TrimPrefix(string(data.CustomID), "levelbg-approve:")
+	case strings.HasPrefix(string(data.CustomID), "levelbg-reject:"):
+		idStr = strings.TrimPrefix(string(data.CustomID), "levelbg-reject:")
+	default:
+		return
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return
+	}
+
+	if ev.Member == nil || bot.DB.Perms.Level(ev.Member) < bot.DB.Overrides.For("levelcfg") {
+		bot.respond(ev, "You're not allowed to review backgrounds.")
+		return
+	}
+
+	var ownerID discord.UserID
+	if approve {
+		err = bot.DB.QueryRow(context.Background(), "update level_backgrounds set status = $2 where id = $1 and status = $3 returning owner_id", id, BackgroundApproved, BackgroundPending).Scan(&ownerID)
+	} else {
+		err = bot.DB.QueryRow(context.Background(), "delete from level_backgrounds where id = $1 and status = $2 returning owner_id", id, BackgroundPending).Scan(&ownerID)
+	}
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			bot.respond(ev, "That background was already reviewed, or was replaced by a newer upload.")
+			return
+		}
+		common.Log.Errorf("Error reviewing background %v: %v", id, err)
+		bot.respond(ev, "Internal error occurred!")
+		return
+	}
+
+	s, _ := bot.Router.StateFromGuildID(ev.GuildID)
+
+	dm := "Your rank card background was rejected by the staff."
+	status := fmt.Sprintf("Rejected by %v", ev.Member.User.Mention())
+	if approve {
+		dm = "Your rank card background was approved, and will now be used on your rank card!"
+		status = fmt.Sprintf("Approved by %v", ev.Member.User.Mention())
+
+		// users only get one custom background at a time
+		_, err = bot.DB.Exec(context.Background(), "delete from level_backgrounds where owner_id = $1 and id <> $2", ownerID, id)
+		if err != nil {
+			common.Log.Errorf("Error deleting old backgrounds for %v: %v", ownerID, err)
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update levels set background = $3 where guild_id = $1 and user_id = $2", ev.GuildID, ownerID, id)
+		if err != nil {
+			common.Log.Errorf("Error setting background for %v: %v", ownerID, err)
+		}
+	}
+
+	var embeds []discord.Embed
+	if ev.Message != nil {
+		embeds = ev.Message.Embeds
+	}
+	if len(embeds) > 0 {
+		embeds[0].Fields = append(embeds[0].Fields, discord.EmbedField{Name: "Status", Value: status})
+		if !approve {
+			// the image is deleted, so don't keep showing it
+			embeds[0].Image = nil
+		}
+	}
+
+	err = s.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &embeds,
+			Components: &discord.ContainerComponents{},
+		},
+	})
+	if err != nil {
+		common.Log.Errorf("Error responding to interaction: %v", err)
+	}
+
+	ch, err := s.CreatePrivateChannel(ownerID)
+	if err == nil {
+		_, err = s.SendMessage(ch.ID, dm)
+	}
+	if err != nil {
+		common.Log.Errorf("Error sending background review DM to %v: %v", ownerID, err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"math/rand"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
 	"github.com/starshine-sys/oodles/common"
 )
 
@@ -44,6 +46,35 @@ type GuildConfig struct {
 
 	LevelsEnabled bool `json:"enabled"`
 	DMOnReward    bool `json:"dm_on_reward"`
+
+	MinXP             int64     `json:"min_xp"`
+	MaxXP             int64     `json:"max_xp"`
+	LevelCurve        string    `json:"level_curve"`
+	CurveCoefficients []float64 `json:"curve_coefficients"`
+
+	VoiceXP int64 `json:"voice_xp"`
+
+	LeaderboardChannel discord.ChannelID `json:"leaderboard_channel"`
+
+	StackRewards bool `json:"stack_rewards"`
+
+	CustomBgLevel     int64             `json:"custom_bg_level"`
+	CustomBgRole      discord.RoleID    `json:"custom_bg_role"`
+	BgApprovalChannel discord.ChannelID `json:"bg_approval_channel"`
+
+	DecayDays    int64   `json:"decay_days"`
+	DecayPercent bool    `json:"decay_percent"`
+	DecayAmount  float64 `json:"decay_amount"`
+	DecayFloor   int64   `json:"decay_floor"`
+
+	LevelupMode    string            `json:"levelup_mode"`
+	LevelupChannel discord.ChannelID `json:"levelup_channel"`
+	LevelupMessage string            `json:"levelup_message"`
+}
+
+// Curve returns the guild's level curve.
+func (gc GuildConfig) Curve() Curve {
+	return Curve{Type: gc.LevelCurve, Coefficients: gc.CurveCoefficients}
 }
 
 type LevelBackground struct {
@@ -54,6 +85,11 @@ type LevelBackground struct {
 
 	EmojiName string
 	EmojiID   *discord.EmojiID
+
+	// the user who uploaded this background, nil for staff-added backgrounds
+	OwnerID  *discord.UserID
+	Status   string
+	Uploaded time.Time
 }
 
 type UserLevel struct {
@@ -61,9 +97,13 @@ type UserLevel struct {
 	UserID  discord.UserID  `json:"user_id"`
 
 	XP int64 `json:"xp"`
+	// The part of XP gained in voice channels
+	VoiceXP int64 `json:"voice_xp"`
 
 	Colour     discord.Color `json:"colour"`
 	Background *int64        `json:"-"`
+	CardTheme  string        `json:"card_theme"`
+	CardLayout string        `json:"card_layout"`
 
 	LastXP time.Time `json:"-"`
 }
@@ -80,7 +120,10 @@ type Nolevels struct {
 	Expires bool
 	Expiry  time.Time
 
-	LogChannel discord.ChannelID // not in table, used for expiry loop
+	Reason    string
+	Moderator discord.UserID
+	// the scheduled event that removes this entry, if it expires
+	EventID *int64
 }
 
 func (bot *Bot) getGuildConfig(guildID discord.GuildID) (gc GuildConfig, err error) {
@@ -93,10 +136,27 @@ func (bot *Bot) getUser(guildID discord.GuildID, userID discord.UserID) (l UserL
 	return l, err
 }
 
-func (bot *Bot) incrementXP(guildID discord.GuildID, userID discord.UserID) (newXP int64, err error) {
-	xp := 15 + rand.Intn(12)
+func (bot *Bot) incrementXP(gc GuildConfig, userID discord.UserID, multiplier float64) (newXP int64, err error) {
+	xp := gc.MinXP
+	if gc.MaxXP > gc.MinXP {
+		xp += rand.Int63n(gc.MaxXP - gc.MinXP + 1)
+	}
+	xp = int64(math.Round(float64(xp) * multiplier))
 
-	err = bot.DB.Pool.QueryRow(context.Background(), "update levels set xp = xp + $4, last_xp = $3 where guild_id = $1 and user_id = $2 returning xp", guildID, userID, time.Now().UTC(), xp).Scan(&newXP)
+	err = bot.DB.Pool.QueryRow(context.Background(), "update levels set xp = xp + $4, last_xp = $3 where guild_id = $1 and user_id = $2 returning xp", gc.ID, userID, time.Now().UTC(), xp).Scan(&newXP)
+	if err == nil {
+		bot.logXP(gc.ID, userID, xp, false)
+	}
+	return
+}
+
+// incrementVoiceXP adds the given voice XP to the user's total XP.
+// This doesn't update the user's last_xp, so it doesn't affect the cooldown for message XP.
+func (bot *Bot) incrementVoiceXP(guildID discord.GuildID, userID discord.UserID, xp int64) (newXP int64, err error) {
+	err = bot.DB.Pool.QueryRow(context.Background(), "update levels set xp = xp + $3, voice_xp = voice_xp + $3 where guild_id = $1 and user_id = $2 returning xp", guildID, userID, xp).Scan(&newXP)
+	if err == nil {
+		bot.logXP(guildID, userID, xp, true)
+	}
 	return
 }
 
@@ -123,35 +183,57 @@ func (bot *Bot) getAllRewards(guildID discord.GuildID) (rwds []LevelReward, err
 	return
 }
 
-func (bot *Bot) getLeaderboard(guildID discord.GuildID, full bool) (lb []UserLevel, err error) {
-	err = pgxscan.Select(context.Background(), bot.DB.Pool, &lb, "select * from levels where guild_id = $1 order by xp desc, user_id asc", guildID)
-	if err != nil || full {
-		return
+// leaderboardMembers returns the IDs of everyone in the guild, to filter the leaderboard to current members.
+// If full is true, it returns nil, and the leaderboard isn't filtered.
+func (bot *Bot) leaderboardMembers(guildID discord.GuildID, full bool) ([]int64, error) {
+	if full {
+		return nil, nil
 	}
 
 	s, _ := bot.Router.StateFromGuildID(guildID)
 
 	ms, err := s.Members(guildID)
 	if err != nil {
-		return lb, nil
+		return nil, errors.Wrap(err, "getting guild members")
 	}
 
-	filtered := []UserLevel{}
-
-	for _, l := range lb {
-		for _, m := range ms {
-			if m.User.ID == l.UserID {
-				filtered = append(filtered, l)
-				break
-			}
-		}
+	ids := make([]int64, 0, len(ms))
+	for _, m := range ms {
+		ids = append(ids, int64(m.User.ID))
 	}
+	return ids, nil
+}
 
-	return filtered, nil
+// leaderboardCount returns the number of users on the leaderboard.
+func (bot *Bot) leaderboardCount(guildID discord.GuildID, members []int64) (count int, err error) {
+	err = bot.DB.QueryRow(context.Background(), "select count(*) from levels where guild_id = $1 and ($2::bigint[] is null or user_id = any($2))", guildID, members).Scan(&count)
+	return
+}
+
+// getLeaderboardPage returns a page of the leaderboard, ordered by XP.
+func (bot *Bot) getLeaderboardPage(guildID discord.GuildID, members []int64, offset, limit int) (lb []UserLevel, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB.Pool, &lb, `select * from levels
+	where guild_id = $1 and ($2::bigint[] is null or user_id = any($2))
+	order by xp desc, user_id asc offset $3 limit $4`, guildID, members, offset, limit)
+	return
+}
+
+// userRank returns the user's position on the leaderboard, or 0 if they're not on it.
+func (bot *Bot) userRank(guildID discord.GuildID, userID discord.UserID, members []int64) (rank int, err error) {
+	err = bot.DB.QueryRow(context.Background(), `select 1 + (
+		select count(*) from levels l where l.guild_id = $1
+		and (l.xp > u.xp or (l.xp = u.xp and l.user_id < u.user_id))
+		and ($3::bigint[] is null or l.user_id = any($3))
+	) from levels u where u.guild_id = $1 and u.user_id = $2
+	and ($3::bigint[] is null or u.user_id = any($3))`, guildID, userID, members).Scan(&rank)
+	if errors.Cause(err) == pgx.ErrNoRows {
+		return 0, nil
+	}
+	return
 }
 
 func (bot *Bot) isBlacklisted(guildID discord.GuildID, userID discord.UserID) (blacklisted bool) {
-	err := bot.DB.Pool.QueryRow(context.Background(), "select exists(select user_id from nolevels where guild_id = $1 and user_id = $2)", guildID, userID).Scan(&blacklisted)
+	err := bot.DB.Pool.QueryRow(context.Background(), "select exists(select user_id from nolevels where guild_id = $1 and user_id = $2 and (not expires or expiry > $3))", guildID, userID, time.Now().UTC()).Scan(&blacklisted)
 	if err != nil {
 		common.Log.Errorf("Error checking if user is blacklisted from levels: %v", err)
 	}
@@ -162,7 +244,7 @@ func (bot *Bot) isBlacklisted(guildID discord.GuildID, userID discord.UserID) (b
 // getBackground gets the user's background, or a random background if they have none set, as []byte.
 func (bot *Bot) getBackground(id *int64) (blob []byte) {
 	if id != nil {
-		err := bot.DB.QueryRow(context.Background(), "select blob from level_backgrounds where id = $1", id).Scan(&blob)
+		err := bot.DB.QueryRow(context.Background(), "select blob from level_backgrounds where id = $1 and status = 'approved'", id).Scan(&blob)
 		if err != nil {
 			common.Log.Errorf("Error getting background ID %v: %v", id, err)
 			return nil
@@ -172,7 +254,7 @@ func (bot *Bot) getBackground(id *int64) (blob []byte) {
 
 	// get random background
 	var lbs []LevelBackground
-	err := pgxscan.Select(context.Background(), bot.DB, &lbs, "select * from level_backgrounds")
+	err := pgxscan.Select(context.Background(), bot.DB, &lbs, "select * from level_backgrounds where owner_id is null and status = 'approved'")
 	if err != nil {
 		common.Log.Errorf("Error getting backgrounds: %v", err)
 		return nil
@@ -189,17 +271,17 @@ func (bot *Bot) getBackground(id *int64) (blob []byte) {
 }
 
 func (bot *Bot) backgroundMetadata() (lbs []LevelBackground, err error) {
-	err = pgxscan.Select(context.Background(), bot.DB, &lbs, "select id, name, source, emoji_name, emoji_id from level_backgrounds")
+	err = pgxscan.Select(context.Background(), bot.DB, &lbs, "select id, name, source, emoji_name, emoji_id from level_backgrounds where owner_id is null and status = 'approved' order by id")
 	return
 }
 
 func (bot *Bot) bgExists(id int64) (exists bool) {
-	err := bot.DB.QueryRow(context.Background(), "select exists(select id from level_backgrounds where id = $1)", id).Scan(&exists)
+	err := bot.DB.QueryRow(context.Background(), "select exists(select id from level_backgrounds where id = $1 and owner_id is null and status = 'approved')", id).Scan(&exists)
 	if err != nil {
 		common.Log.Errorf("Error getting background ID %v: %v", id, err)
 		return false
 	}
-	return true
+	return exists
 }
 
 func (bot *Bot) background(id int64) (lb LevelBackground, err error) {
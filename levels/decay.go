@@ -0,0 +1,283 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/dustin/go-humanize"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/starshine-sys/bcr"
+	botpkg "github.com/starshine-sys/oodles/bot"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// XPDecay is a single decay event, recorded so it can be reversed.
+type XPDecay struct {
+	ID        int64
+	GuildID   discord.GuildID
+	UserID    discord.UserID
+	XPBefore  int64
+	XPAfter   int64
+	DecayedAt time.Time
+	Reversed  bool
+}
+
+func decayString(gc GuildConfig) string {
+	if gc.DecayDays <= 0 || gc.DecayAmount <= 0 {
+		return "disabled"
+	}
+
+	amount := humanize.Comma(int64(gc.DecayAmount)) + " XP"
+	if gc.DecayPercent {
+		amount = strconv.FormatFloat(gc.DecayAmount, 'f', -1, 64) + "%"
+	}
+
+	s := fmt.Sprintf("%v per day after %v days without XP", amount, gc.DecayDays)
+	if gc.DecayFloor > 0 {
+		s += fmt.Sprintf(", down to %v XP", humanize.Comma(gc.DecayFloor))
+	}
+	return s
+}
+
+// parseDecayAmount parses either a percentage ("5%") or a fixed amount of XP ("50").
+func parseDecayAmount(s string) (amount float64, percent bool, err error) {
+	if strings.HasSuffix(s, "%") {
+		amount, err = strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || amount <= 0 || amount > 100 {
+			return 0, false, fmt.Errorf("a percentage must be between 0 and 100")
+		}
+		return amount, true, nil
+	}
+
+	xp, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || xp <= 0 {
+		return 0, false, fmt.Errorf("must be a percentage (like `5%%`) or an amount of XP")
+	}
+	return float64(xp), false, nil
+}
+
+// scheduleDecay (re)schedules the daily decay job, or removes it if decay is disabled.
+func (bot *Bot) scheduleDecay(gc GuildConfig) error {
+	// only ever keep one decay event around
+	_, err := bot.DB.Exec(context.Background(), "delete from scheduled_events where event_type = 'levels.xpDecay' and (data->>'guild_id')::bigint = $1", gc.ID)
+	if err != nil || gc.DecayDays <= 0 || gc.DecayAmount <= 0 {
+		return err
+	}
+
+	_, err = bot.Scheduler.Add(time.Now().UTC().Truncate(day).Add(day), &xpDecay{GuildID: gc.ID})
+	return err
+}
+
+// decayXP takes XP from everyone who hasn't earned any in the configured number of days.
+// Activity is the latest of the last message XP and the last day with any XP (which includes voice XP).
+func (bot *Bot) decayXP(gc GuildConfig) (decays []XPDecay, err error) {
+	inactiveSince := time.Now().UTC().Add(-time.Duration(gc.DecayDays) * day)
+
+	tx, err := bot.DB.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	err = pgxscan.Select(context.Background(), tx, &decays, `with d as (
+		select l.user_id, l.xp as xp_before,
+		greatest($2, case when $3 then l.xp - ceil(l.xp * $4::float8 / 100)::bigint else l.xp - $4::float8::bigint end) as xp_after
+		from levels l
+		where l.guild_id = $1 and l.xp > $2 and l.last_xp < $5
+		and not exists (select 1 from xp_history h where h.guild_id = $1 and h.user_id = l.user_id and h.day >= $5::date)
+	), updated as (
+		update levels l set xp = d.xp_after, voice_xp = least(l.voice_xp, d.xp_after) from d
+		where l.guild_id = $1 and l.user_id = d.user_id
+		returning d.user_id, d.xp_before, d.xp_after
+	)
+	insert into xp_decay_log (guild_id, user_id, xp_before, xp_after)
+	select $1, user_id, xp_before, xp_after from updated
+	returning *`, gc.ID, gc.DecayFloor, gc.DecayPercent, gc.DecayAmount, inactiveSince)
+	if err != nil {
+		return nil, err
+	}
+
+	return decays, tx.Commit(context.Background())
+}
+
+// removeDecayedRewards removes reward roles from members whose level dropped below the reward's level.
+func (bot *Bot) removeDecayedRewards(gc GuildConfig, decays []XPDecay) {
+	rewards, err := bot.getAllRewards(gc.ID)
+	if err != nil {
+		common.Log.Errorf("Error getting rewards: %v", err)
+		return
+	}
+	if len(rewards) == 0 {
+		return
+	}
+
+	s, _ := bot.Router.StateFromGuildID(gc.ID)
+	curve := gc.Curve()
+
+	for _, d := range decays {
+		oldLvl, newLvl := curve.Level(d.XPBefore), curve.Level(d.XPAfter)
+		if newLvl >= oldLvl {
+			continue
+		}
+
+		m, err := s.Member(gc.ID, d.UserID)
+		if err != nil {
+			continue
+		}
+
+		wanted := rewardRoles(rewards, newLvl, gc.StackRewards)
+		for _, r := range rewards {
+			if r.Level <= newLvl || hasRole(wanted, r.RoleReward) || !hasRole(m.RoleIDs, r.RoleReward) {
+				continue
+			}
+
+			err = s.RemoveRole(gc.ID, d.UserID, r.RoleReward, api.AuditLogReason(fmt.Sprintf("XP decay (now level %v)", newLvl)))
+			if err != nil {
+				common.Log.Errorf("Error removing reward role %v from %v: %v", r.RoleReward, d.UserID, err)
+			}
+		}
+	}
+}
+
+// xpDecay runs XP decay once a day.
+type xpDecay struct {
+	GuildID discord.GuildID `json:"guild_id"`
+}
+
+func (dat *xpDecay) Execute(ctx context.Context, id int64, bot *botpkg.Bot) error {
+	b := &Bot{bot}
+
+	gc, err := b.getGuildConfig(dat.GuildID)
+	if err != nil {
+		common.Log.Errorf("Error getting guild config: %v", err)
+		return botpkg.Reschedule
+	}
+
+	if gc.DecayDays <= 0 || gc.DecayAmount <= 0 {
+		return nil
+	}
+	if !gc.LevelsEnabled {
+		return botpkg.Reschedule
+	}
+
+	decays, err := b.decayXP(gc)
+	if err != nil {
+		common.Log.Errorf("Error decaying XP in %v: %v", dat.GuildID, err)
+		return botpkg.Reschedule
+	}
+
+	if len(decays) > 0 {
+		common.Log.Infof("Decayed XP for %v members in %v", len(decays), dat.GuildID)
+		b.removeDecayedRewards(gc, decays)
+	}
+	return botpkg.Reschedule
+}
+
+func (dat *xpDecay) Offset() time.Duration { return day }
+
+func (bot *Bot) decayLog(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	var decays []XPDecay
+	if len(ctx.Args) > 0 {
+		var u *discord.User
+		u, err = ctx.ParseUser(strings.Join(ctx.Args, " "))
+		if err != nil {
+			_, err = ctx.Send("User not found.")
+			return err
+		}
+
+		err = pgxscan.Select(context.Background(), bot.DB, &decays, "select * from xp_decay_log where guild_id = $1 and user_id = $2 order by id desc limit 500", ctx.Guild.ID, u.ID)
+	} else {
+		err = pgxscan.Select(context.Background(), bot.DB, &decays, "select * from xp_decay_log where guild_id = $1 order by id desc limit 500", ctx.Guild.ID)
+	}
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(decays) == 0 {
+		return ctx.SendfX("No XP has decayed yet.\n**Decay:** %v", decayString(gc))
+	}
+
+	var lines []string
+	for _, d := range decays {
+		s := fmt.Sprintf("`#%v` %v: `%v` → `%v` XP, <t:%v:d>", d.ID, d.UserID.Mention(), humanize.Comma(d.XPBefore), humanize.Comma(d.XPAfter), d.DecayedAt.Unix())
+		if d.Reversed {
+			s = "~~" + s + "~~ (reversed)"
+		}
+		lines = append(lines, s+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("XP decay log", bot.Colour, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}
+
+// decayReverse gives back the XP taken by a decay event, or by all of a user's decay events.
+func (bot *Bot) decayReverse(ctx *bcr.Context) (err error) {
+	tx, err := bot.DB.Begin(context.Background())
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	defer tx.Rollback(context.Background())
+
+	var decays []XPDecay
+	if id, err := strconv.ParseInt(strings.TrimPrefix(ctx.Args[0], "#"), 10, 64); err == nil && id < math.MaxInt32 {
+		err = pgxscan.Select(context.Background(), tx, &decays, "update xp_decay_log set reversed = true where guild_id = $1 and id = $2 and not reversed returning *", ctx.Guild.ID, id)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	} else {
+		u, err := ctx.ParseUser(strings.Join(ctx.Args, " "))
+		if err != nil {
+			_, err = ctx.Send("User not found.")
+			return err
+		}
+
+		err = pgxscan.Select(context.Background(), tx, &decays, "update xp_decay_log set reversed = true where guild_id = $1 and user_id = $2 and not reversed returning *", ctx.Guild.ID, u.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	}
+
+	if len(decays) == 0 {
+		return ctx.SendX("There's nothing to reverse.")
+	}
+
+	var total int64
+	users := map[discord.UserID]int64{}
+	for _, d := range decays {
+		users[d.UserID] += d.XPBefore - d.XPAfter
+		total += d.XPBefore - d.XPAfter
+	}
+
+	for userID, xp := range users {
+		_, err = tx.Exec(context.Background(), "update levels set xp = xp + $3 where guild_id = $1 and user_id = $2", ctx.Guild.ID, userID, xp)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		_, err = tx.Exec(context.Background(), "insert into xp_adjustments (guild_id, user_id, xp, reason) values ($1, $2, $3, 'decay reversal')", ctx.Guild.ID, userID, xp)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("Reversed %v decay events, giving back %v XP to %v members.\nUse `%vlevelcfg reward sync` to give back any reward roles they lost.", len(decays), humanize.Comma(total), len(users), bot.Prefix())
+}
@@ -0,0 +1,433 @@
+package levels
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"codeberg.org/eviedelta/detctime/durationparser"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/dustin/go-humanize"
+	"github.com/fogleman/gg"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/starshine-sys/bcr"
+	botpkg "github.com/starshine-sys/oodles/bot"
+	"github.com/starshine-sys/oodles/common"
+)
+
+const day = 24 * time.Hour
+
+// PeriodXP is the XP a user gained in a period of time.
+type PeriodXP struct {
+	UserID discord.UserID
+	XP     int64
+}
+
+// XPDay is the XP a user gained on a single day.
+type XPDay struct {
+	Day     time.Time
+	XP      int64
+	VoiceXP int64
+}
+
+// logXP adds the given XP to the user's history for today.
+func (bot *Bot) logXP(guildID discord.GuildID, userID discord.UserID, xp int64, voice bool) {
+	var voiceXP int64
+	if voice {
+		voiceXP = xp
+	}
+
+	_, err := bot.DB.Exec(context.Background(), `insert into xp_history
+	(guild_id, user_id, day, xp, voice_xp) values ($1, $2, $3, $4, $5)
+	on conflict (guild_id, user_id, day) do update
+	set xp = xp_history.xp + $4, voice_xp = xp_history.voice_xp + $5`, guildID, userID, time.Now().UTC().Truncate(day), xp, voiceXP)
+	if err != nil {
+		common.Log.Errorf("Error logging XP for %v: %v", userID, err)
+	}
+}
+
+// periodLeaderboard returns the XP gained by each user between from (inclusive) and to (exclusive).
+func (bot *Bot) periodLeaderboard(guildID discord.GuildID, from, to time.Time) (lb []PeriodXP, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB, &lb, `select user_id, sum(xp) as xp from xp_history
+	where guild_id = $1 and day >= $2 and day < $3
+	group by user_id order by xp desc, user_id asc`, guildID, from, to)
+	return
+}
+
+// improvedLeaderboard returns how much more XP each user gained between from and to,
+// compared to the period of the same length right before it.
+func (bot *Bot) improvedLeaderboard(guildID discord.GuildID, from, to time.Time) (lb []PeriodXP, err error) {
+	prev := from.Add(-to.Sub(from))
+
+	err = pgxscan.Select(context.Background(), bot.DB, &lb, `select user_id,
+	sum(case when day >= $2 then xp else 0 end) - sum(case when day < $2 then xp else 0 end) as xp
+	from xp_history where guild_id = $1 and day >= $3 and day < $4
+	group by user_id having sum(case when day >= $2 then xp else 0 end) > sum(case when day < $2 then xp else 0 end)
+	order by xp desc, user_id asc`, guildID, from, prev, to)
+	return
+}
+
+// userHistory returns the user's XP per day since the given time.
+func (bot *Bot) userHistory(guildID discord.GuildID, userID discord.UserID, since time.Time) (days []XPDay, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB, &days, `select day, xp, voice_xp from xp_history
+	where guild_id = $1 and user_id = $2 and day >= $3 order by day asc`, guildID, userID, since)
+	return
+}
+
+// userAdjustments returns the user's net XP changes per day since the given time that aren't logged in xp_history,
+// such as decay, decay reversals, and imports.
+func (bot *Bot) userAdjustments(guildID discord.GuildID, userID discord.UserID, since time.Time) (days []XPDay, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB, &days, `select day, sum(xp)::bigint as xp from (
+		select created::date as day, xp from xp_adjustments where guild_id = $1 and user_id = $2 and created >= $3
+		union all
+		select decayed_at::date as day, xp_after - xp_before as xp from xp_decay_log where guild_id = $1 and user_id = $2 and decayed_at >= $3
+	) a group by day order by day asc`, guildID, userID, since)
+	return
+}
+
+// filterPeriodMembers removes users who aren't in the server anymore, like the leaderboard command.
+func (bot *Bot) filterPeriodMembers(guildID discord.GuildID, lb []PeriodXP) []PeriodXP {
+	s, _ := bot.Router.StateFromGuildID(guildID)
+
+	ms, err := s.Members(guildID)
+	if err != nil {
+		return lb
+	}
+
+	inGuild := make(map[discord.UserID]struct{}, len(ms))
+	for _, m := range ms {
+		inGuild[m.User.ID] = struct{}{}
+	}
+
+	filtered := []PeriodXP{}
+	for _, l := range lb {
+		if _, ok := inGuild[l.UserID]; ok {
+			filtered = append(filtered, l)
+		}
+	}
+	return filtered
+}
+
+// parsePeriod parses the period flags of the leaderboard command.
+// since is either a date (YYYY-MM-DD) or a duration before now, until is a date.
+func parsePeriod(weekly, monthly bool, since, until string) (from, to time.Time, ok bool, err error) {
+	now := time.Now().UTC()
+	to = now.Truncate(day).Add(day)
+
+	switch {
+	case since != "":
+		if t, err := time.Parse("2006-01-02", since); err == nil {
+			from = t
+		} else {
+			dur, err := durationparser.Parse(since)
+			if err != nil {
+				return from, to, false, fmt.Errorf("I couldn't parse `%v` as a date (YYYY-MM-DD) or duration", since)
+			}
+			from = now.Add(-dur).Truncate(day)
+		}
+	case monthly:
+		from = now.Truncate(day).AddDate(0, -1, 0).Add(day)
+	case weekly:
+		from = now.Truncate(day).Add(-6 * day)
+	default:
+		if until == "" {
+			return from, to, false, nil
+		}
+		return from, to, false, fmt.Errorf("`--until` can only be used with `--since`")
+	}
+
+	if until != "" {
+		t, err := time.Parse("2006-01-02", until)
+		if err != nil {
+			return from, to, false, fmt.Errorf("I couldn't parse `%v` as a date (YYYY-MM-DD)", until)
+		}
+		// include the given day
+		to = t.Add(day)
+	}
+
+	if !from.Before(to) {
+		return from, to, false, fmt.Errorf("the start of the period must be before the end")
+	}
+	return from, to, true, nil
+}
+
+func periodString(from, to time.Time) string {
+	return fmt.Sprintf("%v to %v", from.Format("2006-01-02"), to.Add(-day).Format("2006-01-02"))
+}
+
+func (bot *Bot) periodLeaderboardCmd(ctx *bcr.Context, from, to time.Time, improved, full bool) (err error) {
+	var lb []PeriodXP
+	if improved {
+		lb, err = bot.improvedLeaderboard(ctx.Message.GuildID, from, to)
+	} else {
+		lb, err = bot.periodLeaderboard(ctx.Message.GuildID, from, to)
+	}
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if !full {
+		lb = bot.filterPeriodMembers(ctx.Message.GuildID, lb)
+	}
+
+	if len(lb) == 0 {
+		_, err = ctx.Sendf("There doesn't seem to be anyone on the leaderboard for that period...")
+		return
+	}
+
+	var strings []string
+	for i, l := range lb {
+		if improved {
+			strings = append(strings, fmt.Sprintf("%v. %v: +`%v` XP\n", i+1, l.UserID.Mention(), humanize.Comma(l.XP)))
+		} else {
+			strings = append(strings, fmt.Sprintf("%v. %v: `%v` XP\n", i+1, l.UserID.Mention(), humanize.Comma(l.XP)))
+		}
+	}
+
+	name := "Leaderboard for " + ctx.Guild.Name
+	if improved {
+		name = "Most improved in " + ctx.Guild.Name
+	}
+	name += " (" + periodString(from, to) + ")"
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator(name, bot.Colour, strings, 15),
+		10*time.Minute,
+	)
+	return err
+}
+
+// scheduleWeeklyLeaderboard (re)schedules the weekly leaderboard post for next Monday.
+func (bot *Bot) scheduleWeeklyLeaderboard(guildID discord.GuildID) error {
+	// only ever keep one weekly leaderboard event around
+	_, err := bot.DB.Exec(context.Background(), "delete from scheduled_events where event_type = 'levels.weeklyLeaderboard' and (data->>'guild_id')::bigint = $1", guildID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(day)
+	next := now.Add(time.Duration((8-int(now.Weekday()))%7) * day)
+	if !next.After(time.Now()) {
+		next = next.Add(7 * day)
+	}
+
+	_, err = bot.Scheduler.Add(next, &weeklyLeaderboard{GuildID: guildID})
+	return err
+}
+
+// weeklyLeaderboard posts the past week's leaderboard in the configured channel.
+type weeklyLeaderboard struct {
+	GuildID discord.GuildID `json:"guild_id"`
+}
+
+func (dat *weeklyLeaderboard) Execute(ctx context.Context, id int64, bot *botpkg.Bot) error {
+	b := &Bot{bot}
+
+	gc, err := b.getGuildConfig(dat.GuildID)
+	if err != nil {
+		common.Log.Errorf("Error getting guild config: %v", err)
+		return botpkg.Reschedule
+	}
+
+	if !gc.LevelsEnabled || !gc.LeaderboardChannel.IsValid() {
+		return nil
+	}
+
+	to := time.Now().UTC().Truncate(day)
+	from := to.Add(-7 * day)
+
+	lb, err := b.periodLeaderboard(dat.GuildID, from, to)
+	if err != nil {
+		common.Log.Errorf("Error getting weekly leaderboard: %v", err)
+		return botpkg.Reschedule
+	}
+	improved, err := b.improvedLeaderboard(dat.GuildID, from, to)
+	if err != nil {
+		common.Log.Errorf("Error getting most improved leaderboard: %v", err)
+		return botpkg.Reschedule
+	}
+
+	lb = b.filterPeriodMembers(dat.GuildID, lb)
+	improved = b.filterPeriodMembers(dat.GuildID, improved)
+
+	e := discord.Embed{
+		Title:     "Weekly leaderboard",
+		Color:     bot.Colour,
+		Footer:    &discord.EmbedFooter{Text: periodString(from, to)},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if len(lb) == 0 {
+		e.Description = "Nobody gained any XP this week..."
+	}
+	for i, l := range lb {
+		if i >= 10 {
+			break
+		}
+		e.Description += fmt.Sprintf("%v. %v: `%v` XP\n", i+1, l.UserID.Mention(), humanize.Comma(l.XP))
+	}
+
+	if len(improved) > 0 {
+		f := discord.EmbedField{Name: "Most improved"}
+		for i, l := range improved {
+			if i >= 5 {
+				break
+			}
+			f.Value += fmt.Sprintf("%v. %v: +`%v` XP\n", i+1, l.UserID.Mention(), humanize.Comma(l.XP))
+		}
+		e.Fields = append(e.Fields, f)
+	}
+
+	s, _ := bot.Router.StateFromGuildID(dat.GuildID)
+
+	_, err = s.SendMessageComplex(gc.LeaderboardChannel, api.SendMessageData{
+		Embeds: []discord.Embed{e},
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		common.Log.Errorf("Error sending weekly leaderboard: %v", err)
+	}
+
+	return botpkg.Reschedule
+}
+
+func (dat *weeklyLeaderboard) Offset() time.Duration { return 7 * day }
+
+func (bot *Bot) xpChart(ctx *bcr.Context) (err error) {
+	days, _ := ctx.Flags.GetInt("days")
+	if days < 2 || days > 365 {
+		return ctx.SendX("The number of days must be between 2 and 365.")
+	}
+
+	u := &ctx.Author
+	if len(ctx.Args) > 0 {
+		u, err = ctx.ParseUser(strings.Join(ctx.Args, " "))
+		if err != nil {
+			_, err = ctx.Send("User not found.")
+			return
+		}
+	}
+
+	uc, err := bot.getUser(ctx.Message.GuildID, u.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	since := time.Now().UTC().Truncate(day).Add(-time.Duration(days-1) * day)
+	history, err := bot.userHistory(ctx.Message.GuildID, u.ID, since)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	adjustments, err := bot.userAdjustments(ctx.Message.GuildID, u.ID, since)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	// total XP at the end of each day, working backwards from the current total
+	daily := make(map[time.Time]int64, len(history))
+	for _, d := range append(history, adjustments...) {
+		daily[d.Day.UTC().Truncate(day)] += d.XP
+	}
+
+	totals := make([]int64, days)
+	total := uc.XP
+	for i := days - 1; i >= 0; i-- {
+		totals[i] = total
+		total -= daily[since.Add(time.Duration(i)*day)]
+	}
+
+	clr := uc.Colour
+	if clr == 0 {
+		clr = bot.Colour
+	}
+
+	img, err := drawXPChart(totals, since, clr)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessageComplex(ctx.Message.ChannelID, api.SendMessageData{
+		Content: fmt.Sprintf("XP over the past %v days for **%v**", days, u.Username),
+		Files: []sendpart.File{{
+			Name:   "xp_chart.png",
+			Reader: img,
+		}},
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
+	})
+	return
+}
+
+const (
+	chartWidth   = 1000
+	chartHeight  = 500
+	chartPadding = 70
+)
+
+// drawXPChart draws a line chart of the given XP totals, one per day.
+func drawXPChart(totals []int64, since time.Time, clr discord.Color) (io.Reader, error) {
+	img := gg.NewContext(chartWidth, chartHeight)
+
+	img.SetHexColor("#2f3136")
+	img.Clear()
+
+	min, max := totals[0], totals[len(totals)-1]
+	for _, t := range totals {
+		min = int64(math.Min(float64(min), float64(t)))
+		max = int64(math.Max(float64(max), float64(t)))
+	}
+	if max == min {
+		max = min + 1
+	}
+
+	plotW := float64(chartWidth - 2*chartPadding)
+	plotH := float64(chartHeight - 2*chartPadding)
+
+	x := func(i int) float64 {
+		return chartPadding + plotW*float64(i)/float64(len(totals)-1)
+	}
+	y := func(xp int64) float64 {
+		return chartHeight - chartPadding - plotH*float64(xp-min)/float64(max-min)
+	}
+
+	// axes
+	img.SetHexColor("#b5b5b5")
+	img.SetLineWidth(2)
+	img.DrawLine(chartPadding, chartPadding, chartPadding, chartHeight-chartPadding)
+	img.DrawLine(chartPadding, chartHeight-chartPadding, chartWidth-chartPadding, chartHeight-chartPadding)
+	img.Stroke()
+
+	// labels
+	img.SetFontFace(smallFont)
+	img.SetHexColor("#ffffff")
+	img.DrawStringAnchored(humanize.Comma(max), chartPadding-10, chartPadding, 1, 0.5)
+	img.DrawStringAnchored(humanize.Comma(min), chartPadding-10, chartHeight-chartPadding, 1, 0.5)
+	img.DrawStringAnchored(since.Format("Jan 2"), chartPadding, chartHeight-chartPadding+30, 0, 0.5)
+	img.DrawStringAnchored(since.Add(time.Duration(len(totals)-1)*day).Format("Jan 2"), chartWidth-chartPadding, chartHeight-chartPadding+30, 1, 0.5)
+
+	// line
+	img.SetHexColor(fmt.Sprintf("#%06x", clr))
+	img.SetLineWidth(4)
+	img.MoveTo(x(0), y(totals[0]))
+	for i := 1; i < len(totals); i++ {
+		img.LineTo(x(i), y(totals[i]))
+	}
+	img.Stroke()
+
+	buf := new(bytes.Buffer)
+	err := img.EncodePNG(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
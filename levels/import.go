@@ -0,0 +1,429 @@
+package levels
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/dustin/go-humanize"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/starshine-sys/bcr"
+)
+
+// the largest file that can be imported
+const maxImportSize = 10 * 1024 * 1024
+
+// Import modes
+const (
+	ImportOverwrite = "overwrite"
+	ImportAdd       = "add"
+	ImportMax       = "max"
+)
+
+// Export is an Oodles level export.
+type Export struct {
+	GuildID  discord.GuildID `json:"guild_id"`
+	Exported time.Time       `json:"exported"`
+	Levels   []UserLevel     `json:"levels"`
+}
+
+type importEntry struct {
+	UserID  discord.UserID
+	XP      int64
+	VoiceXP int64
+}
+
+// genericEntry is a single user in a generic JSON import, or in a MEE6 leaderboard page.
+// IDs can be either strings or numbers.
+type genericEntry struct {
+	UserID json.Number `json:"user_id"`
+	ID     json.Number `json:"id"`
+	// used by the old transferxp script's levels.json
+	UserIDAlt json.Number `json:"userid"`
+	XP        json.Number `json:"xp"`
+}
+
+type mee6Page struct {
+	Players []genericEntry `json:"players"`
+}
+
+// parseImport parses an import file. CSV files are detected by their name, everything else is parsed as JSON.
+func parseImport(name string, b []byte) (entries []importEntry, format string, err error) {
+	if strings.HasSuffix(strings.ToLower(name), ".csv") {
+		entries, err = parseCSVImport(b)
+		return entries, "CSV", err
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, "", fmt.Errorf("the file is empty")
+	}
+
+	// an object is either an Oodles export or a single MEE6 leaderboard page
+	if b[0] == '{' {
+		var obj map[string]json.RawMessage
+		if err = json.Unmarshal(b, &obj); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		if _, ok := obj["players"]; ok {
+			var page mee6Page
+			if err = json.Unmarshal(b, &page); err != nil {
+				return nil, "", fmt.Errorf("invalid MEE6 leaderboard: %w", err)
+			}
+			entries, err = convertGeneric(page.Players)
+			return entries, "MEE6", err
+		}
+
+		if _, ok := obj["levels"]; ok {
+			var ex Export
+			if err = json.Unmarshal(b, &ex); err != nil {
+				return nil, "", fmt.Errorf("invalid Oodles export: %w", err)
+			}
+			for _, l := range ex.Levels {
+				entries = append(entries, importEntry{UserID: l.UserID, XP: l.XP, VoiceXP: l.VoiceXP})
+			}
+			return entries, "Oodles", nil
+		}
+
+		return nil, "", fmt.Errorf("unknown JSON format, expected a MEE6 leaderboard or an Oodles export")
+	}
+
+	// an array is either multiple MEE6 leaderboard pages, or a generic list of users
+	var arr []json.RawMessage
+	if err = json.Unmarshal(b, &arr); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if len(arr) > 0 && bytes.Contains(arr[0], []byte(`"players"`)) {
+		var pages []mee6Page
+		if err = json.Unmarshal(b, &pages); err != nil {
+			return nil, "", fmt.Errorf("invalid MEE6 leaderboard: %w", err)
+		}
+
+		var players []genericEntry
+		for _, p := range pages {
+			players = append(players, p.Players...)
+		}
+		entries, err = convertGeneric(players)
+		return entries, "MEE6", err
+	}
+
+	var generic []genericEntry
+	if err = json.Unmarshal(b, &generic); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	entries, err = convertGeneric(generic)
+	return entries, "JSON", err
+}
+
+func convertGeneric(in []genericEntry) (entries []importEntry, err error) {
+	for i, e := range in {
+		id := e.UserID
+		if id == "" {
+			id = e.UserIDAlt
+		}
+		if id == "" {
+			id = e.ID
+		}
+
+		entry, err := parseImportEntry(id.String(), e.XP.String())
+		if err != nil {
+			return nil, fmt.Errorf("entry %v: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseCSVImport parses a CSV file with user IDs and XP.
+// If the first row is a header, the user_id (or id) and xp columns are used, otherwise the first two columns are.
+func parseCSVImport(b []byte) (entries []importEntry, err error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+
+	idCol, xpCol, voiceCol := 0, 1, -1
+	if _, err := discord.ParseSnowflake(records[0][0]); err != nil {
+		idCol, xpCol = -1, -1
+		for i, col := range records[0] {
+			switch strings.ToLower(strings.TrimSpace(col)) {
+			case "user_id", "userid", "id", "user":
+				idCol = i
+			case "xp", "exp", "experience":
+				xpCol = i
+			case "voice_xp":
+				voiceCol = i
+			}
+		}
+		if idCol == -1 || xpCol == -1 {
+			return nil, fmt.Errorf("the CSV header must have a `user_id` and an `xp` column")
+		}
+		records = records[1:]
+	}
+
+	for i, rec := range records {
+		if len(rec) <= idCol || len(rec) <= xpCol {
+			return nil, fmt.Errorf("row %v: not enough columns", i+1)
+		}
+
+		entry, err := parseImportEntry(rec[idCol], rec[xpCol])
+		if err != nil {
+			return nil, fmt.Errorf("row %v: %w", i+1, err)
+		}
+
+		if voiceCol != -1 && len(rec) > voiceCol && rec[voiceCol] != "" {
+			entry.VoiceXP, err = strconv.ParseInt(strings.TrimSpace(rec[voiceCol]), 10, 64)
+			if err != nil || entry.VoiceXP < 0 {
+				return nil, fmt.Errorf("row %v: `%v` is not a valid amount of voice XP", i+1, rec[voiceCol])
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseImportEntry(id, xp string) (e importEntry, err error) {
+	sf, err := discord.ParseSnowflake(strings.TrimSpace(id))
+	if err != nil || !sf.IsValid() {
+		return e, fmt.Errorf("`%v` is not a valid user ID", id)
+	}
+	e.UserID = discord.UserID(sf)
+
+	e.XP, err = strconv.ParseInt(strings.TrimSpace(xp), 10, 64)
+	if err != nil || e.XP < 0 {
+		return e, fmt.Errorf("`%v` is not a valid amount of XP", xp)
+	}
+	return e, nil
+}
+
+// mergeXP returns the XP a user will have after importing, with the given mode.
+func mergeXP(mode string, old, imported int64) int64 {
+	switch mode {
+	case ImportAdd:
+		return old + imported
+	case ImportMax:
+		if imported > old {
+			return imported
+		}
+		return old
+	default:
+		return imported
+	}
+}
+
+func (bot *Bot) importLevels(ctx *bcr.Context) (err error) {
+	mode, _ := ctx.Flags.GetString("mode")
+	mode = strings.ToLower(mode)
+	if mode != ImportOverwrite && mode != ImportAdd && mode != ImportMax {
+		return ctx.SendfX("`%v` is not a valid mode (valid modes are: overwrite, add, max)", mode)
+	}
+	dryRun, _ := ctx.Flags.GetBool("dry-run")
+
+	if len(ctx.Message.Attachments) == 0 {
+		return ctx.SendX("You must attach a file to import!")
+	}
+	att := ctx.Message.Attachments[0]
+	if att.Size > maxImportSize {
+		return ctx.SendfX("That file is too big (maximum %v).", humanize.Bytes(maxImportSize))
+	}
+
+	resp, err := httpClient.Get(att.URL)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxImportSize))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	entries, format, err := parseImport(att.Filename, b)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "I couldn't read that file: %v", err)
+		return err
+	}
+	if len(entries) == 0 {
+		return ctx.SendX("That file doesn't have any levels in it.")
+	}
+
+	// if a user is in the file more than once, the last entry wins
+	imported := map[discord.UserID]importEntry{}
+	for _, e := range entries {
+		imported[e.UserID] = e
+	}
+
+	var existing []UserLevel
+	err = pgxscan.Select(context.Background(), bot.DB, &existing, "select * from levels where guild_id = $1", ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	current := make(map[discord.UserID]int64, len(existing))
+	for _, l := range existing {
+		current[l.UserID] = l.XP
+	}
+
+	type change struct {
+		UserID   discord.UserID
+		Old, New int64
+	}
+
+	var changes []change
+	var created, unchanged int
+	for _, e := range imported {
+		old, ok := current[e.UserID]
+		if !ok {
+			created++
+		}
+
+		newXP := mergeXP(mode, old, e.XP)
+		if ok && newXP == old {
+			unchanged++
+			continue
+		}
+		changes = append(changes, change{e.UserID, old, newXP})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].New > changes[j].New
+	})
+
+	summary := fmt.Sprintf("**Format:** %v\n**Mode:** %v\n**Users in file:** %v\n**New users:** %v\n**Changed:** %v\n**Unchanged:** %v",
+		format, mode, len(imported), created, len(changes), unchanged)
+
+	if dryRun {
+		e := discord.Embed{
+			Title:       "Import preview",
+			Description: summary,
+			Color:       bot.Colour,
+			Footer: &discord.EmbedFooter{
+				Text: "This was a dry run, nothing was changed.",
+			},
+		}
+
+		if len(changes) > 0 {
+			f := discord.EmbedField{Name: "Top changes"}
+			for i, c := range changes {
+				if i >= 10 {
+					break
+				}
+				f.Value += fmt.Sprintf("%v: `%v` → `%v` XP\n", c.UserID.Mention(), humanize.Comma(c.Old), humanize.Comma(c.New))
+			}
+			e.Fields = append(e.Fields, f)
+		}
+
+		return ctx.SendX("", e)
+	}
+
+	tx, err := bot.DB.Begin(context.Background())
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	defer tx.Rollback(context.Background())
+
+	var sql string
+	switch mode {
+	case ImportOverwrite:
+		sql = "xp = $3, voice_xp = $4"
+	case ImportAdd:
+		sql = "xp = levels.xp + $3, voice_xp = levels.voice_xp + $4"
+	case ImportMax:
+		sql = "xp = greatest(levels.xp, $3), voice_xp = greatest(levels.voice_xp, $4)"
+	}
+
+	for _, e := range imported {
+		_, err = tx.Exec(context.Background(), `insert into levels (guild_id, user_id, xp, voice_xp) values ($1, $2, $3, $4)
+		on conflict (guild_id, user_id) do update set `+sql, ctx.Guild.ID, e.UserID, e.XP, e.VoiceXP)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	}
+
+	for _, c := range changes {
+		_, err = tx.Exec(context.Background(), "insert into xp_adjustments (guild_id, user_id, xp, reason) values ($1, $2, $3, 'import')", ctx.Guild.ID, c.UserID, c.New-c.Old)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendX("", discord.Embed{
+		Title:       "Levels imported",
+		Description: summary + fmt.Sprintf("\n\nUse `%vlevelcfg reward sync` to update everyone's reward roles.", bot.Prefix()),
+		Color:       bcr.ColourGreen,
+	})
+}
+
+func (bot *Bot) exportLevels(ctx *bcr.Context) (err error) {
+	format, _ := ctx.Flags.GetString("format")
+	format = strings.ToLower(format)
+
+	var lvls []UserLevel
+	err = pgxscan.Select(context.Background(), bot.DB, &lvls, "select * from levels where guild_id = $1 order by xp desc, user_id asc", ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	buf := new(bytes.Buffer)
+	var name string
+
+	switch format {
+	case "json", "oodles":
+		name = "levels.json"
+
+		enc := json.NewEncoder(buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(Export{
+			GuildID:  ctx.Guild.ID,
+			Exported: time.Now().UTC(),
+			Levels:   lvls,
+		})
+	case "csv":
+		name = "levels.csv"
+
+		w := csv.NewWriter(buf)
+		_ = w.Write([]string{"user_id", "xp", "voice_xp"})
+		for _, l := range lvls {
+			_ = w.Write([]string{l.UserID.String(), strconv.FormatInt(l.XP, 10), strconv.FormatInt(l.VoiceXP, 10)})
+		}
+		w.Flush()
+		err = w.Error()
+	default:
+		return ctx.SendfX("`%v` is not a valid format (valid formats are: json, csv)", format)
+	}
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessageComplex(ctx.Message.ChannelID, api.SendMessageData{
+		Content: fmt.Sprintf("Exported levels for %v users.", len(lvls)),
+		Files: []sendpart.File{{
+			Name:   name,
+			Reader: buf,
+		}},
+	})
+	return
+}
@@ -2,41 +2,135 @@ package levels
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
 	"github.com/dustin/go-humanize"
 	"github.com/starshine-sys/bcr"
 )
 
 func (bot *Bot) leaderboard(ctx *bcr.Context) (err error) {
 	full, _ := ctx.Flags.GetBool("full")
-	lb, err := bot.getLeaderboard(ctx.Message.GuildID, full)
+
+	weekly, _ := ctx.Flags.GetBool("weekly")
+	monthly, _ := ctx.Flags.GetBool("monthly")
+	improved, _ := ctx.Flags.GetBool("improved")
+	since, _ := ctx.Flags.GetString("since")
+	until, _ := ctx.Flags.GetString("until")
+
+	from, to, ok, err := parsePeriod(weekly || improved, monthly, since, until)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+	if ok {
+		return bot.periodLeaderboardCmd(ctx, from, to, improved, full)
+	}
+
+	gc, err := bot.getGuildConfig(ctx.Message.GuildID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	members, err := bot.leaderboardMembers(ctx.Message.GuildID, full)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	count, err := bot.leaderboardCount(ctx.Message.GuildID, members)
 	if err != nil {
 		return bot.Report(ctx, err)
 	}
 
-	if len(lb) == 0 {
+	if count == 0 {
 		_, err = ctx.Sendf("There doesn't seem to be anyone on the leaderboard...")
 		return
 	}
 
-	var strings []string
-	for i, l := range lb {
-		strings = append(strings, fmt.Sprintf(
-			"%v. %v: `%v` XP, level `%v`\n",
-			i+1,
-			l.UserID.Mention(),
-			humanize.Comma(l.XP),
-			LevelFromXP(l.XP),
-		))
+	text, _ := ctx.Flags.GetBool("text")
+	perPage := lbPerPage
+	if text {
+		perPage = 15
+	}
+	pages := (count + perPage - 1) / perPage
+
+	// jump to the page a user is on, if one is given
+	var target discord.UserID
+	around, _ := ctx.Flags.GetString("around")
+	if around == "" && len(ctx.Args) > 0 {
+		around = strings.Join(ctx.Args, " ")
+	}
+
+	var start int
+	if around != "" {
+		if strings.EqualFold(around, "me") {
+			target = ctx.Author.ID
+		} else {
+			u, err := ctx.ParseUser(around)
+			if err != nil {
+				_, err = ctx.Send("User not found.")
+				return err
+			}
+			target = u.ID
+		}
+
+		rank, err := bot.userRank(ctx.Message.GuildID, target, members)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		if rank == 0 {
+			return ctx.SendfX("%v isn't on the leaderboard.", target.Mention())
+		}
+		start = (rank - 1) / perPage
 	}
 
 	name := "Leaderboard for " + ctx.Guild.Name
 
-	_, _, err = ctx.ButtonPages(
-		bcr.StringPaginator(name, bot.Colour, strings, 15),
-		10*time.Minute,
-	)
-	return err
+	return bot.renderPages(ctx, pages, start, 10*time.Minute, func(page int) (p renderedPage, err error) {
+		lb, err := bot.getLeaderboardPage(ctx.Message.GuildID, members, page*perPage, perPage)
+		if err != nil {
+			return p, err
+		}
+
+		if text {
+			var s string
+			for i, l := range lb {
+				line := fmt.Sprintf(
+					"%v. %v: `%v` XP, level `%v`\n",
+					page*perPage+i+1,
+					l.UserID.Mention(),
+					humanize.Comma(l.XP),
+					gc.Curve().Level(l.XP),
+				)
+				if l.UserID == target {
+					line = "**" + strings.TrimSuffix(line, "\n") + "**\n"
+				}
+				s += line
+			}
+
+			p.Embeds = []discord.Embed{{
+				Title:       name,
+				Description: s,
+				Color:       bot.Colour,
+				Footer: &discord.EmbedFooter{
+					Text: fmt.Sprintf("Page %v/%v", page+1, pages),
+				},
+			}}
+			return p, nil
+		}
+
+		img, err := generateLeaderboardImage(bot.leaderboardEntries(ctx.Message.GuildID, gc, lb, page*perPage, target))
+		if err != nil {
+			return p, err
+		}
 
+		p.Content = fmt.Sprintf("**%v** (page %v/%v)", name, page+1, pages)
+		p.Files = []sendpart.File{{
+			Name:   "leaderboard.png",
+			Reader: img,
+		}}
+		return p, nil
+	})
 }
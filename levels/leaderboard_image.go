@@ -0,0 +1,339 @@
+package levels
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/disintegration/imaging"
+	"github.com/dustin/go-humanize"
+	"github.com/fogleman/gg"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+)
+
+const (
+	lbWidth     = 1000
+	lbRowHeight = 100
+	lbPadding   = 20
+	lbPerPage   = 10
+	lbBarLen    = 520
+)
+
+// httpClient is used for downloads, so one slow server can't hold up a command forever
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// rank badge colours for the top 3
+var lbBadgeColours = map[int]string{
+	1: "#f1c40f",
+	2: "#bdc3c7",
+	3: "#cd7f32",
+}
+
+// lbEntry is a single row on an image leaderboard.
+type lbEntry struct {
+	Rank   int
+	Name   string
+	Avatar image.Image
+	Colour discord.Color
+
+	Level     int64
+	XP        int64
+	XPForNext int64
+	XPForPrev int64
+	Highlight bool
+}
+
+// fetchAvatar downloads an avatar, returning nil if it fails.
+func fetchAvatar(url string) image.Image {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil
+	}
+	return imaging.Resize(img, 70, 70, imaging.Lanczos)
+}
+
+// leaderboardEntries gets the names, colours, and avatars for a leaderboard page.
+// Avatars are downloaded concurrently.
+func (bot *Bot) leaderboardEntries(guildID discord.GuildID, gc GuildConfig, lb []UserLevel, offset int, highlight discord.UserID) []lbEntry {
+	s, _ := bot.Router.StateFromGuildID(guildID)
+	g, guildErr := s.Guild(guildID)
+
+	curve := gc.Curve()
+	entries := make([]lbEntry, len(lb))
+
+	var wg sync.WaitGroup
+	for i, l := range lb {
+		lvl := curve.Level(l.XP)
+
+		e := lbEntry{
+			Rank:      offset + i + 1,
+			Name:      l.UserID.String(),
+			Colour:    l.Colour,
+			Level:     lvl,
+			XP:        l.XP,
+			XPForNext: curve.XP(lvl + 1),
+			XPForPrev: curve.XP(lvl),
+			Highlight: l.UserID == highlight,
+		}
+
+		var avatarURL string
+		if m, err := s.Member(guildID, l.UserID); err == nil {
+			e.Name = m.User.Username
+			if m.Nick != "" {
+				e.Name = m.Nick
+			}
+			if e.Colour == 0 && guildErr == nil {
+				e.Colour = discord.MemberColor(*g, *m)
+			}
+			avatarURL = m.User.AvatarURLWithType(discord.PNGImage) + "?size=128"
+			if m.Avatar != "" {
+				avatarURL = m.AvatarURLWithType(discord.PNGImage, guildID) + "?size=128"
+			}
+		} else if u, err := s.User(l.UserID); err == nil {
+			e.Name = u.Username
+			avatarURL = u.AvatarURLWithType(discord.PNGImage) + "?size=128"
+		}
+
+		if e.Colour == 0 {
+			e.Colour = bot.Colour
+		}
+
+		entries[i] = e
+
+		if avatarURL != "" {
+			wg.Add(1)
+			go func(i int, url string) {
+				defer wg.Done()
+				entries[i].Avatar = fetchAvatar(url)
+			}(i, avatarURL)
+		}
+	}
+	wg.Wait()
+
+	return entries
+}
+
+// generateLeaderboardImage draws a leaderboard page.
+func generateLeaderboardImage(entries []lbEntry) (io.Reader, error) {
+	img := gg.NewContext(lbWidth, lbPadding+len(entries)*lbRowHeight)
+
+	img.SetHexColor("#2f3136")
+	img.Clear()
+
+	for i, e := range entries {
+		top := float64(lbPadding + i*lbRowHeight)
+		mid := top + (lbRowHeight-lbPadding)/2
+
+		// row background
+		if e.Highlight {
+			img.SetHexColor("#5865f2")
+		} else {
+			img.SetHexColor("#202225")
+		}
+		img.DrawRoundedRectangle(lbPadding, top, lbWidth-2*lbPadding, lbRowHeight-lbPadding, 15)
+		img.Fill()
+
+		// rank badge
+		badge, ok := lbBadgeColours[e.Rank]
+		if !ok {
+			badge = "#4f545c"
+		}
+		img.SetHexColor(badge)
+		img.DrawCircle(70, mid, 28)
+		img.Fill()
+
+		img.SetHexColor("#ffffff")
+		if e.Rank > 99 {
+			img.SetFontFace(boldFontSize(20))
+		} else {
+			img.SetFontFace(boldFontSize(28))
+		}
+		img.DrawStringAnchored(fmt.Sprint(e.Rank), 70, mid, 0.5, 0.4)
+
+		// avatar, cropped to a circle
+		img.SetHexColor(fmt.Sprintf("#%06x", e.Colour))
+		img.DrawCircle(150, mid, 37)
+		img.Fill()
+		if e.Avatar != nil {
+			img.DrawCircle(150, mid, 35)
+			img.Clip()
+			img.DrawImageAnchored(e.Avatar, 150, int(mid), 0.5, 0.5)
+			img.ResetClip()
+		}
+
+		// name, shrunk to fit
+		size := 34.0
+		img.SetFontFace(boldFontSize(size))
+		for size > 10 {
+			if w, _ := img.MeasureString(e.Name); w < lbBarLen {
+				break
+			}
+			size--
+			img.SetFontFace(boldFontSize(size))
+		}
+		img.SetHexColor("#ffffff")
+		img.DrawStringAnchored(e.Name, 210, mid-12, 0, 0.5)
+
+		// progress bar
+		progress := e.XP - e.XPForPrev
+		needed := e.XPForNext - e.XPForPrev
+		p := 0.0
+		if needed > 0 {
+			p = float64(progress) / float64(needed)
+		}
+
+		img.SetHexColor("#686868")
+		img.DrawRoundedRectangle(210, mid+14, lbBarLen, 14, 7)
+		img.Fill()
+		if p > 0 {
+			img.SetHexColor(fmt.Sprintf("#%06x", e.Colour))
+			img.DrawRoundedRectangle(210, mid+14, lbBarLen*p, 14, 7)
+			img.Fill()
+		}
+
+		// level and XP
+		img.SetHexColor("#ffffff")
+		img.SetFontFace(normalFont)
+		img.DrawStringAnchored(fmt.Sprintf("Level %v", e.Level), lbWidth-2*lbPadding-10, mid-15, 1, 0.5)
+		img.SetFontFace(smallFont)
+		img.SetHexColor("#b5b5b5")
+		img.DrawStringAnchored(humanize.Comma(e.XP)+" XP", lbWidth-2*lbPadding-10, mid+20, 1, 0.5)
+	}
+
+	buf := new(bytes.Buffer)
+	err := img.EncodePNG(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// renderedPage is a single page for renderPages.
+type renderedPage struct {
+	Content string
+	Embeds  []discord.Embed
+	Files   []sendpart.File
+}
+
+// renderPages sends a paginated message where every page is rendered when it's shown,
+// so pages can be queried from the database and include images.
+func (bot *Bot) renderPages(ctx *bcr.Context, pages, start int, timeout time.Duration, render func(page int) (renderedPage, error)) (err error) {
+	page := start
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	p, err := render(page)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	data := api.SendMessageData{
+		Content: p.Content,
+		Embeds:  p.Embeds,
+		Files:   p.Files,
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
+	}
+
+	if pages > 1 {
+		data.Components = discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{Emoji: &discord.ComponentEmoji{Name: "⏪"}, Style: discord.SecondaryButtonStyle(), CustomID: "first"},
+				&discord.ButtonComponent{Emoji: &discord.ComponentEmoji{Name: "⬅️"}, Style: discord.SecondaryButtonStyle(), CustomID: "prev"},
+				&discord.ButtonComponent{Emoji: &discord.ComponentEmoji{Name: "➡️"}, Style: discord.SecondaryButtonStyle(), CustomID: "next"},
+				&discord.ButtonComponent{Emoji: &discord.ComponentEmoji{Name: "⏩"}, Style: discord.SecondaryButtonStyle(), CustomID: "last"},
+			},
+		}
+	}
+
+	msg, err := ctx.State.SendMessageComplex(ctx.Message.ChannelID, data)
+	if err != nil || pages <= 1 {
+		return err
+	}
+
+	var mu sync.Mutex
+	turn := func(change func(page int) int) func(*bcr.Context, *gateway.InteractionCreateEvent) {
+		return func(ctx *bcr.Context, ev *gateway.InteractionCreateEvent) {
+			err := ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+				Type: api.DeferredMessageUpdate,
+			})
+			if err != nil {
+				common.Log.Errorf("Error responding to interaction: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			page = change(page)
+
+			p, err := render(page)
+			if err != nil {
+				common.Log.Errorf("Error rendering page %v: %v", page, err)
+				return
+			}
+
+			_, err = ctx.State.EditInteractionResponse(ev.AppID, ev.Token, api.EditInteractionResponseData{
+				Content:     option.NewNullableString(p.Content),
+				Embeds:      &p.Embeds,
+				Files:       p.Files,
+				Attachments: &[]discord.Attachment{},
+			})
+			if err != nil {
+				common.Log.Errorf("Error editing page: %v", err)
+			}
+		}
+	}
+
+	rmFuncs := []bcr.ButtonRemoveFunc{
+		ctx.AddButtonHandler(msg.ID, ctx.Author.ID, "first", false, turn(func(int) int { return 0 })),
+		ctx.AddButtonHandler(msg.ID, ctx.Author.ID, "last", false, turn(func(int) int { return pages - 1 })),
+		ctx.AddButtonHandler(msg.ID, ctx.Author.ID, "prev", false, turn(func(page int) int {
+			if page == 0 {
+				return pages - 1
+			}
+			return page - 1
+		})),
+		ctx.AddButtonHandler(msg.ID, ctx.Author.ID, "next", false, turn(func(page int) int {
+			if page >= pages-1 {
+				return 0
+			}
+			return page + 1
+		})),
+	}
+
+	time.AfterFunc(timeout, func() {
+		for _, rm := range rmFuncs {
+			rm()
+		}
+
+		_, err := ctx.State.EditMessageComplex(msg.ChannelID, msg.ID, api.EditMessageData{
+			Components: discord.ComponentsPtr(),
+		})
+		if err != nil {
+			common.Log.Errorf("Error removing page buttons: %v", err)
+		}
+	})
+	return nil
+}
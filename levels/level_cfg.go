@@ -3,11 +3,13 @@ package levels
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/dustin/go-humanize"
 	"github.com/starshine-sys/bcr"
 )
 
@@ -19,9 +21,17 @@ func (bot *Bot) configShow(ctx *bcr.Context) (err error) {
 
 	desc := fmt.Sprintf(`**Levels enabled:** %v
 **DM on reward:** %v
+**Stack rewards:** %v
 **Time between XP:** %v
 **Reward log:** %v
-**Nolevels log:** %v`, gc.LevelsEnabled, gc.DMOnReward, gc.BetweenXP, bot.mentionOrNone(gc.RewardLog), bot.mentionOrNone(gc.NolevelsLog))
+**Nolevels log:** %v
+**XP per message:** %v-%v
+**Level curve:** %v
+**Voice XP per minute:** %v
+**Weekly leaderboard:** %v
+**Custom backgrounds:** %v
+**XP decay:** %v
+**Level-up messages:** %v`, gc.LevelsEnabled, gc.DMOnReward, gc.StackRewards, gc.BetweenXP, bot.mentionOrNone(gc.RewardLog), bot.mentionOrNone(gc.NolevelsLog), gc.MinXP, gc.MaxXP, gc.Curve(), voiceXPString(gc.VoiceXP), bot.mentionOrNone(gc.LeaderboardChannel), customBgString(gc), decayString(gc), levelupString(gc))
 
 	e := discord.Embed{
 		Color:       bot.Colour,
@@ -68,6 +78,36 @@ func (bot *Bot) configShow(ctx *bcr.Context) (err error) {
 	}
 	e.Fields = append(e.Fields, f)
 
+	ms, err := bot.getMultipliers(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if len(ms) > 0 {
+		f = discord.EmbedField{Name: "XP multipliers"}
+		for _, m := range ms {
+			f.Value += fmt.Sprintf("%v: %vx\n", m.Mention(), m.Multiplier)
+		}
+		if len(f.Value) > 1024 {
+			f.Value = fmt.Sprintf("%v multipliers, use `%vlevelcfg multiplier` to see them all.", len(ms), bot.Prefix())
+		}
+		e.Fields = append(e.Fields, f)
+	}
+
+	evs, err := bot.getXPEvents(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if len(evs) > 0 {
+		f = discord.EmbedField{Name: "XP events"}
+		for _, ev := range evs {
+			f.Value += xpEventLine(ev) + "\n"
+		}
+		if len(f.Value) > 1024 {
+			f.Value = fmt.Sprintf("%v events, use `%vlevelcfg event` to see them all.", len(evs), bot.Prefix())
+		}
+		e.Fields = append(e.Fields, f)
+	}
+
 	rewards, err := bot.getAllRewards(ctx.Guild.ID)
 	if err != nil {
 		return bot.Report(ctx, err)
@@ -91,9 +131,33 @@ func (bot *Bot) configShow(ctx *bcr.Context) (err error) {
 		})
 	}
 
+	if gc.LevelupMessage != "" {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Level-up message",
+			Value: "```md\n" + gc.LevelupMessage + "\n```",
+		})
+	}
+
 	return ctx.SendX("", e)
 }
 
+func customBgString(gc GuildConfig) string {
+	if !gc.BgApprovalChannel.IsValid() {
+		return "disabled"
+	}
+	if gc.CustomBgLevel <= 0 && !gc.CustomBgRole.IsValid() {
+		return "anyone can upload, approved in " + gc.BgApprovalChannel.Mention()
+	}
+	return uploadRequirement(gc) + ", approved in " + gc.BgApprovalChannel.Mention()
+}
+
+func voiceXPString(xp int64) string {
+	if xp <= 0 {
+		return "disabled"
+	}
+	return strconv.FormatInt(xp, 10)
+}
+
 func (bot *Bot) mentionOrNone(id discord.ChannelID) string {
 	if id.IsValid() {
 		return id.Mention()
@@ -129,6 +193,19 @@ func (bot *Bot) setConfig(ctx *bcr.Context) (err error) {
 		}
 		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
 
+	case "stack_rewards":
+		b, err := strconv.ParseBool(ctx.Args[1])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `stack_rewards` (true or false)", ctx.Args[1])
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set stack_rewards = $1 where id = $2", b, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
 	case "between_xp":
 		dur, err := time.ParseDuration(ctx.Args[1])
 		if err != nil {
@@ -160,6 +237,120 @@ func (bot *Bot) setConfig(ctx *bcr.Context) (err error) {
 		}
 		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
 
+	case "custom_bg_level":
+		lvl, err := strconv.ParseInt(ctx.Args[1], 10, 64)
+		if err != nil || lvl < 0 {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `custom_bg_level` (must be a level, 0 to disable)", ctx.Args[1])
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set custom_bg_level = $1 where id = $2", lvl, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "custom_bg_role":
+		var id discord.RoleID
+		if !strings.EqualFold(ctx.Args[1], "none") {
+			r, err := ctx.ParseRole(strings.Join(ctx.Args[1:], " "))
+			if err != nil {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `custom_bg_role` (must be a role, or `none`)", ctx.Args[1])
+				return err
+			}
+			id = r.ID
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set custom_bg_role = $1 where id = $2", id, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "bg_approval_channel":
+		var id discord.ChannelID
+		if !strings.EqualFold(ctx.Args[1], "none") && !strings.EqualFold(ctx.Args[1], "off") {
+			ch, err := ctx.ParseChannel(ctx.Args[1])
+			if err != nil || ch.GuildID != ctx.Guild.ID || ch.Type != discord.GuildText {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `bg_approval_channel` (must be a text channel in this guild, or `off`)", ctx.Args[1])
+				return err
+			}
+			id = ch.ID
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set bg_approval_channel = $1 where id = $2", id, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "decay_days", "decay_amount", "decay_floor":
+		key := strings.ToLower(ctx.Args[0])
+
+		var err error
+		if key == "decay_amount" {
+			amount, percent, perr := parseDecayAmount(ctx.Args[1])
+			if perr != nil {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `decay_amount` (%v)", ctx.Args[1], perr)
+				return err
+			}
+			_, err = bot.DB.Exec(context.Background(), "update level_config set decay_amount = $1, decay_percent = $2 where id = $3", amount, percent, ctx.Guild.ID)
+		} else {
+			n, perr := strconv.ParseInt(ctx.Args[1], 10, 64)
+			if perr != nil || n < 0 {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `%v` (must be a number, 0 to disable)", ctx.Args[1], key)
+				return err
+			}
+			_, err = bot.DB.Exec(context.Background(), "update level_config set "+key+" = $1 where id = $2", n, ctx.Guild.ID)
+		}
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		gc, err := bot.getGuildConfig(ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		err = bot.scheduleDecay(gc)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		_, err = ctx.Replyc(bcr.ColourGreen, "**XP decay:** %v", decayString(gc))
+		return err
+
+	case "leaderboard_channel":
+		if strings.EqualFold(ctx.Args[1], "off") || strings.EqualFold(ctx.Args[1], "none") {
+			_, err = bot.DB.Exec(context.Background(), "update level_config set leaderboard_channel = 0 where id = $1", ctx.Guild.ID)
+			if err != nil {
+				return bot.Report(ctx, err)
+			}
+			_, err = bot.DB.Exec(context.Background(), "delete from scheduled_events where event_type = 'levels.weeklyLeaderboard' and (data->>'guild_id')::bigint = $1", ctx.Guild.ID)
+			if err != nil {
+				return bot.Report(ctx, err)
+			}
+			_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+			return nil
+		}
+
+		ch, err := ctx.ParseChannel(ctx.Args[1])
+		if err != nil || ch.GuildID != ctx.Guild.ID || ch.Type != discord.GuildText {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `leaderboard_channel` (must be a text channel in this guild, or `off`)", ctx.Args[1])
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set leaderboard_channel = $1 where id = $2", ch.ID, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		err = bot.scheduleWeeklyLeaderboard(ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_, err = ctx.Replyc(bcr.ColourGreen, "The weekly leaderboard will now be posted in %v every Monday at midnight UTC.", ch.Mention())
+		return err
+
 	case "reward_text":
 		text := strings.TrimSpace(strings.TrimPrefix(ctx.RawArgs, ctx.Args[0]))
 		if text == ctx.RawArgs {
@@ -176,8 +367,114 @@ func (bot *Bot) setConfig(ctx *bcr.Context) (err error) {
 		}
 		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
 
+	case "levelup_channel":
+		mode := strings.ToLower(ctx.Args[1])
+		var id discord.ChannelID
+		switch mode {
+		case LevelupOff, "none", LevelupCurrent, LevelupDM:
+			if mode == "none" {
+				mode = LevelupOff
+			}
+		default:
+			ch, err := ctx.ParseChannel(ctx.Args[1])
+			if err != nil || ch.GuildID != ctx.Guild.ID || (ch.Type != discord.GuildText && ch.Type != discord.GuildNews) {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `levelup_channel` (must be a text channel in this guild, `current`, `dm`, or `off`)", ctx.Args[1])
+				return err
+			}
+			mode = LevelupChannel
+			id = ch.ID
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set levelup_mode = $1, levelup_channel = $2 where id = $3", mode, id, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "levelup_message":
+		text := strings.TrimSpace(strings.TrimPrefix(ctx.RawArgs, ctx.Args[0]))
+		if text == ctx.RawArgs {
+			text = strings.Join(ctx.Args[1:], " ")
+		}
+		if strings.EqualFold(text, "reset") || strings.EqualFold(text, "default") {
+			text = ""
+		}
+		if len(text) > 1500 {
+			_, err = ctx.Replyc(bcr.ColourRed, "Your message is too long! Maximum 1500 characters.")
+			return
+		}
+
+		if text != "" {
+			gc, err := bot.getGuildConfig(ctx.Guild.ID)
+			if err != nil {
+				return bot.Report(ctx, err)
+			}
+
+			_, err = bot.previewLevelupMessage(ctx, gc, text, 1)
+			if err != nil {
+				_, err = ctx.Replyc(bcr.ColourRed, "There's an error in your message: %v", bcr.AsCode(err.Error()))
+				return err
+			}
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set levelup_message = $1 where id = $2", text, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "min_xp", "max_xp":
+		xp, err := strconv.ParseInt(ctx.Args[1], 10, 64)
+		if err != nil || xp < 0 || xp > 10000 {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `%v` (must be a number between 0 and 10000)", ctx.Args[1], strings.ToLower(ctx.Args[0]))
+			return err
+		}
+
+		gc, err := bot.getGuildConfig(ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		if (strings.EqualFold(ctx.Args[0], "min_xp") && xp > gc.MaxXP) || (strings.EqualFold(ctx.Args[0], "max_xp") && xp < gc.MinXP) {
+			_, err = ctx.Replyc(bcr.ColourRed, "`min_xp` can't be higher than `max_xp` (currently %v-%v)", gc.MinXP, gc.MaxXP)
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set "+strings.ToLower(ctx.Args[0])+" = $1 where id = $2", xp, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "voice_xp":
+		xp, err := strconv.ParseInt(ctx.Args[1], 10, 64)
+		if err != nil || xp < 0 || xp > 10000 {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `voice_xp` (must be a number between 0 and 10000, 0 to disable)", ctx.Args[1])
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set voice_xp = $1 where id = $2", xp, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+
+	case "curve":
+		curve, err := ParseCurve(ctx.Args[1], ctx.Args[2:])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "Invalid curve: %v", err)
+			return err
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update level_config set level_curve = $1, curve_coefficients = $2 where id = $3", curve.Type, curve.Coefficients, ctx.Guild.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		_, err = ctx.Replyc(bcr.ColourGreen, "Level curve set to %v! Use `%vlevelcfg preview` to see the XP needed for each level.", curve, bot.Prefix())
+		return err
+
 	default:
-		_, err = ctx.Replyc(bcr.ColourRed, "Sorry, but `%v` is not a valid configuration key. Valid keys are: enable, dm_on_reward, between_xp, nolevels_log, reward_log, reward_text.", ctx.Args[0])
+		_, err = ctx.Replyc(bcr.ColourRed, "Sorry, but `%v` is not a valid configuration key. Valid keys are: enable, dm_on_reward, between_xp, nolevels_log, reward_log, reward_text, stack_rewards, min_xp, max_xp, voice_xp, curve, leaderboard_channel, custom_bg_level, custom_bg_role, bg_approval_channel, decay_days, decay_amount, decay_floor, levelup_channel, levelup_message.", ctx.Args[0])
 		return
 	}
 	return
@@ -260,3 +557,38 @@ func (bot *Bot) blacklistRemove(ctx *bcr.Context) (err error) {
 
 	return ctx.SendfX("Removed @%v from the list of blocked roles!", r.Name)
 }
+
+func (bot *Bot) previewCurve(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	curve := gc.Curve()
+	if len(ctx.Args) > 0 {
+		curve, err = ParseCurve(ctx.Args[0], ctx.Args[1:])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "Invalid curve: %v", err)
+			return err
+		}
+	}
+
+	avgXP := float64(gc.MinXP+gc.MaxXP) / 2
+
+	var lines []string
+	for lvl := int64(1); lvl <= 100; lvl++ {
+		xp := curve.XP(lvl)
+
+		line := fmt.Sprintf("Level %v: `%v` XP (+%v)", lvl, humanize.Comma(xp), humanize.Comma(xp-curve.XP(lvl-1)))
+		if avgXP > 0 {
+			line += fmt.Sprintf(", ~%v messages", humanize.Comma(int64(math.Ceil(float64(xp)/avgXP))))
+		}
+		lines = append(lines, line+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Level curve: "+curve.String(), bot.Colour, lines, 20),
+		10*time.Minute,
+	)
+	return err
+}
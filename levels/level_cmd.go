@@ -30,7 +30,7 @@ var blankPixels = []int{96, 96, 96, 96, 85, 85, 85, 85, 74, 74, 74, 74, 68, 68,
 //go:embed fonts
 var fontData embed.FS
 
-var normalFont font.Face
+var normalFont, smallFont font.Face
 
 const defaultBoldSize = 60
 
@@ -79,12 +79,14 @@ func init() {
 	// emoji fallback
 	emoji = mustParse("fonts/NotoEmoji-Regular.ttf")
 
-	normalFont = truetype.NewFace(
-		mustParse("fonts/Montserrat-Regular.ttf"),
-		&truetype.Options{
-			Size: 40,
-		},
-	)
+	regular := mustParse("fonts/Montserrat-Regular.ttf")
+
+	normalFont = truetype.NewFace(regular, &truetype.Options{
+		Size: 40,
+	})
+	smallFont = truetype.NewFace(regular, &truetype.Options{
+		Size: 28,
+	})
 }
 
 func (bot *Bot) levelCmd(ctx *bcr.Context) (err error) {
@@ -102,21 +104,24 @@ func (bot *Bot) levelCmd(ctx *bcr.Context) (err error) {
 		return bot.Report(ctx, err)
 	}
 
-	lvl := LevelFromXP(uc.XP)
-	xpForNext := XPFromLevel(lvl + 1)
-	xpForPrev := XPFromLevel(lvl)
+	gc, err := bot.getGuildConfig(ctx.Message.GuildID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	curve := gc.Curve()
+
+	lvl := curve.Level(uc.XP)
+	xpForNext := curve.XP(lvl + 1)
+	xpForPrev := curve.XP(lvl)
 
-	// get leaderboard (for rank)
-	// filter the leaderboard to match the `leaderboard` command
+	// get rank, filtered to match the `leaderboard` command
 	var rank int
-	lb, err := bot.getLeaderboard(ctx.Message.GuildID, false)
+	members, err := bot.leaderboardMembers(ctx.Message.GuildID, false)
 	if err == nil {
-		for i, uc := range lb {
-			if uc.UserID == u.ID {
-				rank = i + 1
-				break
-			}
-		}
+		rank, err = bot.userRank(ctx.Message.GuildID, u.ID, members)
+	}
+	if err != nil {
+		common.Log.Errorf("Error getting rank for %v: %v", u.Tag(), err)
 	}
 
 	// get user colour + avatar URL
@@ -141,22 +146,22 @@ func (bot *Bot) levelCmd(ctx *bcr.Context) (err error) {
 	if useEmbed, _ := ctx.Flags.GetBool("embed"); useEmbed {
 		e := bot.generateEmbed(
 			ctx, username, avatarURL, clr,
-			rank, lvl, uc.XP, xpForNext, xpForPrev,
+			rank, lvl, uc.XP, uc.VoiceXP, xpForNext, xpForPrev,
 		)
 		return ctx.SendX("", e)
 	}
 
 	img, err := bot.generateImage(
 		ctx, username, avatarURL, clr,
-		rank, lvl, uc.XP, xpForNext, xpForPrev,
-		bot.getBackground(uc.Background),
+		rank, lvl, uc.XP, uc.VoiceXP, xpForNext, xpForPrev,
+		bot.getBackground(uc.Background), cardStyle{Theme: uc.CardTheme, Layout: uc.CardLayout},
 	)
 	if err != nil {
 		common.Log.Errorf("Error generating level card for %v, falling back to embed: %v", u.Tag(), err)
 
 		e := bot.generateEmbed(
 			ctx, username, avatarURL, clr,
-			rank, lvl, uc.XP, xpForNext, xpForPrev,
+			rank, lvl, uc.XP, uc.VoiceXP, xpForNext, xpForPrev,
 		)
 		return ctx.SendX("", e)
 	}
@@ -178,10 +183,18 @@ const (
 
 func (bot *Bot) generateImage(ctx *bcr.Context,
 	name, avatarURL string, clr discord.Color,
-	rank int, lvl, xp, xpForNext, xpForPrev int64,
-	background []byte,
+	rank int, lvl, xp, voiceXP, xpForNext, xpForPrev int64,
+	background []byte, style cardStyle,
 ) (r io.Reader, err error) {
 
+	theme := style.theme()
+
+	// the avatar is on the left for the classic layout, and on the right for the mirrored one
+	avatarX, contentX, contentEnd := 200.0, 350.0, float64(width-100)
+	if style.Layout == LayoutMirrored {
+		avatarX, contentX, contentEnd = width-200, 100, width-350
+	}
+
 	img := gg.NewContext(width, height)
 
 	// background
@@ -193,7 +206,7 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 		}
 	}
 
-	img.SetHexColor("#00000088")
+	img.SetHexColor(theme.Overlay)
 	img.DrawRoundedRectangle(50, 50, width-100, height-100, 20)
 	img.Fill()
 
@@ -234,10 +247,10 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 
 	// draw pfp to context
 	img.SetHexColor(fmt.Sprintf("#%06x", clr))
-	img.DrawCircle(200, 200, 130)
+	img.DrawCircle(avatarX, 200, 130)
 	img.FillPreserve()
 
-	img.DrawImageAnchored(pfpImg.Image(), 200, 200, 0.5, 0.5)
+	img.DrawImageAnchored(pfpImg.Image(), int(avatarX), 200, 0.5, 0.5)
 
 	img.SetLineWidth(5)
 	img.Stroke()
@@ -249,32 +262,30 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 
 	end := progressBarLen * p
 
-	img.DrawRectangle(350, 275, end, 50)
+	img.DrawRectangle(contentX, 275, end, 50)
 	img.Fill()
 
-	img.SetHexColor("#686868")
-	img.DrawRectangle(350+end, 275, progressBarLen-end, 50)
+	img.SetHexColor(theme.Bar)
+	img.DrawRectangle(contentX+end, 275, progressBarLen-end, 50)
 	img.Fill()
 
-	img.SetHexColor(fmt.Sprintf("#%06xCC", clr))
-
-	img.SetColor(color.NRGBA{0xB5, 0xB5, 0xB5, 0xCC})
+	img.SetHexColor(theme.Divider + "CC")
 
-	img.DrawRectangle(350, 180, progressBarLen, 3)
+	img.DrawRectangle(contentX, 180, progressBarLen, 3)
 	img.Fill()
 
-	img.SetStrokeStyle(gg.NewSolidPattern(color.NRGBA{0xB5, 0xB5, 0xB5, 0xFF}))
+	img.SetHexColor(theme.Divider)
 
-	img.DrawRoundedRectangle(350, 275, progressBarLen, 50, 5)
+	img.DrawRoundedRectangle(contentX, 275, progressBarLen, 50, 5)
 	img.SetLineWidth(2)
 	img.Stroke()
 
-	img.SetHexColor("#ffffff")
+	img.SetHexColor(theme.Text)
 
 	currentSize := float64(defaultBoldSize)
 	img.SetFontFace(boldFontSize(currentSize))
 
-	targetLen := (width - 100) - 350
+	targetLen := contentEnd - contentX
 	if rank != 0 {
 		targetLen -= 200
 	}
@@ -282,7 +293,7 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 	for currentSize > 1 {
 		w, h := img.MeasureString(name)
 
-		if w < float64(targetLen) {
+		if w < targetLen {
 			common.Log.Debugf("name %q fits in %v height", name, h)
 
 			break
@@ -293,22 +304,27 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 	}
 
 	// name
-	img.DrawStringAnchored(name, 350, 120, 0, 0.5)
+	img.DrawStringAnchored(name, contentX, 120, 0, 0.5)
 
 	// rank/xp
 	img.SetFontFace(normalFont)
 
 	if rank != 0 {
-		img.DrawStringAnchored(fmt.Sprintf("Rank #%v", rank), width-100, 120, 1, 0.5)
+		img.DrawStringAnchored(fmt.Sprintf("Rank #%v", rank), contentEnd, 120, 1, 0.5)
 	}
 
-	img.DrawStringAnchored(fmt.Sprintf("Level %v", lvl), width-100, 200, 1, 1)
+	img.DrawStringAnchored(fmt.Sprintf("Level %v", lvl), contentEnd, 200, 1, 1)
 
-	img.DrawStringAnchored(fmt.Sprintf("%v%%", int64(p*100)), 350+(progressBarLen/2), 295, 0.5, 0.5)
+	img.DrawStringAnchored(fmt.Sprintf("%v%%", int64(p*100)), contentX+(progressBarLen/2), 295, 0.5, 0.5)
 
 	progressStr := fmt.Sprintf("%v/%v XP", humanize.Comma(progress), humanize.Comma(needed))
 
-	img.DrawStringAnchored(progressStr, 350, 200, 0, 1)
+	img.DrawStringAnchored(progressStr, contentX, 200, 0, 1)
+
+	if voiceXP > 0 {
+		img.SetFontFace(smallFont)
+		img.DrawStringAnchored(fmt.Sprintf("Text: %v XP · Voice: %v XP", humanize.Comma(xp-voiceXP), humanize.Comma(voiceXP)), contentX, 240, 0, 0.5)
+	}
 
 	buf := new(bytes.Buffer)
 
@@ -322,13 +338,13 @@ func (bot *Bot) generateImage(ctx *bcr.Context,
 
 func (bot *Bot) generateEmbed(ctx *bcr.Context,
 	name, avatarURL string, clr discord.Color,
-	rank int, lvl, xp, xpForNext, xpForPrev int64,
+	rank int, lvl, xp, voiceXP, xpForNext, xpForPrev int64,
 ) discord.Embed {
 
 	e := discord.Embed{
 		Color:       clr,
 		Title:       fmt.Sprintf("Level %v - Rank #%v", lvl, rank),
-		Description: fmt.Sprintf("%v/%v XP", humanize.Comma(xp), humanize.Comma(XPFromLevel(lvl+1))),
+		Description: fmt.Sprintf("%v/%v XP", humanize.Comma(xp), humanize.Comma(xpForNext)),
 		Thumbnail: &discord.EmbedThumbnail{
 			URL: avatarURL,
 		},
@@ -351,5 +367,12 @@ func (bot *Bot) generateEmbed(ctx *bcr.Context,
 		})
 	}
 
+	if voiceXP > 0 {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "XP sources",
+			Value: fmt.Sprintf("Text: %v XP\nVoice: %v XP", humanize.Comma(xp-voiceXP), humanize.Comma(voiceXP)),
+		})
+	}
+
 	return e
 }
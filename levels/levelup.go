@@ -0,0 +1,345 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/dustin/go-humanize"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// Level-up announcement modes
+const (
+	LevelupOff     = "off"
+	LevelupCurrent = "current"
+	LevelupChannel = "channel"
+	LevelupDM      = "dm"
+)
+
+const defaultLevelupMessage = "Congratulations {{.User.Mention}}, you reached level **{{.Level}}**!"
+
+// the users config key for level-up pings
+const levelPingsKey = "level_pings"
+
+const levelupTemplateHelp = "Messages use Go's template syntax, with these fields:\n" +
+	"`{{.User.Mention}}`, `{{.User.Username}}`, `{{displayName .Member}}`: the member who levelled up\n" +
+	"`{{.OldLevel}}`, `{{.Level}}`: their previous and new level\n" +
+	"`{{.XP}}`, `{{.XPToNext}}`: their total XP, and the XP needed for the next level\n" +
+	"`{{.Reward.Mention}}`, `{{.Reward.Name}}`: the role rewarded for this level, check it exists with `{{if .Reward}}...{{end}}`\n" +
+	"`{{.Guild.Name}}`: the server's name"
+
+// LevelupData is passed to level-up message templates.
+type LevelupData struct {
+	Guild  *discord.Guild
+	Member *discord.Member
+	User   discord.User
+
+	OldLevel int64
+	Level    int64
+	XP       int64
+	// the XP still needed to reach the next level
+	XPToNext int64
+
+	// nil if there's no reward for this level
+	Reward *discord.Role
+}
+
+// LevelMessage is a custom level-up message for a single level.
+type LevelMessage struct {
+	GuildID discord.GuildID
+	Level   int64 `db:"lvl"`
+	Message string
+}
+
+func levelupString(gc GuildConfig) string {
+	switch gc.LevelupMode {
+	case LevelupCurrent:
+		return "in the current channel"
+	case LevelupChannel:
+		return "in " + gc.LevelupChannel.Mention()
+	case LevelupDM:
+		return "in DMs"
+	default:
+		return "disabled"
+	}
+}
+
+// levelupData builds the template data for a member reaching newLvl.
+func (bot *Bot) levelupData(s *state.State, sc GuildConfig, m discord.Member, oldLvl, newLvl, xp int64, reward discord.RoleID) LevelupData {
+	curve := sc.Curve()
+
+	data := LevelupData{
+		Member:   &m,
+		User:     m.User,
+		OldLevel: oldLvl,
+		Level:    newLvl,
+		XP:       xp,
+		XPToNext: curve.XP(newLvl+1) - xp,
+	}
+
+	if g, err := s.Guild(sc.ID); err == nil {
+		data.Guild = g
+	} else {
+		data.Guild = &discord.Guild{ID: sc.ID}
+	}
+
+	if reward.IsValid() {
+		if r, err := s.Role(sc.ID, reward); err == nil {
+			data.Reward = r
+		} else {
+			data.Reward = &discord.Role{ID: reward}
+		}
+	}
+	return data
+}
+
+// levelMessage returns the message for the given level: its custom message, the server's default message, or the built-in message.
+func (bot *Bot) levelMessage(sc GuildConfig, lvl int64) string {
+	var msg string
+	err := bot.DB.QueryRow(context.Background(), "select message from level_messages where guild_id = $1 and lvl = $2", sc.ID, lvl).Scan(&msg)
+	if err == nil && msg != "" {
+		return msg
+	}
+
+	if sc.LevelupMessage != "" {
+		return sc.LevelupMessage
+	}
+	return defaultLevelupMessage
+}
+
+// levelPings returns true if the user wants to be pinged in level-up messages.
+func (bot *Bot) levelPings(userID discord.UserID) bool {
+	val, err := bot.DB.UserStringGet(context.Background(), userID, levelPingsKey)
+	if err != nil {
+		common.Log.Errorf("Error getting level ping setting for %v: %v", userID, err)
+	}
+	return val != "false"
+}
+
+// levelUp gives the member any rewards for reaching newLvl, and announces the level-up.
+// chID is the channel the member levelled up in.
+func (bot *Bot) levelUp(s *state.State, sc GuildConfig, m discord.Member, oldLvl, newLvl, xp int64, chID discord.ChannelID, source discord.EmbedField) {
+	reward := bot.giveReward(s, sc, m.User, m.RoleIDs, newLvl, source)
+
+	if sc.LevelupMode == "" || sc.LevelupMode == LevelupOff {
+		return
+	}
+
+	txt, err := common.ExecTemplate(bot.levelMessage(sc, newLvl), bot.levelupData(s, sc, m, oldLvl, newLvl, xp, reward))
+	if err != nil {
+		bot.SendError("Error executing level-up message for level %v in %v: %v", newLvl, sc.ID, err)
+		return
+	}
+	// an empty message can be used to not announce specific levels
+	if strings.TrimSpace(txt) == "" {
+		return
+	}
+
+	var target discord.ChannelID
+	switch sc.LevelupMode {
+	case LevelupCurrent:
+		target = chID
+	case LevelupChannel:
+		target = sc.LevelupChannel
+	case LevelupDM:
+		ch, err := s.CreatePrivateChannel(m.User.ID)
+		if err != nil {
+			common.Log.Errorf("Error creating DM channel for %v: %v", m.User.Tag(), err)
+			return
+		}
+		target = ch.ID
+	}
+	if !target.IsValid() {
+		return
+	}
+
+	mentions := &api.AllowedMentions{
+		Parse: []api.AllowedMentionType{},
+	}
+	if sc.LevelupMode != LevelupDM && bot.levelPings(m.User.ID) {
+		mentions.Users = []discord.UserID{m.User.ID}
+	}
+
+	_, err = s.SendMessageComplex(target, api.SendMessageData{
+		Content:         txt,
+		AllowedMentions: mentions,
+	})
+	if err != nil {
+		common.Log.Errorf("Error sending level-up message for %v: %v", m.User.Tag(), err)
+	}
+}
+
+func (bot *Bot) setLevelPings(ctx *bcr.Context) (err error) {
+	if len(ctx.Args) == 0 {
+		if bot.levelPings(ctx.Author.ID) {
+			return ctx.SendfX("You're currently pinged in level-up messages. Use `%vlevel pings off` to stop being pinged.", bot.Prefix())
+		}
+		return ctx.SendfX("You're currently **not** pinged in level-up messages. Use `%vlevel pings on` to be pinged again.", bot.Prefix())
+	}
+
+	var b bool
+	switch strings.ToLower(ctx.Args[0]) {
+	case "on", "yes", "true", "enable":
+		b = true
+	case "off", "no", "false", "disable":
+		b = false
+	default:
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` isn't a valid setting, use `on` or `off`.", ctx.Args[0])
+		return err
+	}
+
+	err = bot.DB.UserStringSet(ctx.Author.ID, levelPingsKey, strconv.FormatBool(b))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if b {
+		return ctx.SendX("You'll now be pinged in level-up messages!")
+	}
+	return ctx.SendX("You'll no longer be pinged in level-up messages.")
+}
+
+// previewLevelupMessage executes a level-up message template with the command author's data.
+func (bot *Bot) previewLevelupMessage(ctx *bcr.Context, sc GuildConfig, tmpl string, lvl int64) (string, error) {
+	m := discord.Member{User: ctx.Author}
+	if ctx.Member != nil {
+		m = *ctx.Member
+		m.User = ctx.Author
+	}
+
+	var reward discord.RoleID
+	if r := bot.getReward(sc.ID, lvl); r != nil {
+		reward = r.RoleReward
+	}
+
+	data := bot.levelupData(ctx.State, sc, m, lvl-1, lvl, sc.Curve().XP(lvl), reward)
+	return common.ExecTemplate(tmpl, data)
+}
+
+func (bot *Bot) levelMessageList(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	var msgs []LevelMessage
+	err = pgxscan.Select(context.Background(), bot.DB, &msgs, "select * from level_messages where guild_id = $1 order by lvl asc", ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	def := gc.LevelupMessage
+	if def == "" {
+		def = defaultLevelupMessage
+	}
+
+	lines := []string{
+		fmt.Sprintf("**Announcements:** %v\n**Default message:**\n```%v```\n", levelupString(gc), def),
+	}
+	for _, m := range msgs {
+		lines = append(lines, fmt.Sprintf("**Level %v:**\n```%v```\n", m.Level, m.Message))
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Level-up messages", bot.Colour, lines, 5),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) levelMessageSet(ctx *bcr.Context) (err error) {
+	lvl, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil || lvl <= 0 {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid level.", ctx.Args[0])
+		return err
+	}
+
+	msg := strings.TrimSpace(strings.TrimPrefix(ctx.RawArgs, ctx.Args[0]))
+	if msg == ctx.RawArgs {
+		msg = strings.Join(ctx.Args[1:], " ")
+	}
+	if len(msg) > 1500 {
+		_, err = ctx.Replyc(bcr.ColourRed, "Your message is too long! Maximum 1500 characters.")
+		return err
+	}
+
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	preview, err := bot.previewLevelupMessage(ctx, gc, msg, lvl)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "There's an error in your message: %v", bcr.AsCode(err.Error()))
+		return err
+	}
+
+	_, err = bot.DB.Exec(context.Background(), `insert into level_messages (guild_id, lvl, message) values ($1, $2, $3)
+	on conflict (guild_id, lvl) do update set message = $3`, ctx.Guild.ID, lvl, msg)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if strings.TrimSpace(preview) == "" {
+		return ctx.SendfX("Level-ups to level %v won't be announced.", lvl)
+	}
+	return ctx.SendX(fmt.Sprintf("Set the message for level %v! Preview:\n\n%v", lvl, preview))
+}
+
+func (bot *Bot) levelMessageRemove(ctx *bcr.Context) (err error) {
+	lvl, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid level.", ctx.Args[0])
+		return err
+	}
+
+	ct, err := bot.DB.Exec(context.Background(), "delete from level_messages where guild_id = $1 and lvl = $2", ctx.Guild.ID, lvl)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("There's no custom message for level %v.", lvl)
+	}
+
+	return ctx.SendfX("Removed the custom message for level %v! The default message will be used instead.", lvl)
+}
+
+func (bot *Bot) levelMessagePreview(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	uc, err := bot.getUser(ctx.Guild.ID, ctx.Author.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	lvl := gc.Curve().Level(uc.XP) + 1
+	if len(ctx.Args) > 0 {
+		lvl, err = strconv.ParseInt(ctx.Args[0], 10, 64)
+		if err != nil || lvl <= 0 {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid level.", ctx.Args[0])
+			return err
+		}
+	}
+
+	preview, err := bot.previewLevelupMessage(ctx, gc, bot.levelMessage(gc, lvl), lvl)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "There's an error in the message for level %v: %v", lvl, bcr.AsCode(err.Error()))
+		return err
+	}
+	if strings.TrimSpace(preview) == "" {
+		return ctx.SendfX("Level-ups to level %v aren't announced.", lvl)
+	}
+
+	return ctx.SendX(fmt.Sprintf("Level %v (%v XP):\n\n%v", lvl, humanize.Comma(gc.Curve().XP(lvl)), preview))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/starshine-sys/oodles/common"
 )
 
@@ -42,81 +43,124 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 		return
 	}
 
-	// check blocked channels/categories
-	for _, blocked := range sc.BlockedChannels {
-		if m.ChannelID == discord.ChannelID(blocked) {
-			return
-		}
-	}
-	if ch, err := s.Channel(m.ChannelID); err == nil {
-		for _, blocked := range sc.BlockedCategories {
-			if ch.ParentID == discord.ChannelID(blocked) {
-				return
-			}
-		}
+	var parentID discord.ChannelID
+	ch, err := s.Channel(m.ChannelID)
+	if err == nil {
+		parentID = ch.ParentID
 	}
 
-	// check blocked roles
-	for _, blocked := range sc.BlockedRoles {
-		for _, r := range m.Member.RoleIDs {
-			if discord.RoleID(blocked) == r {
-				return
-			}
-		}
+	if sc.isBlocked(m.ChannelID, parentID, m.Member.RoleIDs) {
+		return
 	}
 
 	// increment the user's xp!
-	newXP, err := bot.incrementXP(m.GuildID, m.Author.ID)
+	newXP, err := bot.incrementXP(sc, m.Author.ID, bot.xpMultiplier(m.GuildID, m.Member, ch))
 	if err != nil {
 		common.Log.Errorf("Error updating XP for user: %v", err)
 		return
 	}
 
 	// only check for rewards on level up
-	oldLvl := LevelFromXP(uc.XP)
-	newLvl := LevelFromXP(newXP)
+	oldLvl := sc.Curve().Level(uc.XP)
+	newLvl := sc.Curve().Level(newXP)
 
 	if oldLvl >= newLvl {
 		return
 	}
 
-	reward := bot.getReward(m.GuildID, newLvl)
-	if reward == nil {
-		return
+	member := *m.Member
+	member.User = m.Author
+
+	bot.levelUp(s, sc, member, oldLvl, newLvl, newXP, m.ChannelID, discord.EmbedField{
+		Name:  "Message",
+		Value: fmt.Sprintf("https://discord.com/channels/%v/%v/%v", m.GuildID, m.ChannelID, m.ID),
+	})
+}
+
+// isBlocked returns true if the given channel, its category, or any of the given roles are blacklisted.
+func (gc GuildConfig) isBlocked(chID, parentID discord.ChannelID, roles []discord.RoleID) bool {
+	for _, blocked := range gc.BlockedChannels {
+		if chID == discord.ChannelID(blocked) {
+			return true
+		}
 	}
 
-	if !reward.RoleReward.IsValid() {
-		return
+	if parentID.IsValid() {
+		for _, blocked := range gc.BlockedCategories {
+			if parentID == discord.ChannelID(blocked) {
+				return true
+			}
+		}
 	}
 
-	// don't announce/log roles the user already has
-	for _, r := range m.Member.RoleIDs {
-		if r == reward.RoleReward {
-			return
+	for _, blocked := range gc.BlockedRoles {
+		for _, r := range roles {
+			if discord.RoleID(blocked) == r {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	err = s.AddRole(m.GuildID, m.Author.ID, reward.RoleReward, api.AddRoleData{
-		AuditLogReason: api.AuditLogReason(fmt.Sprintf("Level reward for reaching level %v", newLvl)),
-	})
+// giveReward gives the user any reward roles they should have at their new level but don't have yet.
+// source is shown in the reward log, to show where the user levelled up.
+// The reward role for newLvl is returned, even if the user already had it;
+// if there's no reward for that exact level, the highest role given is returned instead.
+func (bot *Bot) giveReward(s *state.State, sc GuildConfig, u discord.User, roles []discord.RoleID, newLvl int64, source discord.EmbedField) (role discord.RoleID) {
+	rewards, err := bot.getAllRewards(sc.ID)
 	if err != nil {
-		common.Log.Errorf("Error adding role to user: %v", err)
+		common.Log.Errorf("Error getting rewards: %v", err)
 		return
 	}
 
+	for _, r := range rewards {
+		if r.Level == newLvl && r.RoleReward.IsValid() {
+			role = r.RoleReward
+		}
+	}
+
+	// catch up on rewards for any levels that were skipped, too
+	var given []discord.RoleID
+	for _, id := range rewardRoles(rewards, newLvl, sc.StackRewards) {
+		// don't announce/log roles the user already has
+		if !id.IsValid() || hasRole(roles, id) || hasRole(given, id) {
+			continue
+		}
+
+		err = s.AddRole(sc.ID, u.ID, id, api.AddRoleData{
+			AuditLogReason: api.AuditLogReason(fmt.Sprintf("Level reward for reaching level %v", newLvl)),
+		})
+		if err != nil {
+			common.Log.Errorf("Error adding role to user: %v", err)
+			continue
+		}
+		given = append(given, id)
+	}
+	if len(given) == 0 {
+		return
+	}
+	if !role.IsValid() {
+		role = given[len(given)-1]
+	}
+
+	bot.removeLowerRewards(s, sc, u.ID, roles, given[len(given)-1])
+
 	if sc.RewardLog.IsValid() {
+		var mentions []string
+		for _, id := range given {
+			mentions = append(mentions, id.Mention())
+		}
+
 		e := discord.Embed{
 			Title:       "Level reward given",
-			Description: fmt.Sprintf("%v reached level `%v`.", m.Author.Mention(), newLvl),
+			Description: fmt.Sprintf("%v reached level `%v`.", u.Mention(), newLvl),
 			Fields: []discord.EmbedField{
 				{
 					Name:  "Reward given",
-					Value: reward.RoleReward.Mention(),
-				},
-				{
-					Name:  "Message",
-					Value: fmt.Sprintf("https://discord.com/channels/%v/%v/%v", m.GuildID, m.ChannelID, m.ID),
+					Value: strings.Join(mentions, ", "),
 				},
+				source,
 			},
 			Color: bot.Colour,
 		}
@@ -128,14 +172,28 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 	}
 
 	if sc.DMOnReward && sc.RewardText != "" {
+		// {lvl} predates templates, so keep supporting it
 		txt := strings.NewReplacer("{lvl}", fmt.Sprint(newLvl)).Replace(sc.RewardText)
 
-		ch, err := s.CreatePrivateChannel(m.Author.ID)
+		m := discord.Member{User: u, RoleIDs: roles}
+		if mem, err := s.Member(sc.ID, u.ID); err == nil {
+			m = *mem
+		}
+
+		exec, err := common.ExecTemplate(txt, bot.levelupData(s, sc, m, newLvl-1, newLvl, sc.Curve().XP(newLvl), role))
+		if err != nil {
+			bot.SendError("Error executing reward text in %v: %v", sc.ID, err)
+		} else {
+			txt = exec
+		}
+
+		ch, err := s.CreatePrivateChannel(u.ID)
 		if err == nil {
 			_, err = s.SendMessage(ch.ID, txt)
 			if err != nil {
-				common.Log.Errorf("Error sending reward message to %v: %v", m.Author.Tag(), err)
+				common.Log.Errorf("Error sending reward message to %v: %v", u.Tag(), err)
 			}
 		}
 	}
+	return role
 }
@@ -13,7 +13,14 @@ type Bot struct {
 func Init(b *bot.Bot) {
 	bot := &Bot{b}
 
+	bot.Scheduler.AddType(&xpEventToggle{})
+	bot.Scheduler.AddType(&weeklyLeaderboard{})
+	bot.Scheduler.AddType(&nolevelsExpiry{})
+	bot.Scheduler.AddType(&xpDecay{})
+	bot.scheduleNolevelsExpiries()
+
 	bot.Router.AddHandler(bot.messageCreate)
+	go bot.voiceXPLoop()
 
 	lvl := bot.Router.AddCommand(&bcr.Command{
 		Name:    "level",
@@ -31,23 +38,92 @@ func Init(b *bot.Bot) {
 		Command:           bot.levelCmd,
 	})
 
-	lvl.AddSubcommand(&bcr.Command{
+	bg := lvl.AddSubcommand(&bcr.Command{
 		Name:              "background",
 		Aliases:           []string{"bg"},
 		Summary:           "Choose a level background",
+		Usage:             "[random|custom]",
 		CustomPermissions: b.Checker,
 		Command:           bot.setBackground,
 	})
 
+	bg.AddSubcommand(&bcr.Command{
+		Name:    "upload",
+		Summary: "Upload your own level background",
+		Description: "Upload your own level background. It'll be cropped to fit the rank card, " +
+			"and will be used once it's approved by the staff.",
+		CustomPermissions: b.Checker,
+		Command:           bot.uploadBackground,
+	})
+
 	bot.Router.AddHandler(bot.chooseBackground)
+	bot.Router.AddHandler(bot.reviewBackground)
+
+	lvl.AddSubcommand(&bcr.Command{
+		Name:              "colour",
+		Aliases:           []string{"color"},
+		Summary:           "Set your rank card colour",
+		Usage:             "<hex code|reset>",
+		CustomPermissions: b.Checker,
+		Command:           bot.setColour,
+	})
+
+	lvl.AddSubcommand(&bcr.Command{
+		Name:              "theme",
+		Summary:           "Choose a rank card theme",
+		Usage:             "[theme]",
+		CustomPermissions: b.Checker,
+		Command:           bot.setTheme,
+	})
+
+	lvl.AddSubcommand(&bcr.Command{
+		Name:              "layout",
+		Summary:           "Choose a rank card layout",
+		Usage:             "[layout]",
+		CustomPermissions: b.Checker,
+		Command:           bot.setLayout,
+	})
+
+	lvl.AddSubcommand(&bcr.Command{
+		Name:              "pings",
+		Aliases:           []string{"ping"},
+		Summary:           "Choose whether you're pinged in level-up messages",
+		Usage:             "[on|off]",
+		CustomPermissions: b.Checker,
+		Command:           bot.setLevelPings,
+	})
+
+	lvl.AddSubcommand(&bcr.Command{
+		Name:    "chart",
+		Aliases: []string{"history"},
+		Summary: "Show a chart of your, or another user's, XP over time",
+		Usage:   "[user]",
+
+		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
+			fs.IntP("days", "d", 30, "The number of days to show.")
+
+			return fs
+		},
+
+		CustomPermissions: b.Checker,
+		Command:           bot.xpChart,
+	})
 
 	bot.Router.AddCommand(&bcr.Command{
 		Name:    "leaderboard",
 		Aliases: []string{"lb"},
 		Summary: "Show the server's leaderboard!",
+		Usage:   "[user]",
 
 		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
 			fs.BoolP("full", "f", false, "Show the full leaderboard, including people who left the server.")
+			fs.BoolP("text", "t", false, "Show the leaderboard as text, not an image.")
+			fs.StringP("around", "a", "", "Jump to the page with this user on it (or \"me\").")
+			fs.BoolP("weekly", "w", false, "Show the XP gained in the past week.")
+			fs.BoolP("monthly", "m", false, "Show the XP gained in the past month.")
+			fs.StringP("since", "s", "", "Show the XP gained since this date (YYYY-MM-DD) or for this duration.")
+			fs.StringP("until", "u", "", "Show the XP gained until this date (YYYY-MM-DD), used with --since.")
+			fs.BoolP("improved", "i", false, "Show who gained the most XP compared to the previous period (a week by default).")
 
 			return fs
 		},
@@ -56,6 +132,38 @@ func Init(b *bot.Bot) {
 		Command:           bot.leaderboard,
 	})
 
+	nl := bot.Router.AddCommand(&bcr.Command{
+		Name:              "nolevels",
+		Summary:           "Show users who can't gain XP",
+		CustomPermissions: b.Checker,
+		Command:           bot.nolevelsList,
+	})
+
+	nl.AddSubcommand(&bcr.Command{
+		Name:              "list",
+		Summary:           "Show users who can't gain XP",
+		CustomPermissions: b.Checker,
+		Command:           bot.nolevelsList,
+	})
+
+	nl.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Summary:           "Stop a user from gaining XP",
+		Usage:             "<user> [duration] [reason]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.nolevelsAdd,
+	})
+
+	nl.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Let a user gain XP again",
+		Usage:             "<user> [reason]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.nolevelsRemove,
+	})
+
 	cfg := bot.Router.AddCommand(&bcr.Command{
 		Name:              "levelcfg",
 		Aliases:           []string{"levelconfig"},
@@ -73,6 +181,49 @@ func Init(b *bot.Bot) {
 		Command:           bot.setConfig,
 	})
 
+	cfg.AddSubcommand(&bcr.Command{
+		Name:    "preview",
+		Aliases: []string{"curve"},
+		Summary: "Preview the XP needed for levels 1-100",
+		Description: "Preview the XP needed for levels 1-100, with the current level curve or the given one.\n" +
+			"Valid curves are `default`, `mee6`, `linear <xp per level>`, and `polynomial <coefficients...>` (starting with the constant term).",
+		Usage:             "[curve] [arguments...]",
+		CustomPermissions: b.Checker,
+		Command:           bot.previewCurve,
+	})
+
+	cfg.AddSubcommand(&bcr.Command{
+		Name:    "import",
+		Summary: "Import levels from an attached file",
+		Description: "Import levels from an attached file. Supported formats are MEE6 leaderboard dumps, Oodles exports, " +
+			"JSON lists of objects with `user_id` and `xp`, and CSV files with `user_id` and `xp` columns.\n" +
+			"Modes: `overwrite` replaces users' XP, `add` adds to it, `max` keeps whichever is higher.",
+
+		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
+			fs.StringP("mode", "m", ImportMax, "How to merge imported XP with existing XP (overwrite, add, max).")
+			fs.BoolP("dry-run", "n", false, "Only show what would change.")
+
+			return fs
+		},
+
+		CustomPermissions: b.Checker,
+		Command:           bot.importLevels,
+	})
+
+	cfg.AddSubcommand(&bcr.Command{
+		Name:    "export",
+		Summary: "Export this server's levels",
+
+		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
+			fs.StringP("format", "f", "json", "The format to export in (json, csv).")
+
+			return fs
+		},
+
+		CustomPermissions: b.Checker,
+		Command:           bot.exportLevels,
+	})
+
 	cfg.AddSubcommand(&bcr.Command{
 		Name:              "addbackground",
 		Aliases:           []string{"addbg"},
@@ -83,6 +234,159 @@ func Init(b *bot.Bot) {
 		Command:           bot.addBackground,
 	})
 
+	mult := cfg.AddSubcommand(&bcr.Command{
+		Name:              "multiplier",
+		Aliases:           []string{"multipliers", "mult"},
+		Summary:           "Show this server's XP multipliers",
+		CustomPermissions: b.Checker,
+		Command:           bot.multiplierList,
+	})
+
+	mult.AddSubcommand(&bcr.Command{
+		Name:              "set",
+		Aliases:           []string{"add"},
+		Summary:           "Set the XP multiplier for a channel, category, or role.",
+		Description:       "Set the XP multiplier for a channel, category, or role.\nChannel multipliers override category multipliers, and only a user's highest role multiplier is used.",
+		Usage:             "<channel or role> <multiplier>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.multiplierSet,
+	})
+
+	mult.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove the XP multiplier for a channel, category, or role.",
+		Usage:             "<channel or role>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.multiplierRemove,
+	})
+
+	ev := cfg.AddSubcommand(&bcr.Command{
+		Name:              "event",
+		Aliases:           []string{"events"},
+		Summary:           "Show this server's XP events",
+		CustomPermissions: b.Checker,
+		Command:           bot.xpEventList,
+	})
+
+	ev.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Summary:           "Schedule a server-wide XP event",
+		Description:       "Schedule a server-wide XP event. `start` is either `now` or how long until the event starts, `duration` is how long it lasts.",
+		Usage:             "<multiplier> <start> <duration> [name...]",
+		Args:              bcr.MinArgs(3),
+		CustomPermissions: b.Checker,
+		Command:           bot.xpEventAdd,
+	})
+
+	ev.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Aliases:           []string{"cancel"},
+		Summary:           "Remove an XP event",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.xpEventRemove,
+	})
+
+	rwd := cfg.AddSubcommand(&bcr.Command{
+		Name:              "reward",
+		Aliases:           []string{"rewards"},
+		Summary:           "Show this server's level rewards",
+		CustomPermissions: b.Checker,
+		Command:           bot.rewardList,
+	})
+
+	rwd.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Aliases:           []string{"set"},
+		Summary:           "Give a role to members when they reach a level",
+		Usage:             "<level> <role>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.rewardAdd,
+	})
+
+	rwd.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove the reward for a level",
+		Usage:             "<level>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.rewardRemove,
+	})
+
+	rwd.AddSubcommand(&bcr.Command{
+		Name:    "sync",
+		Summary: "Give or remove reward roles to match every member's current level",
+		Description: "Give or remove reward roles to match every member's current level.\n" +
+			"If `stack_rewards` is disabled, members only keep the role for the highest level they've reached.",
+		CustomPermissions: b.Checker,
+		Command:           bot.rewardSync,
+	})
+
+	msg := cfg.AddSubcommand(&bcr.Command{
+		Name:    "message",
+		Aliases: []string{"messages", "msg"},
+		Summary: "Show this server's level-up messages",
+		Description: "Show this server's level-up messages.\n" +
+			"Choose where level-ups are announced with `levelcfg set levelup_channel <channel|current|dm|off>`, " +
+			"and set the default message with `levelcfg set levelup_message <message>`.\n\n" + levelupTemplateHelp,
+		CustomPermissions: b.Checker,
+		Command:           bot.levelMessageList,
+	})
+
+	msg.AddSubcommand(&bcr.Command{
+		Name:    "set",
+		Aliases: []string{"add"},
+		Summary: "Set a custom level-up message for a level",
+		Description: "Set a custom level-up message for a level, used instead of the default message. " +
+			"If the message is empty after filling in the template, the level-up isn't announced.\n\n" + levelupTemplateHelp,
+		Usage:             "<level> <message>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.levelMessageSet,
+	})
+
+	msg.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove the custom level-up message for a level",
+		Usage:             "<level>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.levelMessageRemove,
+	})
+
+	msg.AddSubcommand(&bcr.Command{
+		Name:              "preview",
+		Aliases:           []string{"test"},
+		Summary:           "Preview the level-up message for a level",
+		Usage:             "[level]",
+		CustomPermissions: b.Checker,
+		Command:           bot.levelMessagePreview,
+	})
+
+	decay := cfg.AddSubcommand(&bcr.Command{
+		Name:    "decay",
+		Summary: "Show the XP decay log",
+		Description: "Show the XP decay log, for everyone or a single user.\n" +
+			"Configure decay with `levelcfg set decay_days <days>`, `levelcfg set decay_amount <percentage or XP>`, and `levelcfg set decay_floor <XP>`.",
+		Usage:             "[user]",
+		CustomPermissions: b.Checker,
+		Command:           bot.decayLog,
+	})
+
+	decay.AddSubcommand(&bcr.Command{
+		Name:              "reverse",
+		Aliases:           []string{"undo"},
+		Summary:           "Give back XP lost to decay",
+		Usage:             "<decay ID|user>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.decayReverse,
+	})
+
 	bl := cfg.AddSubcommand(&bcr.Command{
 		Name:              "blacklist",
 		Summary:           "Manage this server's level blacklist",
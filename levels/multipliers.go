@@ -0,0 +1,355 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"codeberg.org/eviedelta/detctime/durationparser"
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	botpkg "github.com/starshine-sys/oodles/bot"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// Multiplier target types
+const (
+	multiplierChannel  = "channel"
+	multiplierCategory = "category"
+	multiplierRole     = "role"
+)
+
+// the highest multiplier that can be set
+const maxMultiplier = 10
+
+type LevelMultiplier struct {
+	GuildID    discord.GuildID
+	TargetID   discord.Snowflake
+	TargetType string
+	Multiplier float64
+}
+
+// Mention returns a mention of the multiplier's target.
+func (m LevelMultiplier) Mention() string {
+	if m.TargetType == multiplierRole {
+		return discord.RoleID(m.TargetID).Mention()
+	}
+	return discord.ChannelID(m.TargetID).Mention()
+}
+
+type XPEvent struct {
+	ID         int64
+	GuildID    discord.GuildID
+	Name       string
+	Multiplier float64
+
+	Starts time.Time
+	Ends   time.Time
+	Active bool
+
+	StartEventID *int64
+	EndEventID   *int64
+}
+
+func (bot *Bot) getMultipliers(guildID discord.GuildID) (ms []LevelMultiplier, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB, &ms, "select * from level_multipliers where guild_id = $1 order by target_type, multiplier desc", guildID)
+	return
+}
+
+func (bot *Bot) getXPEvents(guildID discord.GuildID) (evs []XPEvent, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB, &evs, "select * from xp_events where guild_id = $1 order by starts", guildID)
+	return
+}
+
+// xpMultiplier returns the total XP multiplier for a message by the given member in the given channel.
+// Channel multipliers take precedence over category multipliers, and only the user's highest role multiplier is used.
+// Active XP events stack with both.
+func (bot *Bot) xpMultiplier(guildID discord.GuildID, m *discord.Member, ch *discord.Channel) float64 {
+	mult := 1.0
+
+	ms, err := bot.getMultipliers(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting XP multipliers: %v", err)
+	}
+
+	var (
+		channelMult, categoryMult, roleMult *float64
+	)
+	for i := range ms {
+		switch ms[i].TargetType {
+		case multiplierChannel:
+			if ch != nil && discord.ChannelID(ms[i].TargetID) == ch.ID {
+				channelMult = &ms[i].Multiplier
+			}
+		case multiplierCategory:
+			if ch != nil && discord.ChannelID(ms[i].TargetID) == ch.ParentID {
+				categoryMult = &ms[i].Multiplier
+			}
+		case multiplierRole:
+			for _, r := range m.RoleIDs {
+				if discord.RoleID(ms[i].TargetID) == r && (roleMult == nil || ms[i].Multiplier > *roleMult) {
+					roleMult = &ms[i].Multiplier
+				}
+			}
+		}
+	}
+
+	if channelMult != nil {
+		mult *= *channelMult
+	} else if categoryMult != nil {
+		mult *= *categoryMult
+	}
+	if roleMult != nil {
+		mult *= *roleMult
+	}
+
+	evs, err := bot.getXPEvents(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting XP events: %v", err)
+	}
+	for _, ev := range evs {
+		if ev.Active {
+			mult *= ev.Multiplier
+		}
+	}
+
+	return mult
+}
+
+func parseMultiplier(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(s), "x"), 64)
+	if err != nil || f < 0 || f > maxMultiplier {
+		return 0, fmt.Errorf("`%v` is not a valid multiplier (must be a number between 0 and %v)", s, maxMultiplier)
+	}
+	return f, nil
+}
+
+// parseMultiplierTarget parses a channel, category, or role.
+func (bot *Bot) parseMultiplierTarget(ctx *bcr.Context, s string) (id discord.Snowflake, typ string, err error) {
+	ch, err := ctx.ParseChannel(s)
+	if err == nil {
+		if ch.GuildID != ctx.Guild.ID || (ch.Type != discord.GuildText && ch.Type != discord.GuildNews && ch.Type != discord.GuildCategory) {
+			return 0, "", fmt.Errorf("invalid channel provided, must be in this guild and be a text or category channel")
+		}
+
+		if ch.Type == discord.GuildCategory {
+			return discord.Snowflake(ch.ID), multiplierCategory, nil
+		}
+		return discord.Snowflake(ch.ID), multiplierChannel, nil
+	}
+
+	r, err := ctx.ParseRole(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("input is not a valid role or channel")
+	}
+	return discord.Snowflake(r.ID), multiplierRole, nil
+}
+
+func (bot *Bot) multiplierList(ctx *bcr.Context) (err error) {
+	ms, err := bot.getMultipliers(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(ms) == 0 {
+		return ctx.SendfX("There are no XP multipliers set. Add one with `%vlevelcfg multiplier set`!", bot.Prefix())
+	}
+
+	var lines []string
+	for _, m := range ms {
+		lines = append(lines, fmt.Sprintf("%v (%v): **%vx**\n", m.Mention(), m.TargetType, m.Multiplier))
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("XP multipliers", bot.Colour, lines, 20),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) multiplierSet(ctx *bcr.Context) (err error) {
+	mult, err := parseMultiplier(ctx.Args[len(ctx.Args)-1])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+
+	id, typ, err := bot.parseMultiplierTarget(ctx, strings.Join(ctx.Args[:len(ctx.Args)-1], " "))
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+
+	_, err = bot.DB.Exec(context.Background(), `insert into level_multipliers
+	(guild_id, target_id, target_type, multiplier) values ($1, $2, $3, $4)
+	on conflict (guild_id, target_id) do update set target_type = $3, multiplier = $4`, ctx.Guild.ID, id, typ, mult)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	m := LevelMultiplier{TargetID: id, TargetType: typ}
+	return ctx.SendfX("Set the XP multiplier for %v to **%vx**!", m.Mention(), mult)
+}
+
+func (bot *Bot) multiplierRemove(ctx *bcr.Context) (err error) {
+	id, typ, err := bot.parseMultiplierTarget(ctx, ctx.RawArgs)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+
+	ct, err := bot.DB.Exec(context.Background(), "delete from level_multipliers where guild_id = $1 and target_id = $2", ctx.Guild.ID, id)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	m := LevelMultiplier{TargetID: id, TargetType: typ}
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("%v doesn't have an XP multiplier.", m.Mention())
+	}
+	return ctx.SendfX("Removed the XP multiplier for %v.", m.Mention())
+}
+
+func (bot *Bot) xpEventList(ctx *bcr.Context) (err error) {
+	evs, err := bot.getXPEvents(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(evs) == 0 {
+		return ctx.SendfX("There are no XP events scheduled. Add one with `%vlevelcfg event add`!", bot.Prefix())
+	}
+
+	var lines []string
+	for _, ev := range evs {
+		lines = append(lines, xpEventLine(ev)+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("XP events", bot.Colour, lines, 10),
+		10*time.Minute,
+	)
+	return err
+}
+
+func xpEventLine(ev XPEvent) string {
+	if ev.Active {
+		return fmt.Sprintf("`%v.` **%v** (%vx), active until <t:%v>", ev.ID, ev.Name, ev.Multiplier, ev.Ends.Unix())
+	}
+	return fmt.Sprintf("`%v.` **%v** (%vx), <t:%v> to <t:%v>", ev.ID, ev.Name, ev.Multiplier, ev.Starts.Unix(), ev.Ends.Unix())
+}
+
+func (bot *Bot) xpEventAdd(ctx *bcr.Context) (err error) {
+	mult, err := parseMultiplier(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+
+	start := time.Now().UTC()
+	if !strings.EqualFold(ctx.Args[1], "now") {
+		in, err := durationparser.Parse(ctx.Args[1])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "I couldn't parse `%v` as a duration.", ctx.Args[1])
+			return err
+		}
+		start = start.Add(in)
+	}
+
+	dur, err := durationparser.Parse(ctx.Args[2])
+	if err != nil || dur <= 0 {
+		_, err = ctx.Replyc(bcr.ColourRed, "I couldn't parse `%v` as a duration.", ctx.Args[2])
+		return err
+	}
+	end := start.Add(dur)
+
+	name := "XP event"
+	if len(ctx.Args) > 3 {
+		name = strings.Join(ctx.Args[3:], " ")
+		if r := []rune(name); len(r) > 100 {
+			name = string(r[:100])
+		}
+	}
+
+	ev := XPEvent{
+		GuildID:    ctx.Guild.ID,
+		Name:       name,
+		Multiplier: mult,
+		Starts:     start,
+		Ends:       end,
+	}
+
+	err = pgxscan.Get(context.Background(), bot.DB, &ev, `insert into xp_events
+	(guild_id, name, multiplier, starts, ends) values ($1, $2, $3, $4, $5)
+	returning *`, ev.GuildID, ev.Name, ev.Multiplier, ev.Starts, ev.Ends)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	startID, err := bot.Scheduler.Add(start, &xpEventToggle{EventID: ev.ID, Start: true})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	endID, err := bot.Scheduler.Add(end, &xpEventToggle{EventID: ev.ID, Start: false})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = bot.DB.Exec(context.Background(), "update xp_events set start_event_id = $1, end_event_id = $2 where id = $3", startID, endID, ev.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("Scheduled XP event %v", xpEventLine(ev))
+}
+
+func (bot *Bot) xpEventRemove(ctx *bcr.Context) (err error) {
+	id, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil {
+		return ctx.SendX("You must give a valid event ID.")
+	}
+
+	var ev XPEvent
+	err = pgxscan.Get(context.Background(), bot.DB, &ev, "delete from xp_events where id = $1 and guild_id = $2 returning *", id, ctx.Guild.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ctx.SendfX("There's no XP event with ID %v.", id)
+		}
+		return bot.Report(ctx, err)
+	}
+
+	for _, eventID := range []*int64{ev.StartEventID, ev.EndEventID} {
+		if eventID == nil {
+			continue
+		}
+		err = bot.Scheduler.Remove(*eventID)
+		if err != nil {
+			common.Log.Errorf("Error removing scheduled event %v for XP event %v: %v", *eventID, ev.ID, err)
+		}
+	}
+
+	return ctx.SendfX("Removed XP event **%v**.", ev.Name)
+}
+
+// xpEventToggle starts or ends an XP event.
+type xpEventToggle struct {
+	EventID int64 `json:"event_id"`
+	Start   bool  `json:"start"`
+}
+
+func (dat *xpEventToggle) Execute(ctx context.Context, id int64, bot *botpkg.Bot) error {
+	if dat.Start {
+		_, err := bot.DB.Exec(ctx, "update xp_events set active = true where id = $1", dat.EventID)
+		return err
+	}
+
+	_, err := bot.DB.Exec(ctx, "delete from xp_events where id = $1", dat.EventID)
+	return err
+}
+
+func (dat *xpEventToggle) Offset() time.Duration { return time.Minute }
@@ -0,0 +1,252 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeberg.org/eviedelta/detctime/durationparser"
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	botpkg "github.com/starshine-sys/oodles/bot"
+	"github.com/starshine-sys/oodles/common"
+)
+
+func (bot *Bot) getNolevels(guildID discord.GuildID, userID discord.UserID) (*Nolevels, error) {
+	var nl Nolevels
+	err := pgxscan.Get(context.Background(), bot.DB, &nl, "select * from nolevels where guild_id = $1 and user_id = $2", guildID, userID)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &nl, nil
+}
+
+// logNolevels sends an embed to the nolevels log channel, if one is set.
+func (bot *Bot) logNolevels(guildID discord.GuildID, e discord.Embed) {
+	gc, err := bot.getGuildConfig(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting guild config: %v", err)
+		return
+	}
+
+	if !gc.NolevelsLog.IsValid() {
+		return
+	}
+
+	e.Color = bot.Colour
+	e.Timestamp = discord.NowTimestamp()
+
+	s, _ := bot.Router.StateFromGuildID(guildID)
+	_, err = s.SendEmbeds(gc.NolevelsLog, e)
+	if err != nil {
+		bot.SendError("Error sending nolevels log: %v", err)
+	}
+}
+
+func (bot *Bot) nolevelsAdd(ctx *bcr.Context) (err error) {
+	u, err := ctx.ParseUser(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Send("User not found.")
+		return
+	}
+
+	if u.Bot {
+		return ctx.SendX("Bots can't gain XP anyway.")
+	}
+
+	var (
+		expires bool
+		expiry  time.Time
+		durStr  = "indefinitely"
+		reason  = "No reason given"
+		args    = ctx.Args[1:]
+	)
+
+	if len(args) > 0 {
+		dur, err := durationparser.Parse(args[0])
+		if err == nil {
+			expires = true
+			expiry = time.Now().UTC().Add(dur)
+			durStr = "for " + bcr.HumanizeDuration(bcr.DurationPrecisionSeconds, dur)
+			args = args[1:]
+		}
+	}
+	if len(args) > 0 {
+		reason = strings.Join(args, " ")
+	}
+
+	old, err := bot.getNolevels(ctx.Guild.ID, u.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	// if the user was already on the list, their old expiry is replaced
+	if old != nil && old.EventID != nil {
+		err = bot.Scheduler.Remove(*old.EventID)
+		if err != nil {
+			common.Log.Errorf("Error removing nolevels expiry event %v: %v", *old.EventID, err)
+		}
+	}
+
+	var eventID *int64
+	if expires {
+		id, err := bot.Scheduler.Add(expiry, &nolevelsExpiry{GuildID: ctx.Guild.ID, UserID: u.ID})
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		eventID = &id
+	}
+
+	_, err = bot.DB.Exec(context.Background(), `insert into nolevels
+	(guild_id, user_id, expires, expiry, reason, moderator, event_id) values ($1, $2, $3, $4, $5, $6, $7)
+	on conflict (guild_id, user_id) do update
+	set expires = $3, expiry = $4, reason = $5, moderator = $6, event_id = $7`,
+		ctx.Guild.ID, u.ID, expires, expiry, reason, ctx.Author.ID, eventID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	bot.logNolevels(ctx.Guild.ID, discord.Embed{
+		Title:       "User added to nolevels",
+		Description: fmt.Sprintf("%v (%v) can no longer gain XP, %v.", u.Mention(), u.Tag(), durStr),
+		Fields: []discord.EmbedField{
+			{Name: "Reason", Value: reason},
+			{Name: "Responsible moderator", Value: ctx.Author.Mention()},
+		},
+	})
+
+	return ctx.SendfX("%v can no longer gain XP, %v.", u.Tag(), durStr)
+}
+
+func (bot *Bot) nolevelsRemove(ctx *bcr.Context) (err error) {
+	u, err := ctx.ParseUser(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Send("User not found.")
+		return
+	}
+
+	nl, err := bot.getNolevels(ctx.Guild.ID, u.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if nl == nil {
+		return ctx.SendfX("%v isn't on the nolevels list.", u.Tag())
+	}
+
+	_, err = bot.DB.Exec(context.Background(), "delete from nolevels where guild_id = $1 and user_id = $2", ctx.Guild.ID, u.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if nl.EventID != nil {
+		err = bot.Scheduler.Remove(*nl.EventID)
+		if err != nil {
+			common.Log.Errorf("Error removing nolevels expiry event %v: %v", *nl.EventID, err)
+		}
+	}
+
+	reason := "No reason given"
+	if len(ctx.Args) > 1 {
+		reason = strings.Join(ctx.Args[1:], " ")
+	}
+
+	bot.logNolevels(ctx.Guild.ID, discord.Embed{
+		Title:       "User removed from nolevels",
+		Description: fmt.Sprintf("%v (%v) can gain XP again.", u.Mention(), u.Tag()),
+		Fields: []discord.EmbedField{
+			{Name: "Reason", Value: reason},
+			{Name: "Responsible moderator", Value: ctx.Author.Mention()},
+		},
+	})
+
+	return ctx.SendfX("%v can gain XP again.", u.Tag())
+}
+
+func (bot *Bot) nolevelsList(ctx *bcr.Context) (err error) {
+	var list []Nolevels
+	err = pgxscan.Select(context.Background(), bot.DB, &list, "select * from nolevels where guild_id = $1 order by user_id", ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(list) == 0 {
+		return ctx.SendX("There's nobody on the nolevels list.")
+	}
+
+	var lines []string
+	for _, nl := range list {
+		s := nl.UserID.Mention()
+		if nl.Expires {
+			s += fmt.Sprintf(", expires <t:%v:R>", nl.Expiry.Unix())
+		}
+		if nl.Reason != "" {
+			s += ": " + nl.Reason
+		}
+		lines = append(lines, s+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Nolevels", bot.Colour, lines, 10),
+		10*time.Minute,
+	)
+	return err
+}
+
+// scheduleNolevelsExpiries schedules expiry events for expiring nolevels entries that don't have one,
+// such as entries added before expiry was handled by the scheduler.
+func (bot *Bot) scheduleNolevelsExpiries() {
+	var list []Nolevels
+	err := pgxscan.Select(context.Background(), bot.DB, &list, `select * from nolevels n where n.expires
+	and not exists (select 1 from scheduled_events e where e.id = n.event_id)`)
+	if err != nil {
+		common.Log.Errorf("Error getting nolevels entries without expiry events: %v", err)
+		return
+	}
+
+	for _, nl := range list {
+		id, err := bot.Scheduler.Add(nl.Expiry, &nolevelsExpiry{GuildID: nl.GuildID, UserID: nl.UserID})
+		if err != nil {
+			common.Log.Errorf("Error scheduling nolevels expiry for %v: %v", nl.UserID, err)
+			continue
+		}
+
+		_, err = bot.DB.Exec(context.Background(), "update nolevels set event_id = $3 where guild_id = $1 and user_id = $2", nl.GuildID, nl.UserID, id)
+		if err != nil {
+			common.Log.Errorf("Error saving nolevels expiry event for %v: %v", nl.UserID, err)
+		}
+	}
+}
+
+// nolevelsExpiry removes a user from the nolevels list when their entry expires.
+type nolevelsExpiry struct {
+	GuildID discord.GuildID `json:"guild_id"`
+	UserID  discord.UserID  `json:"user_id"`
+}
+
+func (dat *nolevelsExpiry) Execute(ctx context.Context, id int64, bot *botpkg.Bot) error {
+	b := &Bot{bot}
+
+	// only delete the entry if it's the one this event was scheduled for
+	ct, err := bot.DB.Exec(ctx, "delete from nolevels where guild_id = $1 and user_id = $2 and event_id = $3", dat.GuildID, dat.UserID, id)
+	if err != nil {
+		common.Log.Errorf("Error removing expired nolevels entry: %v", err)
+		return botpkg.Reschedule
+	}
+	if ct.RowsAffected() == 0 {
+		return nil
+	}
+
+	b.logNolevels(dat.GuildID, discord.Embed{
+		Title:       "Nolevels expired",
+		Description: fmt.Sprintf("%v can gain XP again.", dat.UserID.Mention()),
+	})
+	return nil
+}
+
+func (dat *nolevelsExpiry) Offset() time.Duration { return time.Minute }
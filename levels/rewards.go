@@ -0,0 +1,231 @@
+package levels
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// rewardRoles returns the reward roles a member at the given level should have.
+// If stack is false, only the role for the highest level reached is returned.
+// rewards must be sorted by level, as returned by getAllRewards.
+func rewardRoles(rewards []LevelReward, lvl int64, stack bool) (roles []discord.RoleID) {
+	for _, r := range rewards {
+		if r.Level > lvl {
+			break
+		}
+
+		if stack {
+			roles = append(roles, r.RoleReward)
+		} else {
+			roles = []discord.RoleID{r.RoleReward}
+		}
+	}
+	return roles
+}
+
+func hasRole(roles []discord.RoleID, id discord.RoleID) bool {
+	for _, r := range roles {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeLowerRewards removes all reward roles except the given one from the member, if rewards don't stack.
+func (bot *Bot) removeLowerRewards(s *state.State, sc GuildConfig, userID discord.UserID, roles []discord.RoleID, keep discord.RoleID) {
+	if sc.StackRewards {
+		return
+	}
+
+	rewards, err := bot.getAllRewards(sc.ID)
+	if err != nil {
+		common.Log.Errorf("Error getting rewards: %v", err)
+		return
+	}
+
+	for _, r := range rewards {
+		if r.RoleReward == keep || !hasRole(roles, r.RoleReward) {
+			continue
+		}
+
+		err = s.RemoveRole(sc.ID, userID, r.RoleReward, api.AuditLogReason("Replaced by a higher level reward"))
+		if err != nil {
+			common.Log.Errorf("Error removing reward role %v from %v: %v", r.RoleReward, userID, err)
+		}
+	}
+}
+
+func (bot *Bot) rewardList(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	rewards, err := bot.getAllRewards(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(rewards) == 0 {
+		return ctx.SendfX("There are no level rewards. Add one with `%vlevelcfg reward add`!", bot.Prefix())
+	}
+
+	var lines []string
+	for _, r := range rewards {
+		lines = append(lines, fmt.Sprintf("Level %v: %v\n", r.Level, r.RoleReward.Mention()))
+	}
+
+	title := "Level rewards (stacking)"
+	if !gc.StackRewards {
+		title = "Level rewards (highest only)"
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator(title, bot.Colour, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) rewardAdd(ctx *bcr.Context) (err error) {
+	lvl, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil || lvl < 1 || lvl > maxLevel {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid level.", ctx.Args[0])
+		return err
+	}
+
+	r, err := ctx.ParseRole(strings.Join(ctx.Args[1:], " "))
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "Role not found.")
+		return err
+	}
+
+	if discord.GuildID(r.ID) == ctx.Guild.ID || r.Managed {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v can't be given as a level reward.", r.Mention())
+		return err
+	}
+
+	_, err = bot.DB.Exec(context.Background(), `insert into level_rewards (guild_id, lvl, role_reward) values ($1, $2, $3)
+	on conflict (guild_id, lvl) do update set role_reward = $3`, ctx.Guild.ID, lvl, r.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("Members will now get %v when they reach level %v!\nUse `%vlevelcfg reward sync` to give it to members who already reached that level.", r.Mention(), lvl, bot.Prefix())
+}
+
+func (bot *Bot) rewardRemove(ctx *bcr.Context) (err error) {
+	lvl, err := strconv.ParseInt(ctx.Args[0], 10, 64)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid level.", ctx.Args[0])
+		return err
+	}
+
+	ct, err := bot.DB.Exec(context.Background(), "delete from level_rewards where guild_id = $1 and lvl = $2", ctx.Guild.ID, lvl)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("There's no reward for level %v.", lvl)
+	}
+	return ctx.SendfX("Removed the reward for level %v. Members who already have the role will keep it.", lvl)
+}
+
+// rewardSync gives or removes reward roles for all members to match their current level.
+func (bot *Bot) rewardSync(ctx *bcr.Context) (err error) {
+	gc, err := bot.getGuildConfig(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	rewards, err := bot.getAllRewards(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if len(rewards) == 0 {
+		return ctx.SendX("There are no level rewards to sync.")
+	}
+
+	var levels []UserLevel
+	err = pgxscan.Select(context.Background(), bot.DB, &levels, "select * from levels where guild_id = $1", ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	// the same role can be the reward for multiple levels
+	var roles []discord.RoleID
+	for _, r := range rewards {
+		if !hasRole(roles, r.RoleReward) {
+			roles = append(roles, r.RoleReward)
+		}
+	}
+
+	xp := make(map[discord.UserID]int64, len(levels))
+	for _, l := range levels {
+		xp[l.UserID] = l.XP
+	}
+
+	ms, err := ctx.State.Members(ctx.Guild.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	msg, err := ctx.Sendf("Syncing level rewards for %v members, this might take a while...", len(ms))
+	if err != nil {
+		return err
+	}
+
+	var added, removed, failed int
+	for _, m := range ms {
+		if m.User.Bot {
+			continue
+		}
+
+		lvl := gc.Curve().Level(xp[m.User.ID])
+		wanted := rewardRoles(rewards, lvl, gc.StackRewards)
+
+		for _, id := range roles {
+			has := hasRole(m.RoleIDs, id)
+			should := hasRole(wanted, id)
+
+			switch {
+			case should && !has:
+				err = ctx.State.AddRole(ctx.Guild.ID, m.User.ID, id, api.AddRoleData{
+					AuditLogReason: api.AuditLogReason(fmt.Sprintf("Level reward sync (level %v)", lvl)),
+				})
+				if err != nil {
+					failed++
+					continue
+				}
+				added++
+			case !should && has:
+				err = ctx.State.RemoveRole(ctx.Guild.ID, m.User.ID, id, api.AuditLogReason(fmt.Sprintf("Level reward sync (level %v)", lvl)))
+				if err != nil {
+					failed++
+					continue
+				}
+				removed++
+			}
+		}
+	}
+
+	content := fmt.Sprintf("Done syncing level rewards! Added %v roles and removed %v roles.", added, removed)
+	if failed > 0 {
+		content += fmt.Sprintf("\n%v role changes failed, make sure I can manage all reward roles.", failed)
+	}
+
+	_, err = ctx.State.EditMessage(msg.ChannelID, msg.ID, content)
+	return err
+}
@@ -23,6 +23,20 @@ func (bot *Bot) setBackground(ctx *bcr.Context) (err error) {
 		return ctx.SendX("Background preference cleared! You'll now get a random background every time you pull up your level card.")
 	}
 
+	if ctx.RawArgs == "custom" {
+		ct, err := bot.DB.Exec(context.Background(), `update levels set background =
+		(select id from level_backgrounds where owner_id = $2 and status = 'approved' order by id desc limit 1)
+		where guild_id = $1 and user_id = $2
+		and exists(select id from level_backgrounds where owner_id = $2 and status = 'approved')`, ctx.Message.GuildID, ctx.Author.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		if ct.RowsAffected() == 0 {
+			return ctx.SendfX("You don't have an approved custom background. Upload one with `%vlevel background upload`!", bot.Prefix())
+		}
+		return ctx.SendX("Switched to your custom background!")
+	}
+
 	lbs, err := bot.backgroundMetadata()
 	if err != nil {
 		return bot.Report(ctx, err)
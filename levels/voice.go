@@ -0,0 +1,134 @@
+package levels
+
+import (
+	"math"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/starshine-sys/oodles/common"
+)
+
+// how often voice XP is given. voice_xp is per minute, so changing this also changes the amount given.
+const voiceXPInterval = time.Minute
+
+// voiceXPLoop gives voice XP every minute.
+// Voice sessions are tracked through the state's voice state cache, which is kept up to date by voice state update events.
+func (bot *Bot) voiceXPLoop() {
+	ticker := time.NewTicker(voiceXPInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		bot.giveVoiceXP(bot.DB.BotConfig.GuildID)
+	}
+}
+
+// giveVoiceXP gives XP to everyone in a non-AFK voice channel with at least one other unmuted person.
+func (bot *Bot) giveVoiceXP(guildID discord.GuildID) {
+	sc, err := bot.getGuildConfig(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting guild config: %v", err)
+		return
+	}
+
+	if !sc.LevelsEnabled || sc.VoiceXP <= 0 {
+		return
+	}
+
+	s, _ := bot.Router.StateFromGuildID(guildID)
+
+	g, err := s.Guild(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting guild %v: %v", guildID, err)
+		return
+	}
+
+	vss, err := s.VoiceStates(guildID)
+	if err != nil {
+		common.Log.Errorf("Error getting voice states for %v: %v", guildID, err)
+		return
+	}
+
+	channels := map[discord.ChannelID][]discord.VoiceState{}
+	for _, vs := range vss {
+		if !vs.ChannelID.IsValid() || vs.ChannelID == g.AFKChannelID {
+			continue
+		}
+		channels[vs.ChannelID] = append(channels[vs.ChannelID], vs)
+	}
+
+	for chID, states := range channels {
+		ch, err := s.Channel(chID)
+		if err != nil {
+			common.Log.Errorf("Error getting channel %v: %v", chID, err)
+			continue
+		}
+
+		for _, vs := range states {
+			if !voiceXPEligible(s, guildID, vs, states) {
+				continue
+			}
+
+			bot.giveMemberVoiceXP(s, sc, ch, vs.UserID)
+		}
+	}
+}
+
+// voiceXPEligible returns true if the given user isn't deafened, and there's at least one other unmuted person in their channel.
+func voiceXPEligible(s *state.State, guildID discord.GuildID, vs discord.VoiceState, states []discord.VoiceState) bool {
+	if vs.Deaf || vs.SelfDeaf {
+		return false
+	}
+
+	for _, other := range states {
+		if other.UserID == vs.UserID || other.Mute || other.SelfMute {
+			continue
+		}
+
+		m, err := s.Member(guildID, other.UserID)
+		if err == nil && m.User.Bot {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (bot *Bot) giveMemberVoiceXP(s *state.State, sc GuildConfig, ch *discord.Channel, userID discord.UserID) {
+	m, err := s.Member(sc.ID, userID)
+	if err != nil || m.User.Bot {
+		return
+	}
+
+	if sc.isBlocked(ch.ID, ch.ParentID, m.RoleIDs) || bot.isBlacklisted(sc.ID, userID) {
+		return
+	}
+
+	uc, err := bot.getUser(sc.ID, userID)
+	if err != nil {
+		common.Log.Errorf("Error getting user: %v", err)
+		return
+	}
+
+	xp := int64(math.Round(float64(sc.VoiceXP) * bot.xpMultiplier(sc.ID, m, ch)))
+	if xp <= 0 {
+		return
+	}
+
+	newXP, err := bot.incrementVoiceXP(sc.ID, userID, xp)
+	if err != nil {
+		common.Log.Errorf("Error updating voice XP for user: %v", err)
+		return
+	}
+
+	oldLvl := sc.Curve().Level(uc.XP)
+	newLvl := sc.Curve().Level(newXP)
+	if oldLvl >= newLvl {
+		return
+	}
+
+	bot.levelUp(s, sc, *m, oldLvl, newLvl, newXP, ch.ID, discord.EmbedField{
+		Name:  "Voice channel",
+		Value: ch.Mention(),
+	})
+}
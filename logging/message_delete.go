@@ -145,6 +145,29 @@ func (bot *Bot) messageDelete(m *gateway.MessageDeleteEvent) {
 		}...)
 	}
 
+	// the message's revisions are deleted along with it, so this has to happen first
+	revs, err := bot.DB.MessageRevisions(msg.ID)
+	if err != nil {
+		common.Log.Errorf("error getting message revisions: %v", err)
+	} else if len(revs) > 1 {
+		original := revs[0].Content
+		if len(original) > 1000 {
+			original = original[:1000] + "..."
+		}
+
+		e.Fields = append(e.Fields, []discord.EmbedField{
+			{
+				Name:   "Edits",
+				Value:  fmt.Sprintf("Edited %v time(s), last edit <t:%v:R>", len(revs)-1, revs[len(revs)-1].Created.Unix()),
+				Inline: true,
+			},
+			{
+				Name:  "Original content",
+				Value: original,
+			},
+		}...)
+	}
+
 	err = bot.DB.DeleteMessage(msg.ID)
 	if err != nil {
 		common.Log.Errorf("error deleting message from db: %v", err)
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+)
+
+var messageLinkRegex = regexp.MustCompile(`^https://(?:\w+\.)?discord(?:app)?\.com/channels/\d+/\d+/(\d+)$`)
+
+func (bot *Bot) messageHistory(ctx *bcr.Context) (err error) {
+	s := ctx.Args[0]
+	if groups := messageLinkRegex.FindStringSubmatch(s); groups != nil {
+		s = groups[1]
+	}
+
+	sf, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid message link or ID.", ctx.Args[0])
+		return err
+	}
+	id := discord.MessageID(sf)
+
+	msg, err := bot.DB.GetMessage(id)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			_, err = ctx.Replyc(bcr.ColourRed, "That message isn't in the database.")
+			return err
+		}
+		return bot.Report(ctx, err)
+	}
+
+	if msg.ServerID != ctx.Message.GuildID {
+		_, err = ctx.Replyc(bcr.ColourRed, "That message isn't in the database.")
+		return err
+	}
+
+	revs, err := bot.DB.MessageRevisions(id)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(revs) == 0 {
+		return ctx.SendfX("There's no history for message %v.", id)
+	}
+
+	var embeds []discord.Embed
+	for i, r := range revs {
+		title := fmt.Sprintf("Edit %v", i)
+		if i == 0 {
+			title = "Original message"
+		}
+
+		content := r.Content
+		if len(content) > 4000 {
+			content = content[:4000] + "..."
+		}
+
+		embeds = append(embeds, discord.Embed{
+			Title:       title,
+			Description: content,
+			Color:       bcr.ColourPurple,
+			Fields: []discord.EmbedField{
+				{
+					Name:   "Channel",
+					Value:  msg.ChannelID.Mention(),
+					Inline: true,
+				},
+				{
+					Name:   "Sender",
+					Value:  fmt.Sprintf("%v\n%v", msg.UserID.Mention(), msg.Username),
+					Inline: true,
+				},
+				{
+					Name:  "Link",
+					Value: fmt.Sprintf("https://discord.com/channels/%v/%v/%v", msg.ServerID, msg.ChannelID, msg.ID),
+				},
+			},
+			Footer: &discord.EmbedFooter{
+				Text: fmt.Sprintf("ID: %v | Version %v/%v", msg.ID, i+1, len(revs)),
+			},
+			Timestamp: discord.NewTimestamp(r.Created),
+		})
+	}
+
+	_, _, err = ctx.ButtonPages(embeds, 10*time.Minute)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
 	"github.com/starshine-sys/oodles/bot"
 )
 
@@ -44,4 +45,22 @@ func Init(bot *bot.Bot) {
 	b.Router.AddHandler(b.messageUpdate)
 	b.Router.AddHandler(b.messageDelete)
 	b.Router.AddHandler(b.bulkMessageDelete)
+
+	msg := b.Router.AddCommand(&bcr.Command{
+		Name:              "message",
+		Aliases:           []string{"msg"},
+		Summary:           "Show information about logged messages",
+		CustomPermissions: bot.Checker,
+		Command:           func(ctx *bcr.Context) error { return ctx.Help([]string{"message"}) },
+	})
+
+	msg.AddSubcommand(&bcr.Command{
+		Name:              "history",
+		Aliases:           []string{"edits"},
+		Summary:           "Show every version of an edited message",
+		Usage:             "<message link|ID>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: bot.Checker,
+		Command:           b.messageHistory,
+	})
 }
@@ -0,0 +1,551 @@
+package star
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/star/queries"
+)
+
+var customEmojiRegex = regexp.MustCompile(`^<(a)?:(\w+):(\d+)>$`)
+
+const defaultEmoji = "⭐"
+
+func (bot *Bot) showConfig(ctx *bcr.Context) (err error) {
+	boards, err := bot.queries.Boards(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(boards) == 0 {
+		return ctx.SendfX("This server doesn't have any starboards yet. Create one with `%vstarboard create <name> <channel> [emoji]`.", bot.Prefix())
+	}
+
+	overrides, err := bot.queries.Overrides(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	e := discord.Embed{
+		Title: "Starboards",
+		Color: bcr.ColourGold,
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("Use %vstarboard set <board> <key> <value> to change a setting.", bot.Prefix()),
+		},
+	}
+
+	for _, b := range boards {
+		channel := "None (disabled)"
+		if b.ChannelID != 0 {
+			channel = discord.ChannelID(b.ChannelID).Mention()
+		}
+
+		channels := "All"
+		if len(b.Channels) > 0 {
+			var mentions []string
+			for _, id := range b.Channels {
+				mentions = append(mentions, discord.ChannelID(id).Mention())
+			}
+			channels = strings.Join(mentions, ", ")
+		}
+
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name: b.Name,
+			Value: fmt.Sprintf("**Channel:** %v\n**Emoji:** %v\n**Reactions needed:** %v\n**Self-starring allowed:** %v\n**Starred from:** %v",
+				channel, b.Emoji, b.ReactionLimit, b.AllowSelfStar, channels),
+		})
+	}
+
+	if len(overrides) > 0 {
+		f := discord.EmbedField{Name: "Overrides"}
+		for _, o := range overrides {
+			f.Value += overrideString(o) + "\n"
+		}
+		if len(f.Value) > 1024 {
+			f.Value = fmt.Sprintf("%v overrides, use `%vstarboard override` to see them all.", len(overrides), bot.Prefix())
+		}
+		e.Fields = append(e.Fields, f)
+	}
+
+	return ctx.SendX("", e)
+}
+
+func overrideString(o queries.OverridesRow) string {
+	s := fmt.Sprintf("%v (%v): ", discord.ChannelID(o.ChannelID).Mention(), o.BoardName)
+	if o.Disabled {
+		return s + "disabled"
+	}
+
+	var settings []string
+	if o.Starboard != 0 {
+		settings = append(settings, "starboard "+discord.ChannelID(o.Starboard).Mention())
+	}
+	if o.Emoji != "" {
+		settings = append(settings, "emoji "+o.Emoji)
+	}
+	if o.ReactionLimit != 0 {
+		settings = append(settings, fmt.Sprintf("%v reactions needed", o.ReactionLimit))
+	}
+	if len(settings) == 0 {
+		return s + "no changes"
+	}
+	return s + strings.Join(settings, ", ")
+}
+
+// board gets a board by name, replying with an error if it doesn't exist.
+func (bot *Bot) board(ctx *bcr.Context, name string) (b queries.BoardRow, ok bool, err error) {
+	b, err = bot.queries.Board(context.Background(), int64(ctx.Guild.ID), name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			_, err = ctx.Replyc(bcr.ColourRed, "There's no starboard named %v. Use `%vstarboard` to see all starboards.", bcr.AsCode(name), bot.Prefix())
+			return b, false, err
+		}
+		return b, false, bot.Report(ctx, err)
+	}
+	return b, true, nil
+}
+
+// parseEmoji checks that the bot can react with the given emoji, and returns it in the format stored in the database.
+func (bot *Bot) parseEmoji(ctx *bcr.Context, s string) (string, error) {
+	var e discord.Emoji
+	if groups := customEmojiRegex.FindStringSubmatch(s); groups != nil {
+		sf, err := discord.ParseSnowflake(groups[3])
+		if err != nil {
+			return "", errors.New("invalid custom emoji")
+		}
+		e = discord.Emoji{ID: discord.EmojiID(sf), Name: groups[2], Animated: groups[1] == "a"}
+	} else {
+		if s == "" || strings.ContainsAny(s, " <>:") {
+			return "", errors.New("not an emoji")
+		}
+		e = discord.Emoji{Name: s}
+	}
+
+	// the easiest way to check that an emoji is valid and usable is to react with it
+	err := ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, e.APIString())
+	if err != nil {
+		return "", errors.New("I can't react with that emoji")
+	}
+	_ = ctx.State.Unreact(ctx.Message.ChannelID, ctx.Message.ID, e.APIString())
+
+	return emojiString(e), nil
+}
+
+// parseStarboardChannel parses a channel that starred messages can be posted in.
+func (bot *Bot) parseStarboardChannel(ctx *bcr.Context, s string) (discord.ChannelID, error) {
+	ch, err := ctx.ParseChannel(s)
+	if err != nil || ch.GuildID != ctx.Guild.ID || (ch.Type != discord.GuildText && ch.Type != discord.GuildNews) {
+		return 0, errors.New("must be a text channel in this server")
+	}
+
+	perms, err := ctx.State.Permissions(ch.ID, ctx.Bot.ID)
+	if err != nil {
+		return 0, err
+	}
+	if !perms.Has(discord.PermissionViewChannel | discord.PermissionSendMessages | discord.PermissionEmbedLinks) {
+		return 0, fmt.Errorf("I need the View Channel, Send Messages, and Embed Links permissions in %v", ch.Mention())
+	}
+
+	return ch.ID, nil
+}
+
+// parseOverrideChannel parses a channel or category that can have an override.
+func (bot *Bot) parseOverrideChannel(ctx *bcr.Context, s string) (*discord.Channel, error) {
+	ch, err := ctx.ParseChannel(s)
+	if err != nil || ch.GuildID != ctx.Guild.ID {
+		return nil, errors.New("must be a channel or category in this server")
+	}
+
+	switch ch.Type {
+	case discord.GuildText, discord.GuildNews, discord.GuildCategory:
+		return ch, nil
+	default:
+		return nil, errors.New("must be a text channel or category")
+	}
+}
+
+func parseReactionLimit(s string) (int, error) {
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 || limit > 1000 {
+		return 0, errors.New("must be a number between 1 and 1000")
+	}
+	return limit, nil
+}
+
+func (bot *Bot) createBoard(ctx *bcr.Context) (err error) {
+	name := ctx.Args[0]
+	if len(name) > 32 {
+		_, err = ctx.Replyc(bcr.ColourRed, "Starboard names can't be longer than 32 characters.")
+		return err
+	}
+
+	ch, err := bot.parseStarboardChannel(ctx, ctx.Args[1])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid starboard channel (%v)", ctx.Args[1], err)
+		return err
+	}
+
+	emoji := defaultEmoji
+	if len(ctx.Args) > 2 {
+		emoji, err = bot.parseEmoji(ctx, ctx.Args[2])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid emoji (%v)", ctx.Args[2], err)
+			return err
+		}
+	}
+
+	_, err = bot.queries.Board(context.Background(), int64(ctx.Guild.ID), name)
+	if err == nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "There's already a starboard named %v.", bcr.AsCode(name))
+		return err
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return bot.Report(ctx, err)
+	}
+
+	b, err := bot.queries.CreateBoard(context.Background(), queries.CreateBoardParams{
+		GuildID:   int64(ctx.Guild.ID),
+		Name:      name,
+		ChannelID: int64(ch),
+		Emoji:     emoji,
+	})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("Created the %v starboard! Messages with %v %v reactions will be posted in %v.\nUse `%vstarboard filter add %v <channel>` to only post messages from specific channels.",
+		bcr.AsCode(b.Name), b.ReactionLimit, b.Emoji, ch.Mention(), bot.Prefix(), b.Name)
+}
+
+func (bot *Bot) deleteBoard(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	yes, timeout := ctx.ConfirmButton(ctx.Author.ID, bcr.ConfirmData{
+		Message:   fmt.Sprintf("Are you sure you want to delete the %v starboard? Its overrides will also be deleted, but messages already posted will stay.", bcr.AsCode(b.Name)),
+		YesPrompt: "Delete",
+		YesStyle:  discord.DangerButtonStyle(),
+		NoPrompt:  "Cancel",
+		NoStyle:   discord.SecondaryButtonStyle(),
+		Timeout:   2 * time.Minute,
+	})
+	if !yes || timeout {
+		return ctx.SendX("Cancelled.")
+	}
+
+	_, err = bot.queries.DeleteBoard(context.Background(), b.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	return ctx.SendfX("Deleted the %v starboard.", bcr.AsCode(b.Name))
+}
+
+func (bot *Bot) setConfig(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	key := strings.ToLower(ctx.Args[1])
+	val := strings.Join(ctx.Args[2:], " ")
+
+	switch key {
+	case "channel", "starboard":
+		var id discord.ChannelID
+		if !strings.EqualFold(val, "off") && !strings.EqualFold(val, "none") {
+			id, err = bot.parseStarboardChannel(ctx, val)
+			if err != nil {
+				_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `channel` (%v, or `off`)", val, err)
+				return err
+			}
+		}
+
+		_, err = bot.queries.SetBoardChannel(context.Background(), int64(id), b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	case "emoji":
+		emoji, err := bot.parseEmoji(ctx, val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `emoji` (%v)", val, err)
+			return err
+		}
+
+		_, err = bot.queries.SetBoardEmoji(context.Background(), emoji, b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	case "limit", "reaction_limit":
+		limit, err := parseReactionLimit(val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `limit` (%v)", val, err)
+			return err
+		}
+
+		_, err = bot.queries.SetBoardReactionLimit(context.Background(), limit, b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	case "self_star", "allow_self_star":
+		allow, err := strconv.ParseBool(val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `self_star` (true or false)", val)
+			return err
+		}
+
+		_, err = bot.queries.SetBoardAllowSelfStar(context.Background(), allow, b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	default:
+		_, err = ctx.Replyc(bcr.ColourRed, "Sorry, but `%v` is not a valid configuration key. Valid keys are: channel, emoji, limit, self_star.", ctx.Args[1])
+		return err
+	}
+
+	_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+	return nil
+}
+
+// filterAdd restricts a board to messages from the given channels or categories.
+func (bot *Bot) filterAdd(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	var added []string
+	for _, arg := range ctx.Args[1:] {
+		ch, err := bot.parseOverrideChannel(ctx, arg)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid channel (%v)", arg, err)
+			return err
+		}
+
+		_, err = bot.queries.AddBoardChannel(context.Background(), int64(ch.ID), b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+		added = append(added, ch.Mention())
+	}
+
+	return ctx.SendfX("The %v starboard will now only post messages from these channels (and any others already added): %v", bcr.AsCode(b.Name), strings.Join(added, ", "))
+}
+
+func (bot *Bot) filterRemove(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	for _, arg := range ctx.Args[1:] {
+		ch, err := ctx.ParseChannel(arg)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid channel.", arg)
+			return err
+		}
+
+		_, err = bot.queries.RemoveBoardChannel(context.Background(), int64(ch.ID), b.ID)
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+	}
+
+	_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+	return nil
+}
+
+func (bot *Bot) filterClear(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	_, err = bot.queries.ClearBoardChannels(context.Background(), b.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendfX("The %v starboard will now post messages from all channels.", bcr.AsCode(b.Name))
+}
+
+func (bot *Bot) overrideList(ctx *bcr.Context) (err error) {
+	overrides, err := bot.queries.Overrides(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(overrides) == 0 {
+		return ctx.SendX("There are no starboard overrides in this server.")
+	}
+
+	var lines []string
+	for _, o := range overrides {
+		lines = append(lines, overrideString(o)+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Starboard overrides", bcr.ColourGold, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) overrideAdd(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	ch, err := bot.parseOverrideChannel(ctx, ctx.Args[1])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid channel (%v)", ctx.Args[1], err)
+		return err
+	}
+
+	key := strings.ToLower(ctx.Args[2])
+	val := strings.Join(ctx.Args[3:], " ")
+
+	switch key {
+	case "channel", "starboard":
+		id, err := bot.parseStarboardChannel(ctx, val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `starboard` (%v)", val, err)
+			return err
+		}
+
+		_, err = bot.queries.SetOverrideStarboard(context.Background(), queries.SetOverrideStarboardParams{
+			BoardID:   b.ID,
+			ChannelID: int64(ch.ID),
+			GuildID:   int64(ctx.Guild.ID),
+			Starboard: int64(id),
+		})
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	case "emoji":
+		emoji, err := bot.parseEmoji(ctx, val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `emoji` (%v)", val, err)
+			return err
+		}
+
+		_, err = bot.queries.SetOverrideEmoji(context.Background(), queries.SetOverrideEmojiParams{
+			BoardID:   b.ID,
+			ChannelID: int64(ch.ID),
+			GuildID:   int64(ctx.Guild.ID),
+			Emoji:     emoji,
+		})
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	case "limit", "reaction_limit":
+		limit, err := parseReactionLimit(val)
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid setting for `limit` (%v)", val, err)
+			return err
+		}
+
+		_, err = bot.queries.SetOverrideReactionLimit(context.Background(), queries.SetOverrideReactionLimitParams{
+			BoardID:       b.ID,
+			ChannelID:     int64(ch.ID),
+			GuildID:       int64(ctx.Guild.ID),
+			ReactionLimit: limit,
+		})
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+	default:
+		_, err = ctx.Replyc(bcr.ColourRed, "Sorry, but `%v` is not a valid override key. Valid keys are: starboard, emoji, limit.\nTo disable a starboard for a channel, use `%vstarboard disable`.", ctx.Args[2], bot.Prefix())
+		return err
+	}
+
+	_ = ctx.State.React(ctx.Message.ChannelID, ctx.Message.ID, "✅")
+	return nil
+}
+
+func (bot *Bot) overrideRemove(ctx *bcr.Context) (err error) {
+	b, ok, err := bot.board(ctx, ctx.Args[0])
+	if !ok {
+		return err
+	}
+
+	ch, err := bot.parseOverrideChannel(ctx, strings.Join(ctx.Args[1:], " "))
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid channel (%v)", strings.Join(ctx.Args[1:], " "), err)
+		return err
+	}
+
+	ct, err := bot.queries.RemoveOverride(context.Background(), b.ID, int64(ch.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("%v doesn't have an override for the %v starboard.", ch.Mention(), bcr.AsCode(b.Name))
+	}
+
+	return ctx.SendfX("Removed the %v starboard override for %v! It'll now use the board's settings.", bcr.AsCode(b.Name), ch.Mention())
+}
+
+// setDisabled returns a command that disables or re-enables starboards for a channel or category.
+// If no board is given, all boards are changed.
+func (bot *Bot) setDisabled(disabled bool) func(*bcr.Context) error {
+	return func(ctx *bcr.Context) (err error) {
+		ch, err := bot.parseOverrideChannel(ctx, ctx.Args[0])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid channel (%v)", ctx.Args[0], err)
+			return err
+		}
+
+		var boards []queries.BoardsRow
+		if len(ctx.Args) > 1 {
+			b, ok, err := bot.board(ctx, ctx.Args[1])
+			if !ok {
+				return err
+			}
+			boards = append(boards, queries.BoardsRow(b))
+		} else {
+			boards, err = bot.queries.Boards(context.Background(), int64(ctx.Guild.ID))
+			if err != nil {
+				return bot.Report(ctx, err)
+			}
+		}
+
+		for _, b := range boards {
+			_, err = bot.queries.SetOverrideDisabled(context.Background(), queries.SetOverrideDisabledParams{
+				BoardID:   b.ID,
+				ChannelID: int64(ch.ID),
+				GuildID:   int64(ctx.Guild.ID),
+				Disabled:  disabled,
+			})
+			if err != nil {
+				return bot.Report(ctx, err)
+			}
+		}
+
+		which := "any starboard"
+		if len(ctx.Args) > 1 && len(boards) == 1 {
+			which = "the " + bcr.AsCode(boards[0].Name) + " starboard"
+		}
+
+		if disabled {
+			return ctx.SendfX("Messages in %v will no longer be posted to %v.", ch.Mention(), which)
+		}
+		return ctx.SendfX("Messages in %v can be posted to %v again!", ch.Mention(), which)
+	}
+}
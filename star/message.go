@@ -4,37 +4,55 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/jackc/pgx/v4"
 	"github.com/starshine-sys/bcr"
 	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/db"
 	"github.com/starshine-sys/oodles/star/queries"
 )
 
-func (bot *Bot) sendOrUpdateMessage(m discord.Message, cfg queries.ChannelConfigRow, count int) error {
+func (bot *Bot) sendOrUpdateMessage(m discord.Message, board queries.ChannelBoardsRow, count int) error {
 	unlock := bot.acquire(m.ID)
 	defer unlock()
 
-	sm, err := bot.queries.StarboardMessage(context.Background(), int64(m.ID))
+	sm, err := bot.queries.BoardMessage(context.Background(), int64(m.ID), board.ID)
 	if err != nil {
 		if errors.Cause(err) == pgx.ErrNoRows {
-			if cfg.ReactionLimit > count {
+			if board.ReactionLimit > count {
 				return nil
 			}
 
-			content, embed := bot.starboardMessage(m, cfg.Emoji, count)
-			msg, err := bot.State.SendMessage(discord.ChannelID(cfg.Starboard), content, embed)
+			// don't leak NSFW messages into SFW starboards
+			if !bot.canPost(m.ChannelID, discord.ChannelID(board.Starboard)) {
+				return nil
+			}
+
+			pk := bot.pkMessage(m)
+
+			content, embeds := bot.starboardMessage(m, pk, board.Emoji, count)
+			msg, err := bot.State.SendMessage(discord.ChannelID(board.Starboard), content, embeds...)
 			if err != nil {
 				return err
 			}
 
+			authorID := m.Author.ID
+			if pk != nil && pk.UserID.IsValid() {
+				authorID = pk.UserID
+			}
+
 			_, err = bot.queries.InsertStarboard(context.Background(), queries.InsertStarboardParams{
-				MessageID:   int64(m.ID),
-				ChannelID:   int64(m.ChannelID),
-				GuildID:     int64(m.GuildID),
-				StarboardID: int64(msg.ID),
+				MessageID:          int64(m.ID),
+				ChannelID:          int64(m.ChannelID),
+				GuildID:            int64(m.GuildID),
+				StarboardID:        int64(msg.ID),
+				BoardID:            board.ID,
+				StarboardChannelID: int64(msg.ChannelID),
+				AuthorID:           int64(authorID),
+				StarCount:          count,
 			})
 			return err
 		}
@@ -43,31 +61,116 @@ func (bot *Bot) sendOrUpdateMessage(m discord.Message, cfg queries.ChannelConfig
 		return err
 	}
 
-	if cfg.ReactionLimit > count {
-		return bot.deleteMessage(cfg, sm.StarboardID)
+	// locked entries keep their post (and count) as is
+	if sm.Locked {
+		return nil
+	}
+
+	if board.ReactionLimit > count {
+		return bot.deleteMessage(sm.StarboardChannelID, sm.StarboardID)
 	}
 
 	// update existing message
-	content, embed := bot.starboardMessage(m, cfg.Emoji, count)
-	_, err = bot.State.EditMessage(discord.ChannelID(cfg.Starboard), discord.MessageID(sm.StarboardID), content, embed)
+	content, embeds := bot.starboardMessage(m, bot.pkMessage(m), board.Emoji, count)
+	_, err = bot.State.EditMessage(discord.ChannelID(sm.StarboardChannelID), discord.MessageID(sm.StarboardID), content, embeds...)
 	if err != nil {
-		_, err2 := bot.queries.RemoveStarboard(context.Background(), sm.MessageID)
+		_, err2 := bot.queries.RemoveBoardMessage(context.Background(), sm.MessageID, board.ID)
 		return errors.Append(err, err2)
 	}
 
-	return nil
+	_, err = bot.queries.SetStarCount(context.Background(), queries.SetStarCountParams{
+		StarCount: count,
+		MessageID: sm.MessageID,
+		BoardID:   board.ID,
+	})
+	return err
 }
 
-func (bot *Bot) deleteMessage(cfg queries.ChannelConfigRow, starboardID int64) error {
+// deleteMessage deletes a message from a starboard channel.
+func (bot *Bot) deleteMessage(channelID, starboardID int64) error {
 	_, err := bot.queries.RemoveStarboard(context.Background(), starboardID)
 	if err != nil {
 		return err
 	}
 
-	return bot.State.DeleteMessage(discord.ChannelID(cfg.Starboard), discord.MessageID(starboardID), "Remove starboard message")
+	return bot.State.DeleteMessage(discord.ChannelID(channelID), discord.MessageID(starboardID), "Remove starboard message")
+}
+
+// canPost returns false if the source channel is NSFW and the starboard isn't.
+func (bot *Bot) canPost(src, starboard discord.ChannelID) bool {
+	ch, err := bot.RootChannel(src)
+	if err != nil {
+		common.Log.Errorf("getting channel %v: %v", src, err)
+		return false
+	}
+
+	if !ch.NSFW {
+		return true
+	}
+
+	sb, err := bot.State.Channel(starboard)
+	if err != nil {
+		common.Log.Errorf("getting channel %v: %v", starboard, err)
+		return false
+	}
+	return sb.NSFW
+}
+
+// pkMessage returns the logged message if m was proxied by PluralKit, or nil otherwise.
+func (bot *Bot) pkMessage(m discord.Message) *db.Message {
+	if !m.WebhookID.IsValid() {
+		return nil
+	}
+
+	msg, err := bot.DB.GetMessage(m.ID)
+	if err != nil || msg.System == nil || msg.Member == nil {
+		return nil
+	}
+	return msg
+}
+
+var (
+	imageRegex = regexp.MustCompile(`(?i)\.(png|jpe?g|gif|webp)$`)
+	videoRegex = regexp.MustCompile(`(?i)\.(mp4|webm|mov)$`)
+)
+
+// Discord shows at most four images in a single gallery
+const maxImages = 4
+
+// Discord allows 25 fields per embed, leave room for the attachments, reply, sender, and source fields
+const maxCopiedFields = 25 - 4
+
+// the maximum total length of an embed's text
+const maxEmbedLength = 6000
+
+func isImage(a discord.Attachment) bool {
+	if a.ContentType != "" {
+		return strings.HasPrefix(a.ContentType, "image/")
+	}
+	return imageRegex.MatchString(a.Filename)
+}
+
+func isVideo(a discord.Attachment) bool {
+	if a.ContentType != "" {
+		return strings.HasPrefix(a.ContentType, "video/")
+	}
+	return videoRegex.MatchString(a.Filename)
+}
+
+// truncate shortens s to at most n characters, adding an ellipsis if anything was cut off.
+func truncate(s string, n int) string {
+	if n <= 3 {
+		return ""
+	}
+
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
 }
 
-func (bot *Bot) starboardMessage(m discord.Message, emoji string, count int) (string, discord.Embed) {
+func (bot *Bot) starboardMessage(m discord.Message, pk *db.Message, emoji string, count int) (string, []discord.Embed) {
 	// message content (count, emoji, link to channel)
 	content := fmt.Sprintf("**%v** %v <#%v>", count, emoji, m.ChannelID)
 
@@ -80,15 +183,11 @@ func (bot *Bot) starboardMessage(m discord.Message, emoji string, count int) (st
 		}
 	}
 
-	var attachmentLink string
-	if len(m.Attachments) > 0 {
-		match, _ := regexp.MatchString("\\.(png|jpg|jpeg|gif|webp)$", m.Attachments[0].URL)
-		if match {
-			attachmentLink = m.Attachments[0].URL
-		}
-	}
+	link := fmt.Sprintf("https://discord.com/channels/%v/%v/%v", m.GuildID, m.ChannelID, m.ID)
 
 	e := discord.Embed{
+		// all embeds with the same URL are shown as a single gallery
+		URL:         link,
 		Description: m.Content,
 		Author: &discord.EmbedAuthor{
 			Name: username,
@@ -99,33 +198,103 @@ func (bot *Bot) starboardMessage(m discord.Message, emoji string, count int) (st
 		},
 		Timestamp: discord.Timestamp(m.Timestamp.Time()),
 		Color:     bcr.ColourGold,
-		Image: &discord.EmbedImage{
-			URL: attachmentLink,
-		},
 	}
 
-	// do our best to "translate" the original message's embed (if any) to the starboard message
-	if len(m.Embeds) > 0 {
-		title := m.Embeds[0].Title
-		if title == "" && m.Embeds[0].Author != nil && m.Embeds[0].Author.Name != "" {
-			title = m.Embeds[0].Author.Name
+	if pk != nil {
+		e.Footer.Text += fmt.Sprintf(" | System ID: %v | Member ID: %v", *pk.System, *pk.Member)
+	}
+
+	// spoilered attachments are never shown inline, only linked
+	var images, files []string
+	for _, a := range m.Attachments {
+		spoiler := strings.HasPrefix(a.Filename, "SPOILER_")
+
+		switch {
+		case isImage(a) && !spoiler:
+			images = append(images, a.URL)
+		case isVideo(a):
+			files = append(files, fmt.Sprintf("Video: [%v](%v)", a.Filename, a.URL))
+		default:
+			files = append(files, fmt.Sprintf("[%v](%v)", a.Filename, a.URL))
 		}
 
-		value := m.Embeds[0].Description
-		if len(value) > 1000 {
-			value = e.Description[:999] + "..."
+		if spoiler {
+			files[len(files)-1] = "||" + files[len(files)-1] + "|| (spoiler)"
 		}
+	}
 
-		if title != "" && value != "" {
-			e.Fields = append(e.Fields, discord.EmbedField{Name: title, Value: value})
+	for _, s := range m.Stickers {
+		if s.FormatType == discord.StickerFormatPNG || s.FormatType == discord.StickerFormatAPNG {
+			images = append(images, fmt.Sprintf("https://media.discordapp.net/stickers/%v.png", s.ID))
 		}
+		files = append(files, "Sticker: "+s.Name)
+	}
 
-		for _, f := range m.Embeds[0].Fields {
-			if e.Length() > 4000 {
-				break
+	// do our best to "translate" the original message's embeds (if any) to the starboard message
+	var copied []discord.EmbedField
+	for _, me := range m.Embeds {
+		switch me.Type {
+		case discord.ImageEmbed, discord.GIFVEmbed:
+			if me.Thumbnail != nil {
+				images = append(images, me.Thumbnail.URL)
+			}
+			continue
+		case discord.VideoEmbed:
+			if me.URL != "" {
+				files = append(files, fmt.Sprintf("Video: %v", me.URL))
 			}
+			continue
+		case discord.NormalEmbed, "":
+		default:
+			continue
+		}
+
+		title := me.Title
+		if title == "" && me.Author != nil && me.Author.Name != "" {
+			title = me.Author.Name
+		}
+
+		value := truncate(me.Description, 1000)
+
+		if title != "" && value != "" {
+			copied = append(copied, discord.EmbedField{Name: truncate(title, 256), Value: value})
+		}
+		copied = append(copied, me.Fields...)
 
-			e.Fields = append(e.Fields, f)
+		if me.Image != nil {
+			images = append(images, me.Image.URL)
+		}
+	}
+
+	// the jump link (and sender) are always added, everything else only if it fits
+	var last []discord.EmbedField
+	if pk != nil && pk.UserID.IsValid() {
+		last = append(last, discord.EmbedField{
+			Name:   "Sent by",
+			Value:  pk.UserID.Mention(),
+			Inline: true,
+		})
+	}
+	last = append(last, discord.EmbedField{
+		Name:   "Source",
+		Value:  fmt.Sprintf("[Jump to message](%v)", link),
+		Inline: true,
+	})
+
+	budget := maxEmbedLength - e.Length()
+	for _, f := range last {
+		budget -= len(f.Name) + len(f.Value)
+	}
+
+	var extra []discord.EmbedField
+	if len(files) > 0 {
+		f := discord.EmbedField{
+			Name:  "Attachments",
+			Value: truncate(strings.Join(files, "\n"), 1024),
+		}
+		if len(f.Name)+len(f.Value) <= budget {
+			extra = append(extra, f)
+			budget -= len(f.Name) + len(f.Value)
 		}
 	}
 
@@ -138,25 +307,49 @@ func (bot *Bot) starboardMessage(m discord.Message, emoji string, count int) (st
 			value := ref.Content
 			if ref.Content == "" {
 				value = `*\[no content\]*`
-			} else if len(ref.Content) > 5600-e.Length() {
-				maxLen := 5600 - e.Length()
-				value = ref.Content[:maxLen] + "..."
+			} else {
+				// leave room for the link markdown
+				value = truncate(ref.Content, 900)
 			}
+			value = fmt.Sprintf("[%v](%v)", value, ref.URL())
 
-			if name != "" && value != "" {
-				e.Fields = append(e.Fields, discord.EmbedField{
+			if len(name)+len(value) <= budget {
+				extra = append(extra, discord.EmbedField{
 					Name:  name,
-					Value: fmt.Sprintf("[%v](%v)", value, ref.URL()),
+					Value: value,
 				})
+				budget -= len(name) + len(value)
 			}
 		}
 	}
 
-	// add jump link
-	e.Fields = append(e.Fields, discord.EmbedField{
-		Name:  "Source",
-		Value: fmt.Sprintf("[Jump to message](https://discord.com/channels/%v/%v/%v)", m.GuildID, m.ChannelID, m.ID),
-	})
+	for _, f := range copied {
+		if len(f.Name)+len(f.Value) > budget || len(e.Fields) >= maxCopiedFields {
+			break
+		}
+
+		e.Fields = append(e.Fields, f)
+		budget -= len(f.Name) + len(f.Value)
+	}
+	e.Fields = append(e.Fields, extra...)
+	e.Fields = append(e.Fields, last...)
+
+	if len(images) > maxImages {
+		images = images[:maxImages]
+	}
+
+	embeds := []discord.Embed{e}
+	for i, u := range images {
+		if i == 0 {
+			embeds[0].Image = &discord.EmbedImage{URL: u}
+			continue
+		}
+
+		embeds = append(embeds, discord.Embed{
+			URL:   link,
+			Image: &discord.EmbedImage{URL: u},
+		})
+	}
 
-	return content, e
+	return content, embeds
 }
@@ -0,0 +1,269 @@
+package star
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/star/queries"
+)
+
+var messageLinkRegex = regexp.MustCompile(`^https://(?:\w+\.)?discord(?:app)?\.com/channels/\d+/\d+/(\d+)$`)
+
+// blacklisted returns true if the user is on the guild's starboard blacklist.
+func (bot *Bot) blacklisted(guildID discord.GuildID, userID discord.UserID) bool {
+	bl, err := bot.queries.IsBlacklisted(context.Background(), int64(guildID), int64(userID))
+	if err != nil {
+		common.Log.Errorf("checking starboard blacklist: %v", err)
+	}
+	return bl
+}
+
+// ignored returns true if reactions on the message should be ignored entirely,
+// because it was trashed or its author is blacklisted.
+func (bot *Bot) ignored(m discord.Message) bool {
+	trashed, err := bot.queries.IsTrashed(context.Background(), int64(m.ID))
+	if err != nil {
+		common.Log.Errorf("checking starboard trash: %v", err)
+	}
+	if trashed {
+		return true
+	}
+
+	authorID := m.Author.ID
+	if pk := bot.pkMessage(m); pk != nil && pk.UserID.IsValid() {
+		authorID = pk.UserID
+	}
+	return bot.blacklisted(m.GuildID, authorID)
+}
+
+// parseMessageID parses a message link or ID.
+// If the message was posted to a starboard, the original message's ID is returned.
+func (bot *Bot) parseMessageID(s string) (discord.MessageID, []queries.StarboardMessagesRow, error) {
+	if groups := messageLinkRegex.FindStringSubmatch(s); groups != nil {
+		s = groups[1]
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sms, err := bot.queries.StarboardMessages(context.Background(), id)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(sms) > 0 {
+		return discord.MessageID(sms[0].MessageID), sms, nil
+	}
+	return discord.MessageID(id), nil, nil
+}
+
+func (bot *Bot) setLocked(locked bool) func(*bcr.Context) error {
+	return func(ctx *bcr.Context) (err error) {
+		id, sms, err := bot.parseMessageID(ctx.Args[0])
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid message link or ID.", ctx.Args[0])
+			return err
+		}
+
+		if len(sms) == 0 || sms[0].GuildID != int64(ctx.Guild.ID) {
+			_, err = ctx.Replyc(bcr.ColourRed, "That message isn't on any starboard.")
+			return err
+		}
+
+		_, err = bot.queries.SetLocked(context.Background(), locked, int64(id))
+		if err != nil {
+			return bot.Report(ctx, err)
+		}
+
+		if locked {
+			return ctx.SendfX("Locked message %v! Its starboard posts will no longer be updated.", id)
+		}
+		return ctx.SendfX("Unlocked message %v. Its starboard posts will be updated on the next reaction.", id)
+	}
+}
+
+func (bot *Bot) trash(ctx *bcr.Context) (err error) {
+	id, sms, err := bot.parseMessageID(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid message link or ID.", ctx.Args[0])
+		return err
+	}
+
+	if len(sms) > 0 && sms[0].GuildID != int64(ctx.Guild.ID) {
+		_, err = ctx.Replyc(bcr.ColourRed, "That message isn't in this server.")
+		return err
+	}
+
+	_, err = bot.queries.TrashMessage(context.Background(), queries.TrashMessageParams{
+		MessageID:   int64(id),
+		GuildID:     int64(ctx.Guild.ID),
+		ModeratorID: int64(ctx.Author.ID),
+		Reason:      strings.Join(ctx.Args[1:], " "),
+	})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	bot.deleteAllMessages(id, true)
+
+	return ctx.SendfX("Trashed message %v! It's been removed from all starboards and won't be posted again.", id)
+}
+
+func (bot *Bot) untrash(ctx *bcr.Context) (err error) {
+	id, _, err := bot.parseMessageID(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "`%v` is not a valid message link or ID.", ctx.Args[0])
+		return err
+	}
+
+	ct, err := bot.queries.UntrashMessage(context.Background(), int64(id), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("Message %v isn't trashed.", id)
+	}
+
+	return ctx.SendfX("Removed message %v from the trash. It'll be posted again on the next reaction, or use `%vstarboard rescan` to post it now.", id, bot.Prefix())
+}
+
+func (bot *Bot) trashList(ctx *bcr.Context) (err error) {
+	trash, err := bot.queries.Trash(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(trash) == 0 {
+		return ctx.SendX("There are no trashed messages.")
+	}
+
+	var lines []string
+	for _, t := range trash {
+		s := fmt.Sprintf("`%v` by %v", t.MessageID, discord.UserID(t.ModeratorID).Mention())
+		if t.Reason != "" {
+			s += ": " + truncate(t.Reason, 100)
+		}
+		lines = append(lines, s+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Trashed messages", bcr.ColourGold, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) blacklistAdd(ctx *bcr.Context) (err error) {
+	u, err := ctx.ParseUser(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "User not found.")
+		return err
+	}
+
+	_, err = bot.queries.AddBlacklist(context.Background(), queries.AddBlacklistParams{
+		GuildID:     int64(ctx.Guild.ID),
+		UserID:      int64(u.ID),
+		ModeratorID: int64(ctx.Author.ID),
+		Reason:      strings.Join(ctx.Args[1:], " "),
+	})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	// their existing stars no longer count
+	posts, err := bot.queries.UserReactedPosts(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	_, err = bot.queries.RemoveUserReactions(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	// this can take a lot of API calls for users who star a lot, so don't hold up the reply
+	go func() {
+		for _, p := range posts {
+			bot.recount(ctx.Guild.ID, discord.ChannelID(p.ChannelID), discord.MessageID(p.MessageID))
+		}
+	}()
+
+	return ctx.SendfX("Blacklisted %v from the starboard! Their messages can't be starred, and their stars don't count.\nMessages that are already on a starboard will stay there until they're trashed.", u.Mention())
+}
+
+// recount updates a message's starboard posts from its stored reactions.
+func (bot *Bot) recount(guildID discord.GuildID, channelID discord.ChannelID, messageID discord.MessageID) {
+	m, err := bot.State.Message(channelID, messageID)
+	if err != nil {
+		common.Log.Errorf("getting message %v: %v", messageID, err)
+		return
+	}
+	m.GuildID = guildID
+
+	ch, err := bot.RootChannel(channelID)
+	if err != nil {
+		common.Log.Errorf("getting root channel: %v", err)
+		return
+	}
+
+	boards, err := bot.activeBoards(guildID, ch)
+	if err != nil {
+		common.Log.Errorf("getting starboards: %v", err)
+		return
+	}
+
+	bot.updateBoards(*m, boards)
+}
+
+func (bot *Bot) blacklistRemove(ctx *bcr.Context) (err error) {
+	u, err := ctx.ParseUser(strings.Join(ctx.Args, " "))
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "User not found.")
+		return err
+	}
+
+	ct, err := bot.queries.RemoveBlacklist(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ctx.SendfX("%v isn't blacklisted.", u.Mention())
+	}
+
+	return ctx.SendfX("Removed %v from the starboard blacklist.", u.Mention())
+}
+
+func (bot *Bot) blacklistList(ctx *bcr.Context) (err error) {
+	bl, err := bot.queries.Blacklist(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(bl) == 0 {
+		return ctx.SendX("Nobody is blacklisted from the starboard.")
+	}
+
+	var lines []string
+	for _, b := range bl {
+		s := fmt.Sprintf("%v by %v", discord.UserID(b.UserID).Mention(), discord.UserID(b.ModeratorID).Mention())
+		if b.Reason != "" {
+			s += ": " + truncate(b.Reason, 100)
+		}
+		lines = append(lines, s+"\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Starboard blacklist", bcr.ColourGold, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}
@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/spf13/pflag"
+	"github.com/starshine-sys/bcr"
 	"github.com/starshine-sys/oodles/bot"
 	"github.com/starshine-sys/oodles/star/queries"
 )
@@ -30,37 +32,307 @@ func Init(b *bot.Bot) {
 	bot.Router.AddHandler(bot.reactionRemoveAll)
 	bot.Router.AddHandler(bot.reactionRemoveEmoji)
 	bot.Router.AddHandler(bot.messageDelete)
+	go bot.consistencyLoop()
+
+	sb := bot.Router.AddCommand(&bcr.Command{
+		Name:              "starboard",
+		Aliases:           []string{"sb"},
+		Summary:           "Show this server's starboards",
+		CustomPermissions: b.Checker,
+		Command:           bot.showConfig,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "create",
+		Summary:           "Create a new starboard",
+		Description:       "Create a new starboard. Each starboard has its own channel, emoji, and reaction limit, and can be limited to specific channels with `starboard filter`.",
+		Usage:             "<name> <channel> [emoji]",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.createBoard,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "delete",
+		Summary:           "Delete a starboard",
+		Usage:             "<board>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.deleteBoard,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:    "set",
+		Summary: "Set a starboard setting",
+		Description: "Set a starboard setting. Valid keys are:\n" +
+			"`channel`: the starboard channel, or `off` to disable the starboard\n" +
+			"`emoji`: the emoji used to star messages\n" +
+			"`limit`: the number of reactions needed for a message to be starred\n" +
+			"`self_star`: whether users can star their own messages",
+		Usage:             "<board> <key> <value>",
+		Args:              bcr.MinArgs(3),
+		CustomPermissions: b.Checker,
+		Command:           bot.setConfig,
+	})
+
+	f := sb.AddSubcommand(&bcr.Command{
+		Name:              "filter",
+		Summary:           "Limit a starboard to specific channels",
+		Description:       "Limit a starboard to messages from specific channels or categories. Starboards without any channels post messages from all channels.",
+		CustomPermissions: b.Checker,
+		Command:           func(ctx *bcr.Context) error { return ctx.Help([]string{"starboard", "filter"}) },
+	})
+
+	f.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Summary:           "Add channels or categories to a starboard",
+		Usage:             "<board> <channels...>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.filterAdd,
+	})
+
+	f.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove channels or categories from a starboard",
+		Usage:             "<board> <channels...>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.filterRemove,
+	})
+
+	f.AddSubcommand(&bcr.Command{
+		Name:              "clear",
+		Summary:           "Let a starboard post messages from all channels again",
+		Usage:             "<board>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.filterClear,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "disable",
+		Summary:           "Stop messages in a channel or category from being starred",
+		Description:       "Stop messages in a channel or category from being starred. If no starboard is given, this applies to all starboards.",
+		Usage:             "<channel|category> [board]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.setDisabled(true),
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "enable",
+		Summary:           "Let messages in a channel or category be starred again",
+		Description:       "Let messages in a channel or category be starred again. If no starboard is given, this applies to all starboards.",
+		Usage:             "<channel|category> [board]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.setDisabled(false),
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "rescan",
+		Summary:           "Rescan a channel's reactions and fix its starboard posts",
+		Description:       "Fetch reactions on messages in a channel from Discord, then post, update, or delete starboard messages to match. By default, messages from the past week are rescanned.",
+		Usage:             "<channel> [since]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.rescan,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "lock",
+		Summary:           "Lock a starred message, freezing its starboard posts",
+		Usage:             "<message link|ID>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.setLocked(true),
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "unlock",
+		Summary:           "Unlock a starred message",
+		Usage:             "<message link|ID>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.setLocked(false),
+	})
+
+	trash := sb.AddSubcommand(&bcr.Command{
+		Name:              "trash",
+		Summary:           "Remove a message from all starboards and stop it from being posted again",
+		Usage:             "<message link|ID> [reason]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.trash,
+	})
+
+	trash.AddSubcommand(&bcr.Command{
+		Name:              "list",
+		Summary:           "Show trashed messages",
+		CustomPermissions: b.Checker,
+		Command:           bot.trashList,
+	})
+
+	sb.AddSubcommand(&bcr.Command{
+		Name:              "untrash",
+		Summary:           "Let a trashed message be posted again",
+		Usage:             "<message ID>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.untrash,
+	})
+
+	bl := sb.AddSubcommand(&bcr.Command{
+		Name:              "blacklist",
+		Summary:           "Show users blacklisted from the starboard",
+		CustomPermissions: b.Checker,
+		Command:           bot.blacklistList,
+	})
+
+	bl.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Summary:           "Blacklist a user from the starboard",
+		Description:       "Blacklist a user from the starboard. Their messages can't be starred, and their stars don't count.",
+		Usage:             "<user> [reason]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.blacklistAdd,
+	})
+
+	bl.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove a user from the starboard blacklist",
+		Usage:             "<user>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: b.Checker,
+		Command:           bot.blacklistRemove,
+	})
+
+	o := sb.AddSubcommand(&bcr.Command{
+		Name:              "override",
+		Aliases:           []string{"overrides"},
+		Summary:           "Show this server's starboard overrides",
+		CustomPermissions: b.Checker,
+		Command:           bot.overrideList,
+	})
+
+	o.AddSubcommand(&bcr.Command{
+		Name:              "list",
+		Summary:           "Show this server's starboard overrides",
+		CustomPermissions: b.Checker,
+		Command:           bot.overrideList,
+	})
+
+	o.AddSubcommand(&bcr.Command{
+		Name:    "add",
+		Aliases: []string{"set"},
+		Summary: "Override a starboard setting for a channel or category",
+		Description: "Override a starboard's settings for a channel or category. Channel overrides take priority over category overrides.\n" +
+			"Valid keys are `starboard` (the channel to post starred messages in), `emoji`, and `limit`.",
+		Usage:             "<board> <channel|category> <key> <value>",
+		Args:              bcr.MinArgs(4),
+		CustomPermissions: b.Checker,
+		Command:           bot.overrideAdd,
+	})
+
+	o.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Summary:           "Remove a channel or category's starboard override",
+		Usage:             "<board> <channel|category>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: b.Checker,
+		Command:           bot.overrideRemove,
+	})
+
+	stars := bot.Router.AddCommand(&bcr.Command{
+		Name:              "stars",
+		Summary:           "Show your, or another user's, starboard stats",
+		Usage:             "[user]",
+		CustomPermissions: b.Checker,
+		Command:           bot.userStats,
+	})
+
+	stars.AddSubcommand(&bcr.Command{
+		Name:    "top",
+		Summary: "Show the most starred messages",
+
+		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
+			fs.StringP("board", "b", "", "Only show messages from this starboard.")
+			fs.BoolP("weekly", "w", false, "Only show messages from the past week.")
+			fs.BoolP("monthly", "m", false, "Only show messages from the past month.")
+			fs.StringP("since", "s", "", "Only show messages sent since this date (YYYY-MM-DD) or in this duration.")
+
+			return fs
+		},
+
+		CustomPermissions: b.Checker,
+		Command:           bot.topMessages,
+	})
+
+	stars.AddSubcommand(&bcr.Command{
+		Name:              "random",
+		Summary:           "Show a random starred message",
+		Usage:             "[board]",
+		CustomPermissions: b.Checker,
+		Command:           bot.randomMessage,
+	})
+
+	stars.AddSubcommand(&bcr.Command{
+		Name:              "givers",
+		Aliases:           []string{"leaderboard", "lb"},
+		Summary:           "Show who has starred the most messages",
+		CustomPermissions: b.Checker,
+		Command:           bot.starGivers,
+	})
 }
 
-func (bot *Bot) override(guildID discord.GuildID, channelID discord.ChannelID, categoryID discord.ChannelID) (row queries.ChannelConfigRow, err error) {
-	channelHasOverride, err := bot.queries.HasOverride(context.Background(), int64(channelID))
+// emojiBoards returns the enabled boards in the guild that messages in the channel can be posted to with the given emoji.
+func (bot *Bot) emojiBoards(guildID discord.GuildID, ch *discord.Channel, emoji string) (boards []queries.ChannelBoardsRow, err error) {
+	all, err := bot.activeBoards(guildID, ch)
 	if err != nil {
-		return row, err
+		return nil, err
 	}
 
-	if channelHasOverride {
-		return bot.queries.ChannelConfig(context.Background(), int64(channelID), int64(guildID))
+	for _, b := range all {
+		if b.Emoji == emoji {
+			boards = append(boards, b)
+		}
 	}
-
-	return bot.queries.ChannelConfig(context.Background(), int64(categoryID), int64(guildID))
+	return boards, nil
 }
 
-func (bot *Bot) addReaction(message discord.MessageID, user discord.UserID) (int64, error) {
-	_, err := bot.queries.AddReaction(context.Background(), int64(user), int64(message))
+// activeBoards returns the enabled boards in the guild that messages in the channel can be posted to.
+func (bot *Bot) activeBoards(guildID discord.GuildID, ch *discord.Channel) (boards []queries.ChannelBoardsRow, err error) {
+	all, err := bot.queries.ChannelBoards(context.Background(), queries.ChannelBoardsParams{
+		ChannelID:  int64(ch.ID),
+		CategoryID: int64(ch.ParentID),
+		GuildID:    int64(guildID),
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return bot.queries.ReactionCount(context.Background(), int64(message))
+	for _, b := range all {
+		if b.Disabled || b.Starboard == 0 {
+			continue
+		}
+		boards = append(boards, b)
+	}
+	return boards, nil
 }
 
-func (bot *Bot) removeReaction(message discord.MessageID, user discord.UserID) (int64, error) {
-	_, err := bot.queries.RemoveReaction(context.Background(), int64(user), int64(message))
-	if err != nil {
-		return 0, err
+// reactionCount returns the number of reactions on a message for the given board.
+func (bot *Bot) reactionCount(m discord.Message, board queries.ChannelBoardsRow) (int64, error) {
+	if board.AllowSelfStar {
+		return bot.queries.ReactionCount(context.Background(), int64(m.ID), board.Emoji)
 	}
 
-	return bot.queries.ReactionCount(context.Background(), int64(message))
+	return bot.queries.ReactionCountWithout(context.Background(), queries.ReactionCountWithoutParams{
+		MessageID: int64(m.ID),
+		Emoji:     board.Emoji,
+		UserID:    int64(m.Author.ID),
+	})
 }
 
 func emojiString(e discord.Emoji) string {
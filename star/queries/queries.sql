@@ -1,49 +1,226 @@
--- name: ChannelConfig :one
+-- name: ChannelBoards :many
 select
-coalesce(o.starboard, s.channel_id) as starboard,
-coalesce(o.disabled, false) as disabled,
-coalesce(o.emoji, s.emoji) as emoji,
-coalesce(o.reaction_limit, s.reaction_limit) as reaction_limit,
-s.allow_self_star as allow_self_star
-from starboard s
-left outer join starboard_overrides o on o.channel_id = pggen.arg('channel_id')
-where s.guild_id = pggen.arg('guild_id')
-limit 1;
+b.id,
+b.name,
+coalesce(co.starboard, po.starboard, b.channel_id) as starboard,
+coalesce(co.disabled, po.disabled, false) as disabled,
+coalesce(co.emoji, po.emoji, b.emoji) as emoji,
+coalesce(co.reaction_limit, po.reaction_limit, b.reaction_limit) as reaction_limit,
+b.allow_self_star
+from starboards b
+left outer join starboard_overrides co on co.board_id = b.id and co.channel_id = pggen.arg('channel_id')
+left outer join starboard_overrides po on po.board_id = b.id and po.channel_id = pggen.arg('category_id')
+where b.guild_id = pggen.arg('guild_id')
+and (cardinality(b.channels) = 0 or pggen.arg('channel_id') = any(b.channels) or pggen.arg('category_id') = any(b.channels))
+order by b.id;
 
--- name: HasOverride :one
-select exists(select channel_id from starboard_overrides where channel_id = pggen.arg('channel_id'));
-
--- name: StarboardMessage :one
+-- name: StarboardMessages :many
 select * from starboard_messages
 where message_id = pggen.arg('id') or starboard_id = pggen.arg('id');
 
+-- name: BoardMessage :one
+select * from starboard_messages
+where message_id = pggen.arg('message_id') and board_id = pggen.arg('board_id');
+
 -- name: AddReaction :exec
-insert into starboard_reactions (user_id, message_id) values (pggen.arg('user_id'), pggen.arg('message_id')) on conflict (user_id, message_id) do nothing;
+insert into starboard_reactions (user_id, message_id, emoji, guild_id) values (pggen.arg('user_id'), pggen.arg('message_id'), pggen.arg('emoji'), pggen.arg('guild_id')) on conflict (user_id, message_id, emoji) do nothing;
 
 -- name: RemoveReaction :exec
-delete from starboard_reactions where user_id = pggen.arg('user_id') and message_id = pggen.arg('message_id');
+delete from starboard_reactions where user_id = pggen.arg('user_id') and message_id = pggen.arg('message_id') and emoji = pggen.arg('emoji');
 
 -- name: ReactionCount :one
-select count(*) from starboard_reactions where message_id = pggen.arg('message_id');
+select count(*) from starboard_reactions where message_id = pggen.arg('message_id') and emoji = pggen.arg('emoji');
+
+-- name: ReactionCountWithout :one
+select count(*) from starboard_reactions where message_id = pggen.arg('message_id') and emoji = pggen.arg('emoji') and user_id <> pggen.arg('user_id');
 
 -- name: RemoveAllReactions :exec
 delete from starboard_reactions where message_id = pggen.arg('message_id');
 
+-- name: RemoveEmojiReactions :exec
+delete from starboard_reactions where message_id = pggen.arg('message_id') and emoji = pggen.arg('emoji');
+
+-- name: RecentPosts :many
+select distinct message_id, channel_id from starboard_messages
+where message_id >= pggen.arg('min_id');
+
 -- name: RemoveStarboard :exec
 delete from starboard_messages where message_id = pggen.arg('message_id')
 or starboard_id = pggen.arg('message_id');
 
+-- name: RemoveBoardMessage :exec
+delete from starboard_messages where message_id = pggen.arg('message_id') and board_id = pggen.arg('board_id');
+
 -- name: InsertStarboard :one
 insert into starboard_messages
-(message_id, channel_id, guild_id, starboard_id)
+(message_id, channel_id, guild_id, starboard_id, board_id, starboard_channel_id, author_id, star_count)
 values (
     pggen.arg('message_id'),
     pggen.arg('channel_id'),
     pggen.arg('guild_id'),
-    pggen.arg('starboard_id')
+    pggen.arg('starboard_id'),
+    pggen.arg('board_id'),
+    pggen.arg('starboard_channel_id'),
+    pggen.arg('author_id'),
+    pggen.arg('star_count')
 ) returning *;
 
--- name: GetStarboard :one
-select * from starboard_messages
-where message_id = pggen.arg('message_id')
-or starboard_id = pggen.arg('message_id');
+-- name: SetStarCount :exec
+update starboard_messages set star_count = pggen.arg('star_count')
+where message_id = pggen.arg('message_id') and board_id = pggen.arg('board_id');
+
+-- name: Boards :many
+select * from starboards where guild_id = pggen.arg('guild_id') order by id;
+
+-- name: Board :one
+select * from starboards where guild_id = pggen.arg('guild_id') and lower(name) = lower(pggen.arg('name'));
+
+-- name: CreateBoard :one
+insert into starboards (guild_id, name, channel_id, emoji)
+values (pggen.arg('guild_id'), pggen.arg('name'), pggen.arg('channel_id'), pggen.arg('emoji'))
+returning *;
+
+-- name: DeleteBoard :exec
+delete from starboards where id = pggen.arg('id');
+
+-- name: SetBoardChannel :exec
+update starboards set channel_id = pggen.arg('channel_id') where id = pggen.arg('id');
+
+-- name: SetBoardEmoji :exec
+update starboards set emoji = pggen.arg('emoji') where id = pggen.arg('id');
+
+-- name: SetBoardReactionLimit :exec
+update starboards set reaction_limit = pggen.arg('reaction_limit') where id = pggen.arg('id');
+
+-- name: SetBoardAllowSelfStar :exec
+update starboards set allow_self_star = pggen.arg('allow_self_star') where id = pggen.arg('id');
+
+-- name: AddBoardChannel :exec
+update starboards set channels = array_append(channels, pggen.arg('channel_id'))
+where id = pggen.arg('id') and not pggen.arg('channel_id') = any(channels);
+
+-- name: RemoveBoardChannel :exec
+update starboards set channels = array_remove(channels, pggen.arg('channel_id')) where id = pggen.arg('id');
+
+-- name: ClearBoardChannels :exec
+update starboards set channels = array[]::bigint[] where id = pggen.arg('id');
+
+-- name: Overrides :many
+select
+o.board_id,
+b.name as board_name,
+o.channel_id,
+coalesce(o.disabled, false) as disabled,
+coalesce(o.starboard, 0) as starboard,
+coalesce(o.emoji, '') as emoji,
+coalesce(o.reaction_limit, 0) as reaction_limit
+from starboard_overrides o
+join starboards b on b.id = o.board_id
+where o.guild_id = pggen.arg('guild_id')
+order by o.board_id, o.channel_id;
+
+-- name: SetOverrideDisabled :exec
+insert into starboard_overrides (board_id, channel_id, guild_id, disabled)
+values (pggen.arg('board_id'), pggen.arg('channel_id'), pggen.arg('guild_id'), pggen.arg('disabled'))
+on conflict (board_id, channel_id) do update set disabled = pggen.arg('disabled');
+
+-- name: SetOverrideStarboard :exec
+insert into starboard_overrides (board_id, channel_id, guild_id, starboard)
+values (pggen.arg('board_id'), pggen.arg('channel_id'), pggen.arg('guild_id'), pggen.arg('starboard'))
+on conflict (board_id, channel_id) do update set starboard = pggen.arg('starboard');
+
+-- name: SetOverrideEmoji :exec
+insert into starboard_overrides (board_id, channel_id, guild_id, emoji)
+values (pggen.arg('board_id'), pggen.arg('channel_id'), pggen.arg('guild_id'), pggen.arg('emoji'))
+on conflict (board_id, channel_id) do update set emoji = pggen.arg('emoji');
+
+-- name: SetOverrideReactionLimit :exec
+insert into starboard_overrides (board_id, channel_id, guild_id, reaction_limit)
+values (pggen.arg('board_id'), pggen.arg('channel_id'), pggen.arg('guild_id'), pggen.arg('reaction_limit'))
+on conflict (board_id, channel_id) do update set reaction_limit = pggen.arg('reaction_limit');
+
+-- name: RemoveOverride :exec
+delete from starboard_overrides where board_id = pggen.arg('board_id') and channel_id = pggen.arg('channel_id');
+
+-- name: TopMessages :many
+select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = pggen.arg('guild_id')
+and (pggen.arg('board_id') = 0 or m.board_id = pggen.arg('board_id'))
+and m.message_id >= pggen.arg('min_id')
+order by m.star_count desc, m.message_id desc
+limit 500;
+
+-- name: RandomMessage :one
+select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = pggen.arg('guild_id')
+and (pggen.arg('board_id') = 0 or m.board_id = pggen.arg('board_id'))
+order by random()
+limit 1;
+
+-- name: UserTopMessage :one
+select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = pggen.arg('guild_id') and m.author_id = pggen.arg('author_id')
+order by m.star_count desc, m.message_id desc
+limit 1;
+
+-- name: StarsReceived :one
+select count(*) as messages, coalesce(sum(stars), 0)::bigint as stars from (
+    select max(star_count) as stars from starboard_messages
+    where guild_id = pggen.arg('guild_id') and author_id = pggen.arg('author_id')
+    group by message_id
+) s;
+
+-- name: StarsGiven :one
+select count(*) from starboard_reactions where guild_id = pggen.arg('guild_id') and user_id = pggen.arg('user_id');
+
+-- name: StarGivers :many
+select user_id, count(*) as stars from starboard_reactions
+where guild_id = pggen.arg('guild_id')
+group by user_id
+order by stars desc, user_id
+limit 1000;
+
+-- name: SetLocked :exec
+update starboard_messages set locked = pggen.arg('locked')
+where message_id = pggen.arg('id') or starboard_id = pggen.arg('id');
+
+-- name: IsTrashed :one
+select exists(select 1 from starboard_trash where message_id = pggen.arg('message_id'));
+
+-- name: TrashMessage :exec
+insert into starboard_trash (message_id, guild_id, moderator_id, reason)
+values (pggen.arg('message_id'), pggen.arg('guild_id'), pggen.arg('moderator_id'), pggen.arg('reason'))
+on conflict (message_id) do update set moderator_id = pggen.arg('moderator_id'), reason = pggen.arg('reason');
+
+-- name: UntrashMessage :exec
+delete from starboard_trash where message_id = pggen.arg('message_id') and guild_id = pggen.arg('guild_id');
+
+-- name: Trash :many
+select message_id, moderator_id, reason from starboard_trash
+where guild_id = pggen.arg('guild_id') order by created desc;
+
+-- name: IsBlacklisted :one
+select exists(select 1 from starboard_blacklist where guild_id = pggen.arg('guild_id') and user_id = pggen.arg('user_id'));
+
+-- name: AddBlacklist :exec
+insert into starboard_blacklist (guild_id, user_id, moderator_id, reason)
+values (pggen.arg('guild_id'), pggen.arg('user_id'), pggen.arg('moderator_id'), pggen.arg('reason'))
+on conflict (guild_id, user_id) do update set moderator_id = pggen.arg('moderator_id'), reason = pggen.arg('reason');
+
+-- name: RemoveBlacklist :exec
+delete from starboard_blacklist where guild_id = pggen.arg('guild_id') and user_id = pggen.arg('user_id');
+
+-- name: Blacklist :many
+select user_id, moderator_id, reason from starboard_blacklist
+where guild_id = pggen.arg('guild_id') order by created desc;
+
+-- name: RemoveUserReactions :exec
+delete from starboard_reactions where guild_id = pggen.arg('guild_id') and user_id = pggen.arg('user_id');
+
+-- name: UserReactedPosts :many
+select distinct r.message_id, m.channel_id from starboard_reactions r
+join starboard_messages m on m.message_id = r.message_id
+where r.guild_id = pggen.arg('guild_id') and r.user_id = pggen.arg('user_id');
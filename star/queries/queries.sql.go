@@ -16,48 +16,55 @@ import (
 // calling SendBatch on pgx.Conn, pgxpool.Pool, or pgx.Tx, use the Scan methods
 // to parse the results.
 type Querier interface {
-	ChannelConfig(ctx context.Context, channelID int64, guildID int64) (ChannelConfigRow, error)
-	// ChannelConfigBatch enqueues a ChannelConfig query into batch to be executed
+	ChannelBoards(ctx context.Context, params ChannelBoardsParams) ([]ChannelBoardsRow, error)
+	// ChannelBoardsBatch enqueues a ChannelBoards query into batch to be executed
 	// later by the batch.
-	ChannelConfigBatch(batch genericBatch, channelID int64, guildID int64)
-	// ChannelConfigScan scans the result of an executed ChannelConfigBatch query.
-	ChannelConfigScan(results pgx.BatchResults) (ChannelConfigRow, error)
+	ChannelBoardsBatch(batch genericBatch, params ChannelBoardsParams)
+	// ChannelBoardsScan scans the result of an executed ChannelBoardsBatch query.
+	ChannelBoardsScan(results pgx.BatchResults) ([]ChannelBoardsRow, error)
 
-	HasOverride(ctx context.Context, channelID int64) (bool, error)
-	// HasOverrideBatch enqueues a HasOverride query into batch to be executed
+	StarboardMessages(ctx context.Context, id int64) ([]StarboardMessagesRow, error)
+	// StarboardMessagesBatch enqueues a StarboardMessages query into batch to be executed
 	// later by the batch.
-	HasOverrideBatch(batch genericBatch, channelID int64)
-	// HasOverrideScan scans the result of an executed HasOverrideBatch query.
-	HasOverrideScan(results pgx.BatchResults) (bool, error)
+	StarboardMessagesBatch(batch genericBatch, id int64)
+	// StarboardMessagesScan scans the result of an executed StarboardMessagesBatch query.
+	StarboardMessagesScan(results pgx.BatchResults) ([]StarboardMessagesRow, error)
 
-	StarboardMessage(ctx context.Context, id int64) (StarboardMessageRow, error)
-	// StarboardMessageBatch enqueues a StarboardMessage query into batch to be executed
+	BoardMessage(ctx context.Context, messageID int64, boardID int) (BoardMessageRow, error)
+	// BoardMessageBatch enqueues a BoardMessage query into batch to be executed
 	// later by the batch.
-	StarboardMessageBatch(batch genericBatch, id int64)
-	// StarboardMessageScan scans the result of an executed StarboardMessageBatch query.
-	StarboardMessageScan(results pgx.BatchResults) (StarboardMessageRow, error)
+	BoardMessageBatch(batch genericBatch, messageID int64, boardID int)
+	// BoardMessageScan scans the result of an executed BoardMessageBatch query.
+	BoardMessageScan(results pgx.BatchResults) (BoardMessageRow, error)
 
-	AddReaction(ctx context.Context, userID int64, messageID int64) (pgconn.CommandTag, error)
+	AddReaction(ctx context.Context, params AddReactionParams) (pgconn.CommandTag, error)
 	// AddReactionBatch enqueues a AddReaction query into batch to be executed
 	// later by the batch.
-	AddReactionBatch(batch genericBatch, userID int64, messageID int64)
+	AddReactionBatch(batch genericBatch, params AddReactionParams)
 	// AddReactionScan scans the result of an executed AddReactionBatch query.
 	AddReactionScan(results pgx.BatchResults) (pgconn.CommandTag, error)
 
-	RemoveReaction(ctx context.Context, userID int64, messageID int64) (pgconn.CommandTag, error)
+	RemoveReaction(ctx context.Context, params RemoveReactionParams) (pgconn.CommandTag, error)
 	// RemoveReactionBatch enqueues a RemoveReaction query into batch to be executed
 	// later by the batch.
-	RemoveReactionBatch(batch genericBatch, userID int64, messageID int64)
+	RemoveReactionBatch(batch genericBatch, params RemoveReactionParams)
 	// RemoveReactionScan scans the result of an executed RemoveReactionBatch query.
 	RemoveReactionScan(results pgx.BatchResults) (pgconn.CommandTag, error)
 
-	ReactionCount(ctx context.Context, messageID int64) (int64, error)
+	ReactionCount(ctx context.Context, messageID int64, emoji string) (int64, error)
 	// ReactionCountBatch enqueues a ReactionCount query into batch to be executed
 	// later by the batch.
-	ReactionCountBatch(batch genericBatch, messageID int64)
+	ReactionCountBatch(batch genericBatch, messageID int64, emoji string)
 	// ReactionCountScan scans the result of an executed ReactionCountBatch query.
 	ReactionCountScan(results pgx.BatchResults) (int64, error)
 
+	ReactionCountWithout(ctx context.Context, params ReactionCountWithoutParams) (int64, error)
+	// ReactionCountWithoutBatch enqueues a ReactionCountWithout query into batch to be executed
+	// later by the batch.
+	ReactionCountWithoutBatch(batch genericBatch, params ReactionCountWithoutParams)
+	// ReactionCountWithoutScan scans the result of an executed ReactionCountWithoutBatch query.
+	ReactionCountWithoutScan(results pgx.BatchResults) (int64, error)
+
 	RemoveAllReactions(ctx context.Context, messageID int64) (pgconn.CommandTag, error)
 	// RemoveAllReactionsBatch enqueues a RemoveAllReactions query into batch to be executed
 	// later by the batch.
@@ -65,6 +72,20 @@ type Querier interface {
 	// RemoveAllReactionsScan scans the result of an executed RemoveAllReactionsBatch query.
 	RemoveAllReactionsScan(results pgx.BatchResults) (pgconn.CommandTag, error)
 
+	RemoveEmojiReactions(ctx context.Context, messageID int64, emoji string) (pgconn.CommandTag, error)
+	// RemoveEmojiReactionsBatch enqueues a RemoveEmojiReactions query into batch to be executed
+	// later by the batch.
+	RemoveEmojiReactionsBatch(batch genericBatch, messageID int64, emoji string)
+	// RemoveEmojiReactionsScan scans the result of an executed RemoveEmojiReactionsBatch query.
+	RemoveEmojiReactionsScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	RecentPosts(ctx context.Context, minID int64) ([]RecentPostsRow, error)
+	// RecentPostsBatch enqueues a RecentPosts query into batch to be executed
+	// later by the batch.
+	RecentPostsBatch(batch genericBatch, minID int64)
+	// RecentPostsScan scans the result of an executed RecentPostsBatch query.
+	RecentPostsScan(results pgx.BatchResults) ([]RecentPostsRow, error)
+
 	RemoveStarboard(ctx context.Context, messageID int64) (pgconn.CommandTag, error)
 	// RemoveStarboardBatch enqueues a RemoveStarboard query into batch to be executed
 	// later by the batch.
@@ -72,6 +93,13 @@ type Querier interface {
 	// RemoveStarboardScan scans the result of an executed RemoveStarboardBatch query.
 	RemoveStarboardScan(results pgx.BatchResults) (pgconn.CommandTag, error)
 
+	RemoveBoardMessage(ctx context.Context, messageID int64, boardID int) (pgconn.CommandTag, error)
+	// RemoveBoardMessageBatch enqueues a RemoveBoardMessage query into batch to be executed
+	// later by the batch.
+	RemoveBoardMessageBatch(batch genericBatch, messageID int64, boardID int)
+	// RemoveBoardMessageScan scans the result of an executed RemoveBoardMessageBatch query.
+	RemoveBoardMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
 	InsertStarboard(ctx context.Context, params InsertStarboardParams) (InsertStarboardRow, error)
 	// InsertStarboardBatch enqueues a InsertStarboard query into batch to be executed
 	// later by the batch.
@@ -79,12 +107,250 @@ type Querier interface {
 	// InsertStarboardScan scans the result of an executed InsertStarboardBatch query.
 	InsertStarboardScan(results pgx.BatchResults) (InsertStarboardRow, error)
 
-	GetStarboard(ctx context.Context, messageID int64) (GetStarboardRow, error)
-	// GetStarboardBatch enqueues a GetStarboard query into batch to be executed
+	SetStarCount(ctx context.Context, params SetStarCountParams) (pgconn.CommandTag, error)
+	// SetStarCountBatch enqueues a SetStarCount query into batch to be executed
+	// later by the batch.
+	SetStarCountBatch(batch genericBatch, params SetStarCountParams)
+	// SetStarCountScan scans the result of an executed SetStarCountBatch query.
+	SetStarCountScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	Boards(ctx context.Context, guildID int64) ([]BoardsRow, error)
+	// BoardsBatch enqueues a Boards query into batch to be executed
+	// later by the batch.
+	BoardsBatch(batch genericBatch, guildID int64)
+	// BoardsScan scans the result of an executed BoardsBatch query.
+	BoardsScan(results pgx.BatchResults) ([]BoardsRow, error)
+
+	Board(ctx context.Context, guildID int64, name string) (BoardRow, error)
+	// BoardBatch enqueues a Board query into batch to be executed
+	// later by the batch.
+	BoardBatch(batch genericBatch, guildID int64, name string)
+	// BoardScan scans the result of an executed BoardBatch query.
+	BoardScan(results pgx.BatchResults) (BoardRow, error)
+
+	CreateBoard(ctx context.Context, params CreateBoardParams) (CreateBoardRow, error)
+	// CreateBoardBatch enqueues a CreateBoard query into batch to be executed
+	// later by the batch.
+	CreateBoardBatch(batch genericBatch, params CreateBoardParams)
+	// CreateBoardScan scans the result of an executed CreateBoardBatch query.
+	CreateBoardScan(results pgx.BatchResults) (CreateBoardRow, error)
+
+	DeleteBoard(ctx context.Context, id int) (pgconn.CommandTag, error)
+	// DeleteBoardBatch enqueues a DeleteBoard query into batch to be executed
+	// later by the batch.
+	DeleteBoardBatch(batch genericBatch, id int)
+	// DeleteBoardScan scans the result of an executed DeleteBoardBatch query.
+	DeleteBoardScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error)
+	// SetBoardChannelBatch enqueues a SetBoardChannel query into batch to be executed
+	// later by the batch.
+	SetBoardChannelBatch(batch genericBatch, channelID int64, id int)
+	// SetBoardChannelScan scans the result of an executed SetBoardChannelBatch query.
+	SetBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetBoardEmoji(ctx context.Context, emoji string, id int) (pgconn.CommandTag, error)
+	// SetBoardEmojiBatch enqueues a SetBoardEmoji query into batch to be executed
+	// later by the batch.
+	SetBoardEmojiBatch(batch genericBatch, emoji string, id int)
+	// SetBoardEmojiScan scans the result of an executed SetBoardEmojiBatch query.
+	SetBoardEmojiScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetBoardReactionLimit(ctx context.Context, reactionLimit int, id int) (pgconn.CommandTag, error)
+	// SetBoardReactionLimitBatch enqueues a SetBoardReactionLimit query into batch to be executed
+	// later by the batch.
+	SetBoardReactionLimitBatch(batch genericBatch, reactionLimit int, id int)
+	// SetBoardReactionLimitScan scans the result of an executed SetBoardReactionLimitBatch query.
+	SetBoardReactionLimitScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetBoardAllowSelfStar(ctx context.Context, allowSelfStar bool, id int) (pgconn.CommandTag, error)
+	// SetBoardAllowSelfStarBatch enqueues a SetBoardAllowSelfStar query into batch to be executed
+	// later by the batch.
+	SetBoardAllowSelfStarBatch(batch genericBatch, allowSelfStar bool, id int)
+	// SetBoardAllowSelfStarScan scans the result of an executed SetBoardAllowSelfStarBatch query.
+	SetBoardAllowSelfStarScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	AddBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error)
+	// AddBoardChannelBatch enqueues a AddBoardChannel query into batch to be executed
+	// later by the batch.
+	AddBoardChannelBatch(batch genericBatch, channelID int64, id int)
+	// AddBoardChannelScan scans the result of an executed AddBoardChannelBatch query.
+	AddBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	RemoveBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error)
+	// RemoveBoardChannelBatch enqueues a RemoveBoardChannel query into batch to be executed
+	// later by the batch.
+	RemoveBoardChannelBatch(batch genericBatch, channelID int64, id int)
+	// RemoveBoardChannelScan scans the result of an executed RemoveBoardChannelBatch query.
+	RemoveBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	ClearBoardChannels(ctx context.Context, id int) (pgconn.CommandTag, error)
+	// ClearBoardChannelsBatch enqueues a ClearBoardChannels query into batch to be executed
+	// later by the batch.
+	ClearBoardChannelsBatch(batch genericBatch, id int)
+	// ClearBoardChannelsScan scans the result of an executed ClearBoardChannelsBatch query.
+	ClearBoardChannelsScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	Overrides(ctx context.Context, guildID int64) ([]OverridesRow, error)
+	// OverridesBatch enqueues a Overrides query into batch to be executed
+	// later by the batch.
+	OverridesBatch(batch genericBatch, guildID int64)
+	// OverridesScan scans the result of an executed OverridesBatch query.
+	OverridesScan(results pgx.BatchResults) ([]OverridesRow, error)
+
+	SetOverrideDisabled(ctx context.Context, params SetOverrideDisabledParams) (pgconn.CommandTag, error)
+	// SetOverrideDisabledBatch enqueues a SetOverrideDisabled query into batch to be executed
+	// later by the batch.
+	SetOverrideDisabledBatch(batch genericBatch, params SetOverrideDisabledParams)
+	// SetOverrideDisabledScan scans the result of an executed SetOverrideDisabledBatch query.
+	SetOverrideDisabledScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetOverrideStarboard(ctx context.Context, params SetOverrideStarboardParams) (pgconn.CommandTag, error)
+	// SetOverrideStarboardBatch enqueues a SetOverrideStarboard query into batch to be executed
+	// later by the batch.
+	SetOverrideStarboardBatch(batch genericBatch, params SetOverrideStarboardParams)
+	// SetOverrideStarboardScan scans the result of an executed SetOverrideStarboardBatch query.
+	SetOverrideStarboardScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetOverrideEmoji(ctx context.Context, params SetOverrideEmojiParams) (pgconn.CommandTag, error)
+	// SetOverrideEmojiBatch enqueues a SetOverrideEmoji query into batch to be executed
+	// later by the batch.
+	SetOverrideEmojiBatch(batch genericBatch, params SetOverrideEmojiParams)
+	// SetOverrideEmojiScan scans the result of an executed SetOverrideEmojiBatch query.
+	SetOverrideEmojiScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	SetOverrideReactionLimit(ctx context.Context, params SetOverrideReactionLimitParams) (pgconn.CommandTag, error)
+	// SetOverrideReactionLimitBatch enqueues a SetOverrideReactionLimit query into batch to be executed
+	// later by the batch.
+	SetOverrideReactionLimitBatch(batch genericBatch, params SetOverrideReactionLimitParams)
+	// SetOverrideReactionLimitScan scans the result of an executed SetOverrideReactionLimitBatch query.
+	SetOverrideReactionLimitScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	RemoveOverride(ctx context.Context, boardID int, channelID int64) (pgconn.CommandTag, error)
+	// RemoveOverrideBatch enqueues a RemoveOverride query into batch to be executed
+	// later by the batch.
+	RemoveOverrideBatch(batch genericBatch, boardID int, channelID int64)
+	// RemoveOverrideScan scans the result of an executed RemoveOverrideBatch query.
+	RemoveOverrideScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	TopMessages(ctx context.Context, params TopMessagesParams) ([]TopMessagesRow, error)
+	// TopMessagesBatch enqueues a TopMessages query into batch to be executed
+	// later by the batch.
+	TopMessagesBatch(batch genericBatch, params TopMessagesParams)
+	// TopMessagesScan scans the result of an executed TopMessagesBatch query.
+	TopMessagesScan(results pgx.BatchResults) ([]TopMessagesRow, error)
+
+	RandomMessage(ctx context.Context, guildID int64, boardID int) (RandomMessageRow, error)
+	// RandomMessageBatch enqueues a RandomMessage query into batch to be executed
+	// later by the batch.
+	RandomMessageBatch(batch genericBatch, guildID int64, boardID int)
+	// RandomMessageScan scans the result of an executed RandomMessageBatch query.
+	RandomMessageScan(results pgx.BatchResults) (RandomMessageRow, error)
+
+	UserTopMessage(ctx context.Context, guildID int64, authorID int64) (UserTopMessageRow, error)
+	// UserTopMessageBatch enqueues a UserTopMessage query into batch to be executed
+	// later by the batch.
+	UserTopMessageBatch(batch genericBatch, guildID int64, authorID int64)
+	// UserTopMessageScan scans the result of an executed UserTopMessageBatch query.
+	UserTopMessageScan(results pgx.BatchResults) (UserTopMessageRow, error)
+
+	StarsReceived(ctx context.Context, guildID int64, authorID int64) (StarsReceivedRow, error)
+	// StarsReceivedBatch enqueues a StarsReceived query into batch to be executed
+	// later by the batch.
+	StarsReceivedBatch(batch genericBatch, guildID int64, authorID int64)
+	// StarsReceivedScan scans the result of an executed StarsReceivedBatch query.
+	StarsReceivedScan(results pgx.BatchResults) (StarsReceivedRow, error)
+
+	StarsGiven(ctx context.Context, guildID int64, userID int64) (int64, error)
+	// StarsGivenBatch enqueues a StarsGiven query into batch to be executed
+	// later by the batch.
+	StarsGivenBatch(batch genericBatch, guildID int64, userID int64)
+	// StarsGivenScan scans the result of an executed StarsGivenBatch query.
+	StarsGivenScan(results pgx.BatchResults) (int64, error)
+
+	StarGivers(ctx context.Context, guildID int64) ([]StarGiversRow, error)
+	// StarGiversBatch enqueues a StarGivers query into batch to be executed
+	// later by the batch.
+	StarGiversBatch(batch genericBatch, guildID int64)
+	// StarGiversScan scans the result of an executed StarGiversBatch query.
+	StarGiversScan(results pgx.BatchResults) ([]StarGiversRow, error)
+
+	SetLocked(ctx context.Context, locked bool, id int64) (pgconn.CommandTag, error)
+	// SetLockedBatch enqueues a SetLocked query into batch to be executed
+	// later by the batch.
+	SetLockedBatch(batch genericBatch, locked bool, id int64)
+	// SetLockedScan scans the result of an executed SetLockedBatch query.
+	SetLockedScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	IsTrashed(ctx context.Context, messageID int64) (bool, error)
+	// IsTrashedBatch enqueues a IsTrashed query into batch to be executed
+	// later by the batch.
+	IsTrashedBatch(batch genericBatch, messageID int64)
+	// IsTrashedScan scans the result of an executed IsTrashedBatch query.
+	IsTrashedScan(results pgx.BatchResults) (bool, error)
+
+	TrashMessage(ctx context.Context, params TrashMessageParams) (pgconn.CommandTag, error)
+	// TrashMessageBatch enqueues a TrashMessage query into batch to be executed
+	// later by the batch.
+	TrashMessageBatch(batch genericBatch, params TrashMessageParams)
+	// TrashMessageScan scans the result of an executed TrashMessageBatch query.
+	TrashMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	UntrashMessage(ctx context.Context, messageID int64, guildID int64) (pgconn.CommandTag, error)
+	// UntrashMessageBatch enqueues a UntrashMessage query into batch to be executed
+	// later by the batch.
+	UntrashMessageBatch(batch genericBatch, messageID int64, guildID int64)
+	// UntrashMessageScan scans the result of an executed UntrashMessageBatch query.
+	UntrashMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	Trash(ctx context.Context, guildID int64) ([]TrashRow, error)
+	// TrashBatch enqueues a Trash query into batch to be executed
 	// later by the batch.
-	GetStarboardBatch(batch genericBatch, messageID int64)
-	// GetStarboardScan scans the result of an executed GetStarboardBatch query.
-	GetStarboardScan(results pgx.BatchResults) (GetStarboardRow, error)
+	TrashBatch(batch genericBatch, guildID int64)
+	// TrashScan scans the result of an executed TrashBatch query.
+	TrashScan(results pgx.BatchResults) ([]TrashRow, error)
+
+	IsBlacklisted(ctx context.Context, guildID int64, userID int64) (bool, error)
+	// IsBlacklistedBatch enqueues a IsBlacklisted query into batch to be executed
+	// later by the batch.
+	IsBlacklistedBatch(batch genericBatch, guildID int64, userID int64)
+	// IsBlacklistedScan scans the result of an executed IsBlacklistedBatch query.
+	IsBlacklistedScan(results pgx.BatchResults) (bool, error)
+
+	AddBlacklist(ctx context.Context, params AddBlacklistParams) (pgconn.CommandTag, error)
+	// AddBlacklistBatch enqueues a AddBlacklist query into batch to be executed
+	// later by the batch.
+	AddBlacklistBatch(batch genericBatch, params AddBlacklistParams)
+	// AddBlacklistScan scans the result of an executed AddBlacklistBatch query.
+	AddBlacklistScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	RemoveBlacklist(ctx context.Context, guildID int64, userID int64) (pgconn.CommandTag, error)
+	// RemoveBlacklistBatch enqueues a RemoveBlacklist query into batch to be executed
+	// later by the batch.
+	RemoveBlacklistBatch(batch genericBatch, guildID int64, userID int64)
+	// RemoveBlacklistScan scans the result of an executed RemoveBlacklistBatch query.
+	RemoveBlacklistScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	Blacklist(ctx context.Context, guildID int64) ([]BlacklistRow, error)
+	// BlacklistBatch enqueues a Blacklist query into batch to be executed
+	// later by the batch.
+	BlacklistBatch(batch genericBatch, guildID int64)
+	// BlacklistScan scans the result of an executed BlacklistBatch query.
+	BlacklistScan(results pgx.BatchResults) ([]BlacklistRow, error)
+
+	RemoveUserReactions(ctx context.Context, guildID int64, userID int64) (pgconn.CommandTag, error)
+	// RemoveUserReactionsBatch enqueues a RemoveUserReactions query into batch to be executed
+	// later by the batch.
+	RemoveUserReactionsBatch(batch genericBatch, guildID int64, userID int64)
+	// RemoveUserReactionsScan scans the result of an executed RemoveUserReactionsBatch query.
+	RemoveUserReactionsScan(results pgx.BatchResults) (pgconn.CommandTag, error)
+
+	UserReactedPosts(ctx context.Context, guildID int64, userID int64) ([]UserReactedPostsRow, error)
+	// UserReactedPostsBatch enqueues a UserReactedPosts query into batch to be executed
+	// later by the batch.
+	UserReactedPostsBatch(batch genericBatch, guildID int64, userID int64)
+	// UserReactedPostsScan scans the result of an executed UserReactedPostsBatch query.
+	UserReactedPostsScan(results pgx.BatchResults) ([]UserReactedPostsRow, error)
 }
 
 type DBQuerier struct {
@@ -162,14 +428,14 @@ type preparer interface {
 // is an optional optimization to avoid a network round-trip the first time pgx
 // runs a query if pgx statement caching is enabled.
 func PrepareAllQueries(ctx context.Context, p preparer) error {
-	if _, err := p.Prepare(ctx, channelConfigSQL, channelConfigSQL); err != nil {
-		return fmt.Errorf("prepare query 'ChannelConfig': %w", err)
+	if _, err := p.Prepare(ctx, channelBoardsSQL, channelBoardsSQL); err != nil {
+		return fmt.Errorf("prepare query 'ChannelBoards': %w", err)
 	}
-	if _, err := p.Prepare(ctx, hasOverrideSQL, hasOverrideSQL); err != nil {
-		return fmt.Errorf("prepare query 'HasOverride': %w", err)
+	if _, err := p.Prepare(ctx, starboardMessagesSQL, starboardMessagesSQL); err != nil {
+		return fmt.Errorf("prepare query 'StarboardMessages': %w", err)
 	}
-	if _, err := p.Prepare(ctx, starboardMessageSQL, starboardMessageSQL); err != nil {
-		return fmt.Errorf("prepare query 'StarboardMessage': %w", err)
+	if _, err := p.Prepare(ctx, boardMessageSQL, boardMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'BoardMessage': %w", err)
 	}
 	if _, err := p.Prepare(ctx, addReactionSQL, addReactionSQL); err != nil {
 		return fmt.Errorf("prepare query 'AddReaction': %w", err)
@@ -180,17 +446,131 @@ func PrepareAllQueries(ctx context.Context, p preparer) error {
 	if _, err := p.Prepare(ctx, reactionCountSQL, reactionCountSQL); err != nil {
 		return fmt.Errorf("prepare query 'ReactionCount': %w", err)
 	}
+	if _, err := p.Prepare(ctx, reactionCountWithoutSQL, reactionCountWithoutSQL); err != nil {
+		return fmt.Errorf("prepare query 'ReactionCountWithout': %w", err)
+	}
 	if _, err := p.Prepare(ctx, removeAllReactionsSQL, removeAllReactionsSQL); err != nil {
 		return fmt.Errorf("prepare query 'RemoveAllReactions': %w", err)
 	}
+	if _, err := p.Prepare(ctx, removeEmojiReactionsSQL, removeEmojiReactionsSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveEmojiReactions': %w", err)
+	}
+	if _, err := p.Prepare(ctx, recentPostsSQL, recentPostsSQL); err != nil {
+		return fmt.Errorf("prepare query 'RecentPosts': %w", err)
+	}
 	if _, err := p.Prepare(ctx, removeStarboardSQL, removeStarboardSQL); err != nil {
 		return fmt.Errorf("prepare query 'RemoveStarboard': %w", err)
 	}
+	if _, err := p.Prepare(ctx, removeBoardMessageSQL, removeBoardMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveBoardMessage': %w", err)
+	}
 	if _, err := p.Prepare(ctx, insertStarboardSQL, insertStarboardSQL); err != nil {
 		return fmt.Errorf("prepare query 'InsertStarboard': %w", err)
 	}
-	if _, err := p.Prepare(ctx, getStarboardSQL, getStarboardSQL); err != nil {
-		return fmt.Errorf("prepare query 'GetStarboard': %w", err)
+	if _, err := p.Prepare(ctx, setStarCountSQL, setStarCountSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetStarCount': %w", err)
+	}
+	if _, err := p.Prepare(ctx, boardsSQL, boardsSQL); err != nil {
+		return fmt.Errorf("prepare query 'Boards': %w", err)
+	}
+	if _, err := p.Prepare(ctx, boardSQL, boardSQL); err != nil {
+		return fmt.Errorf("prepare query 'Board': %w", err)
+	}
+	if _, err := p.Prepare(ctx, createBoardSQL, createBoardSQL); err != nil {
+		return fmt.Errorf("prepare query 'CreateBoard': %w", err)
+	}
+	if _, err := p.Prepare(ctx, deleteBoardSQL, deleteBoardSQL); err != nil {
+		return fmt.Errorf("prepare query 'DeleteBoard': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setBoardChannelSQL, setBoardChannelSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetBoardChannel': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setBoardEmojiSQL, setBoardEmojiSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetBoardEmoji': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setBoardReactionLimitSQL, setBoardReactionLimitSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetBoardReactionLimit': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setBoardAllowSelfStarSQL, setBoardAllowSelfStarSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetBoardAllowSelfStar': %w", err)
+	}
+	if _, err := p.Prepare(ctx, addBoardChannelSQL, addBoardChannelSQL); err != nil {
+		return fmt.Errorf("prepare query 'AddBoardChannel': %w", err)
+	}
+	if _, err := p.Prepare(ctx, removeBoardChannelSQL, removeBoardChannelSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveBoardChannel': %w", err)
+	}
+	if _, err := p.Prepare(ctx, clearBoardChannelsSQL, clearBoardChannelsSQL); err != nil {
+		return fmt.Errorf("prepare query 'ClearBoardChannels': %w", err)
+	}
+	if _, err := p.Prepare(ctx, overridesSQL, overridesSQL); err != nil {
+		return fmt.Errorf("prepare query 'Overrides': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setOverrideDisabledSQL, setOverrideDisabledSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetOverrideDisabled': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setOverrideStarboardSQL, setOverrideStarboardSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetOverrideStarboard': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setOverrideEmojiSQL, setOverrideEmojiSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetOverrideEmoji': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setOverrideReactionLimitSQL, setOverrideReactionLimitSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetOverrideReactionLimit': %w", err)
+	}
+	if _, err := p.Prepare(ctx, removeOverrideSQL, removeOverrideSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveOverride': %w", err)
+	}
+	if _, err := p.Prepare(ctx, topMessagesSQL, topMessagesSQL); err != nil {
+		return fmt.Errorf("prepare query 'TopMessages': %w", err)
+	}
+	if _, err := p.Prepare(ctx, randomMessageSQL, randomMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'RandomMessage': %w", err)
+	}
+	if _, err := p.Prepare(ctx, userTopMessageSQL, userTopMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'UserTopMessage': %w", err)
+	}
+	if _, err := p.Prepare(ctx, starsReceivedSQL, starsReceivedSQL); err != nil {
+		return fmt.Errorf("prepare query 'StarsReceived': %w", err)
+	}
+	if _, err := p.Prepare(ctx, starsGivenSQL, starsGivenSQL); err != nil {
+		return fmt.Errorf("prepare query 'StarsGiven': %w", err)
+	}
+	if _, err := p.Prepare(ctx, starGiversSQL, starGiversSQL); err != nil {
+		return fmt.Errorf("prepare query 'StarGivers': %w", err)
+	}
+	if _, err := p.Prepare(ctx, setLockedSQL, setLockedSQL); err != nil {
+		return fmt.Errorf("prepare query 'SetLocked': %w", err)
+	}
+	if _, err := p.Prepare(ctx, isTrashedSQL, isTrashedSQL); err != nil {
+		return fmt.Errorf("prepare query 'IsTrashed': %w", err)
+	}
+	if _, err := p.Prepare(ctx, trashMessageSQL, trashMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'TrashMessage': %w", err)
+	}
+	if _, err := p.Prepare(ctx, untrashMessageSQL, untrashMessageSQL); err != nil {
+		return fmt.Errorf("prepare query 'UntrashMessage': %w", err)
+	}
+	if _, err := p.Prepare(ctx, trashSQL, trashSQL); err != nil {
+		return fmt.Errorf("prepare query 'Trash': %w", err)
+	}
+	if _, err := p.Prepare(ctx, isBlacklistedSQL, isBlacklistedSQL); err != nil {
+		return fmt.Errorf("prepare query 'IsBlacklisted': %w", err)
+	}
+	if _, err := p.Prepare(ctx, addBlacklistSQL, addBlacklistSQL); err != nil {
+		return fmt.Errorf("prepare query 'AddBlacklist': %w", err)
+	}
+	if _, err := p.Prepare(ctx, removeBlacklistSQL, removeBlacklistSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveBlacklist': %w", err)
+	}
+	if _, err := p.Prepare(ctx, blacklistSQL, blacklistSQL); err != nil {
+		return fmt.Errorf("prepare query 'Blacklist': %w", err)
+	}
+	if _, err := p.Prepare(ctx, removeUserReactionsSQL, removeUserReactionsSQL); err != nil {
+		return fmt.Errorf("prepare query 'RemoveUserReactions': %w", err)
+	}
+	if _, err := p.Prepare(ctx, userReactedPostsSQL, userReactedPostsSQL); err != nil {
+		return fmt.Errorf("prepare query 'UserReactedPosts': %w", err)
 	}
 	return nil
 }
@@ -230,18 +610,30 @@ func (tr *typeResolver) setValue(vt pgtype.ValueTranscoder, val interface{}) pgt
 	return vt
 }
 
-const channelConfigSQL = `select
-coalesce(o.starboard, s.channel_id) as starboard,
-coalesce(o.disabled, false) as disabled,
-coalesce(o.emoji, s.emoji) as emoji,
-coalesce(o.reaction_limit, s.reaction_limit) as reaction_limit,
-s.allow_self_star as allow_self_star
-from starboard s
-left outer join starboard_overrides o on o.channel_id = $1
-where s.guild_id = $2
-limit 1;`
+const channelBoardsSQL = `select
+b.id,
+b.name,
+coalesce(co.starboard, po.starboard, b.channel_id) as starboard,
+coalesce(co.disabled, po.disabled, false) as disabled,
+coalesce(co.emoji, po.emoji, b.emoji) as emoji,
+coalesce(co.reaction_limit, po.reaction_limit, b.reaction_limit) as reaction_limit,
+b.allow_self_star
+from starboards b
+left outer join starboard_overrides co on co.board_id = b.id and co.channel_id = $1
+left outer join starboard_overrides po on po.board_id = b.id and po.channel_id = $2
+where b.guild_id = $3
+and (cardinality(b.channels) = 0 or $1 = any(b.channels) or $2 = any(b.channels))
+order by b.id;`
+
+type ChannelBoardsParams struct {
+	ChannelID  int64
+	CategoryID int64
+	GuildID    int64
+}
 
-type ChannelConfigRow struct {
+type ChannelBoardsRow struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
 	Starboard     int64  `json:"starboard"`
 	Disabled      bool   `json:"disabled"`
 	Emoji         string `json:"emoji"`
@@ -249,102 +641,171 @@ type ChannelConfigRow struct {
 	AllowSelfStar bool   `json:"allow_self_star"`
 }
 
-// ChannelConfig implements Querier.ChannelConfig.
-func (q *DBQuerier) ChannelConfig(ctx context.Context, channelID int64, guildID int64) (ChannelConfigRow, error) {
-	ctx = context.WithValue(ctx, "pggen_query_name", "ChannelConfig")
-	row := q.conn.QueryRow(ctx, channelConfigSQL, channelID, guildID)
-	var item ChannelConfigRow
-	if err := row.Scan(&item.Starboard, &item.Disabled, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar); err != nil {
-		return item, fmt.Errorf("query ChannelConfig: %w", err)
+// ChannelBoards implements Querier.ChannelBoards.
+func (q *DBQuerier) ChannelBoards(ctx context.Context, params ChannelBoardsParams) ([]ChannelBoardsRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "ChannelBoards")
+	rows, err := q.conn.Query(ctx, channelBoardsSQL, params.ChannelID, params.CategoryID, params.GuildID)
+	if err != nil {
+		return nil, fmt.Errorf("query ChannelBoards: %w", err)
 	}
-	return item, nil
+	defer rows.Close()
+	items := []ChannelBoardsRow{}
+	for rows.Next() {
+		var item ChannelBoardsRow
+		if err := rows.Scan(&item.ID, &item.Name, &item.Starboard, &item.Disabled, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar); err != nil {
+			return nil, fmt.Errorf("scan ChannelBoards row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close ChannelBoards rows: %w", err)
+	}
+	return items, err
 }
 
-// ChannelConfigBatch implements Querier.ChannelConfigBatch.
-func (q *DBQuerier) ChannelConfigBatch(batch genericBatch, channelID int64, guildID int64) {
-	batch.Queue(channelConfigSQL, channelID, guildID)
+// ChannelBoardsBatch implements Querier.ChannelBoardsBatch.
+func (q *DBQuerier) ChannelBoardsBatch(batch genericBatch, params ChannelBoardsParams) {
+	batch.Queue(channelBoardsSQL, params.ChannelID, params.CategoryID, params.GuildID)
 }
 
-// ChannelConfigScan implements Querier.ChannelConfigScan.
-func (q *DBQuerier) ChannelConfigScan(results pgx.BatchResults) (ChannelConfigRow, error) {
-	row := results.QueryRow()
-	var item ChannelConfigRow
-	if err := row.Scan(&item.Starboard, &item.Disabled, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar); err != nil {
-		return item, fmt.Errorf("scan ChannelConfigBatch row: %w", err)
+// ChannelBoardsScan implements Querier.ChannelBoardsScan.
+func (q *DBQuerier) ChannelBoardsScan(results pgx.BatchResults) ([]ChannelBoardsRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query ChannelBoardsBatch: %w", err)
 	}
-	return item, nil
+	defer rows.Close()
+	items := []ChannelBoardsRow{}
+	for rows.Next() {
+		var item ChannelBoardsRow
+		if err := rows.Scan(&item.ID, &item.Name, &item.Starboard, &item.Disabled, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar); err != nil {
+			return nil, fmt.Errorf("scan ChannelBoardsBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close ChannelBoardsBatch rows: %w", err)
+	}
+	return items, err
 }
 
-const hasOverrideSQL = `select exists(select channel_id from starboard_overrides where channel_id = $1);`
+const starboardMessagesSQL = `select * from starboard_messages
+where message_id = $1 or starboard_id = $1;`
 
-// HasOverride implements Querier.HasOverride.
-func (q *DBQuerier) HasOverride(ctx context.Context, channelID int64) (bool, error) {
-	ctx = context.WithValue(ctx, "pggen_query_name", "HasOverride")
-	row := q.conn.QueryRow(ctx, hasOverrideSQL, channelID)
-	var item bool
-	if err := row.Scan(&item); err != nil {
-		return item, fmt.Errorf("query HasOverride: %w", err)
+type StarboardMessagesRow struct {
+	MessageID          int64 `json:"message_id"`
+	ChannelID          int64 `json:"channel_id"`
+	GuildID            int64 `json:"guild_id"`
+	StarboardID        int64 `json:"starboard_id"`
+	BoardID            int   `json:"board_id"`
+	StarboardChannelID int64 `json:"starboard_channel_id"`
+	AuthorID           int64 `json:"author_id"`
+	StarCount          int   `json:"star_count"`
+	Locked             bool  `json:"locked"`
+}
+
+// StarboardMessages implements Querier.StarboardMessages.
+func (q *DBQuerier) StarboardMessages(ctx context.Context, id int64) ([]StarboardMessagesRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "StarboardMessages")
+	rows, err := q.conn.Query(ctx, starboardMessagesSQL, id)
+	if err != nil {
+		return nil, fmt.Errorf("query StarboardMessages: %w", err)
 	}
-	return item, nil
+	defer rows.Close()
+	items := []StarboardMessagesRow{}
+	for rows.Next() {
+		var item StarboardMessagesRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
+			return nil, fmt.Errorf("scan StarboardMessages row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close StarboardMessages rows: %w", err)
+	}
+	return items, err
 }
 
-// HasOverrideBatch implements Querier.HasOverrideBatch.
-func (q *DBQuerier) HasOverrideBatch(batch genericBatch, channelID int64) {
-	batch.Queue(hasOverrideSQL, channelID)
+// StarboardMessagesBatch implements Querier.StarboardMessagesBatch.
+func (q *DBQuerier) StarboardMessagesBatch(batch genericBatch, id int64) {
+	batch.Queue(starboardMessagesSQL, id)
 }
 
-// HasOverrideScan implements Querier.HasOverrideScan.
-func (q *DBQuerier) HasOverrideScan(results pgx.BatchResults) (bool, error) {
-	row := results.QueryRow()
-	var item bool
-	if err := row.Scan(&item); err != nil {
-		return item, fmt.Errorf("scan HasOverrideBatch row: %w", err)
+// StarboardMessagesScan implements Querier.StarboardMessagesScan.
+func (q *DBQuerier) StarboardMessagesScan(results pgx.BatchResults) ([]StarboardMessagesRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query StarboardMessagesBatch: %w", err)
 	}
-	return item, nil
+	defer rows.Close()
+	items := []StarboardMessagesRow{}
+	for rows.Next() {
+		var item StarboardMessagesRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
+			return nil, fmt.Errorf("scan StarboardMessagesBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close StarboardMessagesBatch rows: %w", err)
+	}
+	return items, err
 }
 
-const starboardMessageSQL = `select * from starboard_messages
-where message_id = $1 or starboard_id = $1;`
-
-type StarboardMessageRow struct {
-	MessageID   int64 `json:"message_id"`
-	ChannelID   int64 `json:"channel_id"`
-	GuildID     int64 `json:"guild_id"`
-	StarboardID int64 `json:"starboard_id"`
+const boardMessageSQL = `select * from starboard_messages
+where message_id = $1 and board_id = $2;`
+
+type BoardMessageRow struct {
+	MessageID          int64 `json:"message_id"`
+	ChannelID          int64 `json:"channel_id"`
+	GuildID            int64 `json:"guild_id"`
+	StarboardID        int64 `json:"starboard_id"`
+	BoardID            int   `json:"board_id"`
+	StarboardChannelID int64 `json:"starboard_channel_id"`
+	AuthorID           int64 `json:"author_id"`
+	StarCount          int   `json:"star_count"`
+	Locked             bool  `json:"locked"`
 }
 
-// StarboardMessage implements Querier.StarboardMessage.
-func (q *DBQuerier) StarboardMessage(ctx context.Context, id int64) (StarboardMessageRow, error) {
-	ctx = context.WithValue(ctx, "pggen_query_name", "StarboardMessage")
-	row := q.conn.QueryRow(ctx, starboardMessageSQL, id)
-	var item StarboardMessageRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
-		return item, fmt.Errorf("query StarboardMessage: %w", err)
+// BoardMessage implements Querier.BoardMessage.
+func (q *DBQuerier) BoardMessage(ctx context.Context, messageID int64, boardID int) (BoardMessageRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "BoardMessage")
+	row := q.conn.QueryRow(ctx, boardMessageSQL, messageID, boardID)
+	var item BoardMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
+		return item, fmt.Errorf("query BoardMessage: %w", err)
 	}
 	return item, nil
 }
 
-// StarboardMessageBatch implements Querier.StarboardMessageBatch.
-func (q *DBQuerier) StarboardMessageBatch(batch genericBatch, id int64) {
-	batch.Queue(starboardMessageSQL, id)
+// BoardMessageBatch implements Querier.BoardMessageBatch.
+func (q *DBQuerier) BoardMessageBatch(batch genericBatch, messageID int64, boardID int) {
+	batch.Queue(boardMessageSQL, messageID, boardID)
 }
 
-// StarboardMessageScan implements Querier.StarboardMessageScan.
-func (q *DBQuerier) StarboardMessageScan(results pgx.BatchResults) (StarboardMessageRow, error) {
+// BoardMessageScan implements Querier.BoardMessageScan.
+func (q *DBQuerier) BoardMessageScan(results pgx.BatchResults) (BoardMessageRow, error) {
 	row := results.QueryRow()
-	var item StarboardMessageRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
-		return item, fmt.Errorf("scan StarboardMessageBatch row: %w", err)
+	var item BoardMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
+		return item, fmt.Errorf("scan BoardMessageBatch row: %w", err)
 	}
 	return item, nil
 }
 
-const addReactionSQL = `insert into starboard_reactions (user_id, message_id) values ($1, $2) on conflict (user_id, message_id) do nothing;`
+const addReactionSQL = `insert into starboard_reactions (user_id, message_id, emoji, guild_id) values ($1, $2, $3, $4) on conflict (user_id, message_id, emoji) do nothing;`
+
+type AddReactionParams struct {
+	UserID    int64
+	MessageID int64
+	Emoji     string
+	GuildID   int64
+}
 
 // AddReaction implements Querier.AddReaction.
-func (q *DBQuerier) AddReaction(ctx context.Context, userID int64, messageID int64) (pgconn.CommandTag, error) {
+func (q *DBQuerier) AddReaction(ctx context.Context, params AddReactionParams) (pgconn.CommandTag, error) {
 	ctx = context.WithValue(ctx, "pggen_query_name", "AddReaction")
-	cmdTag, err := q.conn.Exec(ctx, addReactionSQL, userID, messageID)
+	cmdTag, err := q.conn.Exec(ctx, addReactionSQL, params.UserID, params.MessageID, params.Emoji, params.GuildID)
 	if err != nil {
 		return cmdTag, fmt.Errorf("exec query AddReaction: %w", err)
 	}
@@ -352,8 +813,8 @@ func (q *DBQuerier) AddReaction(ctx context.Context, userID int64, messageID int
 }
 
 // AddReactionBatch implements Querier.AddReactionBatch.
-func (q *DBQuerier) AddReactionBatch(batch genericBatch, userID int64, messageID int64) {
-	batch.Queue(addReactionSQL, userID, messageID)
+func (q *DBQuerier) AddReactionBatch(batch genericBatch, params AddReactionParams) {
+	batch.Queue(addReactionSQL, params.UserID, params.MessageID, params.Emoji, params.GuildID)
 }
 
 // AddReactionScan implements Querier.AddReactionScan.
@@ -365,12 +826,18 @@ func (q *DBQuerier) AddReactionScan(results pgx.BatchResults) (pgconn.CommandTag
 	return cmdTag, err
 }
 
-const removeReactionSQL = `delete from starboard_reactions where user_id = $1 and message_id = $2;`
+const removeReactionSQL = `delete from starboard_reactions where user_id = $1 and message_id = $2 and emoji = $3;`
+
+type RemoveReactionParams struct {
+	UserID    int64
+	MessageID int64
+	Emoji     string
+}
 
 // RemoveReaction implements Querier.RemoveReaction.
-func (q *DBQuerier) RemoveReaction(ctx context.Context, userID int64, messageID int64) (pgconn.CommandTag, error) {
+func (q *DBQuerier) RemoveReaction(ctx context.Context, params RemoveReactionParams) (pgconn.CommandTag, error) {
 	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveReaction")
-	cmdTag, err := q.conn.Exec(ctx, removeReactionSQL, userID, messageID)
+	cmdTag, err := q.conn.Exec(ctx, removeReactionSQL, params.UserID, params.MessageID, params.Emoji)
 	if err != nil {
 		return cmdTag, fmt.Errorf("exec query RemoveReaction: %w", err)
 	}
@@ -378,8 +845,8 @@ func (q *DBQuerier) RemoveReaction(ctx context.Context, userID int64, messageID
 }
 
 // RemoveReactionBatch implements Querier.RemoveReactionBatch.
-func (q *DBQuerier) RemoveReactionBatch(batch genericBatch, userID int64, messageID int64) {
-	batch.Queue(removeReactionSQL, userID, messageID)
+func (q *DBQuerier) RemoveReactionBatch(batch genericBatch, params RemoveReactionParams) {
+	batch.Queue(removeReactionSQL, params.UserID, params.MessageID, params.Emoji)
 }
 
 // RemoveReactionScan implements Querier.RemoveReactionScan.
@@ -391,12 +858,12 @@ func (q *DBQuerier) RemoveReactionScan(results pgx.BatchResults) (pgconn.Command
 	return cmdTag, err
 }
 
-const reactionCountSQL = `select count(*) from starboard_reactions where message_id = $1;`
+const reactionCountSQL = `select count(*) from starboard_reactions where message_id = $1 and emoji = $2;`
 
 // ReactionCount implements Querier.ReactionCount.
-func (q *DBQuerier) ReactionCount(ctx context.Context, messageID int64) (int64, error) {
+func (q *DBQuerier) ReactionCount(ctx context.Context, messageID int64, emoji string) (int64, error) {
 	ctx = context.WithValue(ctx, "pggen_query_name", "ReactionCount")
-	row := q.conn.QueryRow(ctx, reactionCountSQL, messageID)
+	row := q.conn.QueryRow(ctx, reactionCountSQL, messageID, emoji)
 	var item int64
 	if err := row.Scan(&item); err != nil {
 		return item, fmt.Errorf("query ReactionCount: %w", err)
@@ -405,8 +872,8 @@ func (q *DBQuerier) ReactionCount(ctx context.Context, messageID int64) (int64,
 }
 
 // ReactionCountBatch implements Querier.ReactionCountBatch.
-func (q *DBQuerier) ReactionCountBatch(batch genericBatch, messageID int64) {
-	batch.Queue(reactionCountSQL, messageID)
+func (q *DBQuerier) ReactionCountBatch(batch genericBatch, messageID int64, emoji string) {
+	batch.Queue(reactionCountSQL, messageID, emoji)
 }
 
 // ReactionCountScan implements Querier.ReactionCountScan.
@@ -419,6 +886,40 @@ func (q *DBQuerier) ReactionCountScan(results pgx.BatchResults) (int64, error) {
 	return item, nil
 }
 
+const reactionCountWithoutSQL = `select count(*) from starboard_reactions where message_id = $1 and emoji = $2 and user_id <> $3;`
+
+type ReactionCountWithoutParams struct {
+	MessageID int64
+	Emoji     string
+	UserID    int64
+}
+
+// ReactionCountWithout implements Querier.ReactionCountWithout.
+func (q *DBQuerier) ReactionCountWithout(ctx context.Context, params ReactionCountWithoutParams) (int64, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "ReactionCountWithout")
+	row := q.conn.QueryRow(ctx, reactionCountWithoutSQL, params.MessageID, params.Emoji, params.UserID)
+	var item int64
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("query ReactionCountWithout: %w", err)
+	}
+	return item, nil
+}
+
+// ReactionCountWithoutBatch implements Querier.ReactionCountWithoutBatch.
+func (q *DBQuerier) ReactionCountWithoutBatch(batch genericBatch, params ReactionCountWithoutParams) {
+	batch.Queue(reactionCountWithoutSQL, params.MessageID, params.Emoji, params.UserID)
+}
+
+// ReactionCountWithoutScan implements Querier.ReactionCountWithoutScan.
+func (q *DBQuerier) ReactionCountWithoutScan(results pgx.BatchResults) (int64, error) {
+	row := results.QueryRow()
+	var item int64
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("scan ReactionCountWithoutBatch row: %w", err)
+	}
+	return item, nil
+}
+
 const removeAllReactionsSQL = `delete from starboard_reactions where message_id = $1;`
 
 // RemoveAllReactions implements Querier.RemoveAllReactions.
@@ -445,6 +946,88 @@ func (q *DBQuerier) RemoveAllReactionsScan(results pgx.BatchResults) (pgconn.Com
 	return cmdTag, err
 }
 
+const removeEmojiReactionsSQL = `delete from starboard_reactions where message_id = $1 and emoji = $2;`
+
+// RemoveEmojiReactions implements Querier.RemoveEmojiReactions.
+func (q *DBQuerier) RemoveEmojiReactions(ctx context.Context, messageID int64, emoji string) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveEmojiReactions")
+	cmdTag, err := q.conn.Exec(ctx, removeEmojiReactionsSQL, messageID, emoji)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveEmojiReactions: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveEmojiReactionsBatch implements Querier.RemoveEmojiReactionsBatch.
+func (q *DBQuerier) RemoveEmojiReactionsBatch(batch genericBatch, messageID int64, emoji string) {
+	batch.Queue(removeEmojiReactionsSQL, messageID, emoji)
+}
+
+// RemoveEmojiReactionsScan implements Querier.RemoveEmojiReactionsScan.
+func (q *DBQuerier) RemoveEmojiReactionsScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveEmojiReactionsBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const recentPostsSQL = `select distinct message_id, channel_id from starboard_messages
+where message_id >= $1;`
+
+type RecentPostsRow struct {
+	MessageID int64 `json:"message_id"`
+	ChannelID int64 `json:"channel_id"`
+}
+
+// RecentPosts implements Querier.RecentPosts.
+func (q *DBQuerier) RecentPosts(ctx context.Context, minID int64) ([]RecentPostsRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RecentPosts")
+	rows, err := q.conn.Query(ctx, recentPostsSQL, minID)
+	if err != nil {
+		return nil, fmt.Errorf("query RecentPosts: %w", err)
+	}
+	defer rows.Close()
+	items := []RecentPostsRow{}
+	for rows.Next() {
+		var item RecentPostsRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID); err != nil {
+			return nil, fmt.Errorf("scan RecentPosts row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close RecentPosts rows: %w", err)
+	}
+	return items, err
+}
+
+// RecentPostsBatch implements Querier.RecentPostsBatch.
+func (q *DBQuerier) RecentPostsBatch(batch genericBatch, minID int64) {
+	batch.Queue(recentPostsSQL, minID)
+}
+
+// RecentPostsScan implements Querier.RecentPostsScan.
+func (q *DBQuerier) RecentPostsScan(results pgx.BatchResults) ([]RecentPostsRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query RecentPostsBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []RecentPostsRow{}
+	for rows.Next() {
+		var item RecentPostsRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID); err != nil {
+			return nil, fmt.Errorf("scan RecentPostsBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close RecentPostsBatch rows: %w", err)
+	}
+	return items, err
+}
+
 const removeStarboardSQL = `delete from starboard_messages where message_id = $1
 or starboard_id = $1;`
 
@@ -472,35 +1055,74 @@ func (q *DBQuerier) RemoveStarboardScan(results pgx.BatchResults) (pgconn.Comman
 	return cmdTag, err
 }
 
+const removeBoardMessageSQL = `delete from starboard_messages where message_id = $1 and board_id = $2;`
+
+// RemoveBoardMessage implements Querier.RemoveBoardMessage.
+func (q *DBQuerier) RemoveBoardMessage(ctx context.Context, messageID int64, boardID int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveBoardMessage")
+	cmdTag, err := q.conn.Exec(ctx, removeBoardMessageSQL, messageID, boardID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveBoardMessage: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveBoardMessageBatch implements Querier.RemoveBoardMessageBatch.
+func (q *DBQuerier) RemoveBoardMessageBatch(batch genericBatch, messageID int64, boardID int) {
+	batch.Queue(removeBoardMessageSQL, messageID, boardID)
+}
+
+// RemoveBoardMessageScan implements Querier.RemoveBoardMessageScan.
+func (q *DBQuerier) RemoveBoardMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveBoardMessageBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
 const insertStarboardSQL = `insert into starboard_messages
-(message_id, channel_id, guild_id, starboard_id)
+(message_id, channel_id, guild_id, starboard_id, board_id, starboard_channel_id, author_id, star_count)
 values (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
 ) returning *;`
 
 type InsertStarboardParams struct {
-	MessageID   int64
-	ChannelID   int64
-	GuildID     int64
-	StarboardID int64
+	MessageID          int64
+	ChannelID          int64
+	GuildID            int64
+	StarboardID        int64
+	BoardID            int
+	StarboardChannelID int64
+	AuthorID           int64
+	StarCount          int
 }
 
 type InsertStarboardRow struct {
-	MessageID   int64 `json:"message_id"`
-	ChannelID   int64 `json:"channel_id"`
-	GuildID     int64 `json:"guild_id"`
-	StarboardID int64 `json:"starboard_id"`
+	MessageID          int64 `json:"message_id"`
+	ChannelID          int64 `json:"channel_id"`
+	GuildID            int64 `json:"guild_id"`
+	StarboardID        int64 `json:"starboard_id"`
+	BoardID            int   `json:"board_id"`
+	StarboardChannelID int64 `json:"starboard_channel_id"`
+	AuthorID           int64 `json:"author_id"`
+	StarCount          int   `json:"star_count"`
+	Locked             bool  `json:"locked"`
 }
 
 // InsertStarboard implements Querier.InsertStarboard.
 func (q *DBQuerier) InsertStarboard(ctx context.Context, params InsertStarboardParams) (InsertStarboardRow, error) {
 	ctx = context.WithValue(ctx, "pggen_query_name", "InsertStarboard")
-	row := q.conn.QueryRow(ctx, insertStarboardSQL, params.MessageID, params.ChannelID, params.GuildID, params.StarboardID)
+	row := q.conn.QueryRow(ctx, insertStarboardSQL, params.MessageID, params.ChannelID, params.GuildID, params.StarboardID, params.BoardID, params.StarboardChannelID, params.AuthorID, params.StarCount)
 	var item InsertStarboardRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
 		return item, fmt.Errorf("query InsertStarboard: %w", err)
 	}
 	return item, nil
@@ -508,56 +1130,1338 @@ func (q *DBQuerier) InsertStarboard(ctx context.Context, params InsertStarboardP
 
 // InsertStarboardBatch implements Querier.InsertStarboardBatch.
 func (q *DBQuerier) InsertStarboardBatch(batch genericBatch, params InsertStarboardParams) {
-	batch.Queue(insertStarboardSQL, params.MessageID, params.ChannelID, params.GuildID, params.StarboardID)
+	batch.Queue(insertStarboardSQL, params.MessageID, params.ChannelID, params.GuildID, params.StarboardID, params.BoardID, params.StarboardChannelID, params.AuthorID, params.StarCount)
 }
 
 // InsertStarboardScan implements Querier.InsertStarboardScan.
 func (q *DBQuerier) InsertStarboardScan(results pgx.BatchResults) (InsertStarboardRow, error) {
 	row := results.QueryRow()
 	var item InsertStarboardRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked); err != nil {
 		return item, fmt.Errorf("scan InsertStarboardBatch row: %w", err)
 	}
 	return item, nil
 }
 
-const getStarboardSQL = `select * from starboard_messages
-where message_id = $1
-or starboard_id = $1;`
+const setStarCountSQL = `update starboard_messages set star_count = $1
+where message_id = $2 and board_id = $3;`
+
+type SetStarCountParams struct {
+	StarCount int
+	MessageID int64
+	BoardID   int
+}
+
+// SetStarCount implements Querier.SetStarCount.
+func (q *DBQuerier) SetStarCount(ctx context.Context, params SetStarCountParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetStarCount")
+	cmdTag, err := q.conn.Exec(ctx, setStarCountSQL, params.StarCount, params.MessageID, params.BoardID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetStarCount: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetStarCountBatch implements Querier.SetStarCountBatch.
+func (q *DBQuerier) SetStarCountBatch(batch genericBatch, params SetStarCountParams) {
+	batch.Queue(setStarCountSQL, params.StarCount, params.MessageID, params.BoardID)
+}
+
+// SetStarCountScan implements Querier.SetStarCountScan.
+func (q *DBQuerier) SetStarCountScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetStarCountBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const boardsSQL = `select * from starboards where guild_id = $1 order by id;`
+
+type BoardsRow struct {
+	ID            int     `json:"id"`
+	GuildID       int64   `json:"guild_id"`
+	Name          string  `json:"name"`
+	ChannelID     int64   `json:"channel_id"`
+	Emoji         string  `json:"emoji"`
+	ReactionLimit int     `json:"reaction_limit"`
+	AllowSelfStar bool    `json:"allow_self_star"`
+	Channels      []int64 `json:"channels"`
+}
+
+// Boards implements Querier.Boards.
+func (q *DBQuerier) Boards(ctx context.Context, guildID int64) ([]BoardsRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "Boards")
+	rows, err := q.conn.Query(ctx, boardsSQL, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("query Boards: %w", err)
+	}
+	defer rows.Close()
+	items := []BoardsRow{}
+	for rows.Next() {
+		var item BoardsRow
+		if err := rows.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+			return nil, fmt.Errorf("scan Boards row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close Boards rows: %w", err)
+	}
+	return items, err
+}
+
+// BoardsBatch implements Querier.BoardsBatch.
+func (q *DBQuerier) BoardsBatch(batch genericBatch, guildID int64) {
+	batch.Queue(boardsSQL, guildID)
+}
+
+// BoardsScan implements Querier.BoardsScan.
+func (q *DBQuerier) BoardsScan(results pgx.BatchResults) ([]BoardsRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query BoardsBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []BoardsRow{}
+	for rows.Next() {
+		var item BoardsRow
+		if err := rows.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+			return nil, fmt.Errorf("scan BoardsBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close BoardsBatch rows: %w", err)
+	}
+	return items, err
+}
 
-type GetStarboardRow struct {
-	MessageID   int64 `json:"message_id"`
-	ChannelID   int64 `json:"channel_id"`
-	GuildID     int64 `json:"guild_id"`
-	StarboardID int64 `json:"starboard_id"`
+const boardSQL = `select * from starboards where guild_id = $1 and lower(name) = lower($2);`
+
+type BoardRow struct {
+	ID            int     `json:"id"`
+	GuildID       int64   `json:"guild_id"`
+	Name          string  `json:"name"`
+	ChannelID     int64   `json:"channel_id"`
+	Emoji         string  `json:"emoji"`
+	ReactionLimit int     `json:"reaction_limit"`
+	AllowSelfStar bool    `json:"allow_self_star"`
+	Channels      []int64 `json:"channels"`
 }
 
-// GetStarboard implements Querier.GetStarboard.
-func (q *DBQuerier) GetStarboard(ctx context.Context, messageID int64) (GetStarboardRow, error) {
-	ctx = context.WithValue(ctx, "pggen_query_name", "GetStarboard")
-	row := q.conn.QueryRow(ctx, getStarboardSQL, messageID)
-	var item GetStarboardRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
-		return item, fmt.Errorf("query GetStarboard: %w", err)
+// Board implements Querier.Board.
+func (q *DBQuerier) Board(ctx context.Context, guildID int64, name string) (BoardRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "Board")
+	row := q.conn.QueryRow(ctx, boardSQL, guildID, name)
+	var item BoardRow
+	if err := row.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+		return item, fmt.Errorf("query Board: %w", err)
 	}
 	return item, nil
 }
 
-// GetStarboardBatch implements Querier.GetStarboardBatch.
-func (q *DBQuerier) GetStarboardBatch(batch genericBatch, messageID int64) {
-	batch.Queue(getStarboardSQL, messageID)
+// BoardBatch implements Querier.BoardBatch.
+func (q *DBQuerier) BoardBatch(batch genericBatch, guildID int64, name string) {
+	batch.Queue(boardSQL, guildID, name)
 }
 
-// GetStarboardScan implements Querier.GetStarboardScan.
-func (q *DBQuerier) GetStarboardScan(results pgx.BatchResults) (GetStarboardRow, error) {
+// BoardScan implements Querier.BoardScan.
+func (q *DBQuerier) BoardScan(results pgx.BatchResults) (BoardRow, error) {
 	row := results.QueryRow()
-	var item GetStarboardRow
-	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID); err != nil {
-		return item, fmt.Errorf("scan GetStarboardBatch row: %w", err)
+	var item BoardRow
+	if err := row.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+		return item, fmt.Errorf("scan BoardBatch row: %w", err)
 	}
 	return item, nil
 }
 
+const createBoardSQL = `insert into starboards (guild_id, name, channel_id, emoji)
+values ($1, $2, $3, $4)
+returning *;`
+
+type CreateBoardParams struct {
+	GuildID   int64
+	Name      string
+	ChannelID int64
+	Emoji     string
+}
+
+type CreateBoardRow struct {
+	ID            int     `json:"id"`
+	GuildID       int64   `json:"guild_id"`
+	Name          string  `json:"name"`
+	ChannelID     int64   `json:"channel_id"`
+	Emoji         string  `json:"emoji"`
+	ReactionLimit int     `json:"reaction_limit"`
+	AllowSelfStar bool    `json:"allow_self_star"`
+	Channels      []int64 `json:"channels"`
+}
+
+// CreateBoard implements Querier.CreateBoard.
+func (q *DBQuerier) CreateBoard(ctx context.Context, params CreateBoardParams) (CreateBoardRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "CreateBoard")
+	row := q.conn.QueryRow(ctx, createBoardSQL, params.GuildID, params.Name, params.ChannelID, params.Emoji)
+	var item CreateBoardRow
+	if err := row.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+		return item, fmt.Errorf("query CreateBoard: %w", err)
+	}
+	return item, nil
+}
+
+// CreateBoardBatch implements Querier.CreateBoardBatch.
+func (q *DBQuerier) CreateBoardBatch(batch genericBatch, params CreateBoardParams) {
+	batch.Queue(createBoardSQL, params.GuildID, params.Name, params.ChannelID, params.Emoji)
+}
+
+// CreateBoardScan implements Querier.CreateBoardScan.
+func (q *DBQuerier) CreateBoardScan(results pgx.BatchResults) (CreateBoardRow, error) {
+	row := results.QueryRow()
+	var item CreateBoardRow
+	if err := row.Scan(&item.ID, &item.GuildID, &item.Name, &item.ChannelID, &item.Emoji, &item.ReactionLimit, &item.AllowSelfStar, &item.Channels); err != nil {
+		return item, fmt.Errorf("scan CreateBoardBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const deleteBoardSQL = `delete from starboards where id = $1;`
+
+// DeleteBoard implements Querier.DeleteBoard.
+func (q *DBQuerier) DeleteBoard(ctx context.Context, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "DeleteBoard")
+	cmdTag, err := q.conn.Exec(ctx, deleteBoardSQL, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query DeleteBoard: %w", err)
+	}
+	return cmdTag, err
+}
+
+// DeleteBoardBatch implements Querier.DeleteBoardBatch.
+func (q *DBQuerier) DeleteBoardBatch(batch genericBatch, id int) {
+	batch.Queue(deleteBoardSQL, id)
+}
+
+// DeleteBoardScan implements Querier.DeleteBoardScan.
+func (q *DBQuerier) DeleteBoardScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec DeleteBoardBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setBoardChannelSQL = `update starboards set channel_id = $1 where id = $2;`
+
+// SetBoardChannel implements Querier.SetBoardChannel.
+func (q *DBQuerier) SetBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetBoardChannel")
+	cmdTag, err := q.conn.Exec(ctx, setBoardChannelSQL, channelID, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetBoardChannel: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetBoardChannelBatch implements Querier.SetBoardChannelBatch.
+func (q *DBQuerier) SetBoardChannelBatch(batch genericBatch, channelID int64, id int) {
+	batch.Queue(setBoardChannelSQL, channelID, id)
+}
+
+// SetBoardChannelScan implements Querier.SetBoardChannelScan.
+func (q *DBQuerier) SetBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetBoardChannelBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setBoardEmojiSQL = `update starboards set emoji = $1 where id = $2;`
+
+// SetBoardEmoji implements Querier.SetBoardEmoji.
+func (q *DBQuerier) SetBoardEmoji(ctx context.Context, emoji string, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetBoardEmoji")
+	cmdTag, err := q.conn.Exec(ctx, setBoardEmojiSQL, emoji, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetBoardEmoji: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetBoardEmojiBatch implements Querier.SetBoardEmojiBatch.
+func (q *DBQuerier) SetBoardEmojiBatch(batch genericBatch, emoji string, id int) {
+	batch.Queue(setBoardEmojiSQL, emoji, id)
+}
+
+// SetBoardEmojiScan implements Querier.SetBoardEmojiScan.
+func (q *DBQuerier) SetBoardEmojiScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetBoardEmojiBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setBoardReactionLimitSQL = `update starboards set reaction_limit = $1 where id = $2;`
+
+// SetBoardReactionLimit implements Querier.SetBoardReactionLimit.
+func (q *DBQuerier) SetBoardReactionLimit(ctx context.Context, reactionLimit int, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetBoardReactionLimit")
+	cmdTag, err := q.conn.Exec(ctx, setBoardReactionLimitSQL, reactionLimit, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetBoardReactionLimit: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetBoardReactionLimitBatch implements Querier.SetBoardReactionLimitBatch.
+func (q *DBQuerier) SetBoardReactionLimitBatch(batch genericBatch, reactionLimit int, id int) {
+	batch.Queue(setBoardReactionLimitSQL, reactionLimit, id)
+}
+
+// SetBoardReactionLimitScan implements Querier.SetBoardReactionLimitScan.
+func (q *DBQuerier) SetBoardReactionLimitScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetBoardReactionLimitBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setBoardAllowSelfStarSQL = `update starboards set allow_self_star = $1 where id = $2;`
+
+// SetBoardAllowSelfStar implements Querier.SetBoardAllowSelfStar.
+func (q *DBQuerier) SetBoardAllowSelfStar(ctx context.Context, allowSelfStar bool, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetBoardAllowSelfStar")
+	cmdTag, err := q.conn.Exec(ctx, setBoardAllowSelfStarSQL, allowSelfStar, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetBoardAllowSelfStar: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetBoardAllowSelfStarBatch implements Querier.SetBoardAllowSelfStarBatch.
+func (q *DBQuerier) SetBoardAllowSelfStarBatch(batch genericBatch, allowSelfStar bool, id int) {
+	batch.Queue(setBoardAllowSelfStarSQL, allowSelfStar, id)
+}
+
+// SetBoardAllowSelfStarScan implements Querier.SetBoardAllowSelfStarScan.
+func (q *DBQuerier) SetBoardAllowSelfStarScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetBoardAllowSelfStarBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const addBoardChannelSQL = `update starboards set channels = array_append(channels, $1)
+where id = $2 and not $1 = any(channels);`
+
+// AddBoardChannel implements Querier.AddBoardChannel.
+func (q *DBQuerier) AddBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "AddBoardChannel")
+	cmdTag, err := q.conn.Exec(ctx, addBoardChannelSQL, channelID, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query AddBoardChannel: %w", err)
+	}
+	return cmdTag, err
+}
+
+// AddBoardChannelBatch implements Querier.AddBoardChannelBatch.
+func (q *DBQuerier) AddBoardChannelBatch(batch genericBatch, channelID int64, id int) {
+	batch.Queue(addBoardChannelSQL, channelID, id)
+}
+
+// AddBoardChannelScan implements Querier.AddBoardChannelScan.
+func (q *DBQuerier) AddBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec AddBoardChannelBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const removeBoardChannelSQL = `update starboards set channels = array_remove(channels, $1) where id = $2;`
+
+// RemoveBoardChannel implements Querier.RemoveBoardChannel.
+func (q *DBQuerier) RemoveBoardChannel(ctx context.Context, channelID int64, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveBoardChannel")
+	cmdTag, err := q.conn.Exec(ctx, removeBoardChannelSQL, channelID, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveBoardChannel: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveBoardChannelBatch implements Querier.RemoveBoardChannelBatch.
+func (q *DBQuerier) RemoveBoardChannelBatch(batch genericBatch, channelID int64, id int) {
+	batch.Queue(removeBoardChannelSQL, channelID, id)
+}
+
+// RemoveBoardChannelScan implements Querier.RemoveBoardChannelScan.
+func (q *DBQuerier) RemoveBoardChannelScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveBoardChannelBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const clearBoardChannelsSQL = `update starboards set channels = array[]::bigint[] where id = $1;`
+
+// ClearBoardChannels implements Querier.ClearBoardChannels.
+func (q *DBQuerier) ClearBoardChannels(ctx context.Context, id int) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "ClearBoardChannels")
+	cmdTag, err := q.conn.Exec(ctx, clearBoardChannelsSQL, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query ClearBoardChannels: %w", err)
+	}
+	return cmdTag, err
+}
+
+// ClearBoardChannelsBatch implements Querier.ClearBoardChannelsBatch.
+func (q *DBQuerier) ClearBoardChannelsBatch(batch genericBatch, id int) {
+	batch.Queue(clearBoardChannelsSQL, id)
+}
+
+// ClearBoardChannelsScan implements Querier.ClearBoardChannelsScan.
+func (q *DBQuerier) ClearBoardChannelsScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec ClearBoardChannelsBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const overridesSQL = `select
+o.board_id,
+b.name as board_name,
+o.channel_id,
+coalesce(o.disabled, false) as disabled,
+coalesce(o.starboard, 0) as starboard,
+coalesce(o.emoji, '') as emoji,
+coalesce(o.reaction_limit, 0) as reaction_limit
+from starboard_overrides o
+join starboards b on b.id = o.board_id
+where o.guild_id = $1
+order by o.board_id, o.channel_id;`
+
+type OverridesRow struct {
+	BoardID       int    `json:"board_id"`
+	BoardName     string `json:"board_name"`
+	ChannelID     int64  `json:"channel_id"`
+	Disabled      bool   `json:"disabled"`
+	Starboard     int64  `json:"starboard"`
+	Emoji         string `json:"emoji"`
+	ReactionLimit int    `json:"reaction_limit"`
+}
+
+// Overrides implements Querier.Overrides.
+func (q *DBQuerier) Overrides(ctx context.Context, guildID int64) ([]OverridesRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "Overrides")
+	rows, err := q.conn.Query(ctx, overridesSQL, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("query Overrides: %w", err)
+	}
+	defer rows.Close()
+	items := []OverridesRow{}
+	for rows.Next() {
+		var item OverridesRow
+		if err := rows.Scan(&item.BoardID, &item.BoardName, &item.ChannelID, &item.Disabled, &item.Starboard, &item.Emoji, &item.ReactionLimit); err != nil {
+			return nil, fmt.Errorf("scan Overrides row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close Overrides rows: %w", err)
+	}
+	return items, err
+}
+
+// OverridesBatch implements Querier.OverridesBatch.
+func (q *DBQuerier) OverridesBatch(batch genericBatch, guildID int64) {
+	batch.Queue(overridesSQL, guildID)
+}
+
+// OverridesScan implements Querier.OverridesScan.
+func (q *DBQuerier) OverridesScan(results pgx.BatchResults) ([]OverridesRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query OverridesBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []OverridesRow{}
+	for rows.Next() {
+		var item OverridesRow
+		if err := rows.Scan(&item.BoardID, &item.BoardName, &item.ChannelID, &item.Disabled, &item.Starboard, &item.Emoji, &item.ReactionLimit); err != nil {
+			return nil, fmt.Errorf("scan OverridesBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close OverridesBatch rows: %w", err)
+	}
+	return items, err
+}
+
+const setOverrideDisabledSQL = `insert into starboard_overrides (board_id, channel_id, guild_id, disabled)
+values ($1, $2, $3, $4)
+on conflict (board_id, channel_id) do update set disabled = $4;`
+
+type SetOverrideDisabledParams struct {
+	BoardID   int
+	ChannelID int64
+	GuildID   int64
+	Disabled  bool
+}
+
+// SetOverrideDisabled implements Querier.SetOverrideDisabled.
+func (q *DBQuerier) SetOverrideDisabled(ctx context.Context, params SetOverrideDisabledParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetOverrideDisabled")
+	cmdTag, err := q.conn.Exec(ctx, setOverrideDisabledSQL, params.BoardID, params.ChannelID, params.GuildID, params.Disabled)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetOverrideDisabled: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetOverrideDisabledBatch implements Querier.SetOverrideDisabledBatch.
+func (q *DBQuerier) SetOverrideDisabledBatch(batch genericBatch, params SetOverrideDisabledParams) {
+	batch.Queue(setOverrideDisabledSQL, params.BoardID, params.ChannelID, params.GuildID, params.Disabled)
+}
+
+// SetOverrideDisabledScan implements Querier.SetOverrideDisabledScan.
+func (q *DBQuerier) SetOverrideDisabledScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetOverrideDisabledBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setOverrideStarboardSQL = `insert into starboard_overrides (board_id, channel_id, guild_id, starboard)
+values ($1, $2, $3, $4)
+on conflict (board_id, channel_id) do update set starboard = $4;`
+
+type SetOverrideStarboardParams struct {
+	BoardID   int
+	ChannelID int64
+	GuildID   int64
+	Starboard int64
+}
+
+// SetOverrideStarboard implements Querier.SetOverrideStarboard.
+func (q *DBQuerier) SetOverrideStarboard(ctx context.Context, params SetOverrideStarboardParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetOverrideStarboard")
+	cmdTag, err := q.conn.Exec(ctx, setOverrideStarboardSQL, params.BoardID, params.ChannelID, params.GuildID, params.Starboard)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetOverrideStarboard: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetOverrideStarboardBatch implements Querier.SetOverrideStarboardBatch.
+func (q *DBQuerier) SetOverrideStarboardBatch(batch genericBatch, params SetOverrideStarboardParams) {
+	batch.Queue(setOverrideStarboardSQL, params.BoardID, params.ChannelID, params.GuildID, params.Starboard)
+}
+
+// SetOverrideStarboardScan implements Querier.SetOverrideStarboardScan.
+func (q *DBQuerier) SetOverrideStarboardScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetOverrideStarboardBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setOverrideEmojiSQL = `insert into starboard_overrides (board_id, channel_id, guild_id, emoji)
+values ($1, $2, $3, $4)
+on conflict (board_id, channel_id) do update set emoji = $4;`
+
+type SetOverrideEmojiParams struct {
+	BoardID   int
+	ChannelID int64
+	GuildID   int64
+	Emoji     string
+}
+
+// SetOverrideEmoji implements Querier.SetOverrideEmoji.
+func (q *DBQuerier) SetOverrideEmoji(ctx context.Context, params SetOverrideEmojiParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetOverrideEmoji")
+	cmdTag, err := q.conn.Exec(ctx, setOverrideEmojiSQL, params.BoardID, params.ChannelID, params.GuildID, params.Emoji)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetOverrideEmoji: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetOverrideEmojiBatch implements Querier.SetOverrideEmojiBatch.
+func (q *DBQuerier) SetOverrideEmojiBatch(batch genericBatch, params SetOverrideEmojiParams) {
+	batch.Queue(setOverrideEmojiSQL, params.BoardID, params.ChannelID, params.GuildID, params.Emoji)
+}
+
+// SetOverrideEmojiScan implements Querier.SetOverrideEmojiScan.
+func (q *DBQuerier) SetOverrideEmojiScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetOverrideEmojiBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const setOverrideReactionLimitSQL = `insert into starboard_overrides (board_id, channel_id, guild_id, reaction_limit)
+values ($1, $2, $3, $4)
+on conflict (board_id, channel_id) do update set reaction_limit = $4;`
+
+type SetOverrideReactionLimitParams struct {
+	BoardID       int
+	ChannelID     int64
+	GuildID       int64
+	ReactionLimit int
+}
+
+// SetOverrideReactionLimit implements Querier.SetOverrideReactionLimit.
+func (q *DBQuerier) SetOverrideReactionLimit(ctx context.Context, params SetOverrideReactionLimitParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetOverrideReactionLimit")
+	cmdTag, err := q.conn.Exec(ctx, setOverrideReactionLimitSQL, params.BoardID, params.ChannelID, params.GuildID, params.ReactionLimit)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetOverrideReactionLimit: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetOverrideReactionLimitBatch implements Querier.SetOverrideReactionLimitBatch.
+func (q *DBQuerier) SetOverrideReactionLimitBatch(batch genericBatch, params SetOverrideReactionLimitParams) {
+	batch.Queue(setOverrideReactionLimitSQL, params.BoardID, params.ChannelID, params.GuildID, params.ReactionLimit)
+}
+
+// SetOverrideReactionLimitScan implements Querier.SetOverrideReactionLimitScan.
+func (q *DBQuerier) SetOverrideReactionLimitScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetOverrideReactionLimitBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const removeOverrideSQL = `delete from starboard_overrides where board_id = $1 and channel_id = $2;`
+
+// RemoveOverride implements Querier.RemoveOverride.
+func (q *DBQuerier) RemoveOverride(ctx context.Context, boardID int, channelID int64) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveOverride")
+	cmdTag, err := q.conn.Exec(ctx, removeOverrideSQL, boardID, channelID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveOverride: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveOverrideBatch implements Querier.RemoveOverrideBatch.
+func (q *DBQuerier) RemoveOverrideBatch(batch genericBatch, boardID int, channelID int64) {
+	batch.Queue(removeOverrideSQL, boardID, channelID)
+}
+
+// RemoveOverrideScan implements Querier.RemoveOverrideScan.
+func (q *DBQuerier) RemoveOverrideScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveOverrideBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const topMessagesSQL = `select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = $1
+and ($2 = 0 or m.board_id = $2)
+and m.message_id >= $3
+order by m.star_count desc, m.message_id desc
+limit 500;`
+
+type TopMessagesParams struct {
+	GuildID int64
+	BoardID int
+	MinID   int64
+}
+
+type TopMessagesRow struct {
+	MessageID          int64  `json:"message_id"`
+	ChannelID          int64  `json:"channel_id"`
+	GuildID            int64  `json:"guild_id"`
+	StarboardID        int64  `json:"starboard_id"`
+	BoardID            int    `json:"board_id"`
+	StarboardChannelID int64  `json:"starboard_channel_id"`
+	AuthorID           int64  `json:"author_id"`
+	StarCount          int    `json:"star_count"`
+	Locked             bool   `json:"locked"`
+	BoardName          string `json:"board_name"`
+}
+
+// TopMessages implements Querier.TopMessages.
+func (q *DBQuerier) TopMessages(ctx context.Context, params TopMessagesParams) ([]TopMessagesRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "TopMessages")
+	rows, err := q.conn.Query(ctx, topMessagesSQL, params.GuildID, params.BoardID, params.MinID)
+	if err != nil {
+		return nil, fmt.Errorf("query TopMessages: %w", err)
+	}
+	defer rows.Close()
+	items := []TopMessagesRow{}
+	for rows.Next() {
+		var item TopMessagesRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+			return nil, fmt.Errorf("scan TopMessages row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close TopMessages rows: %w", err)
+	}
+	return items, err
+}
+
+// TopMessagesBatch implements Querier.TopMessagesBatch.
+func (q *DBQuerier) TopMessagesBatch(batch genericBatch, params TopMessagesParams) {
+	batch.Queue(topMessagesSQL, params.GuildID, params.BoardID, params.MinID)
+}
+
+// TopMessagesScan implements Querier.TopMessagesScan.
+func (q *DBQuerier) TopMessagesScan(results pgx.BatchResults) ([]TopMessagesRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query TopMessagesBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []TopMessagesRow{}
+	for rows.Next() {
+		var item TopMessagesRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+			return nil, fmt.Errorf("scan TopMessagesBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close TopMessagesBatch rows: %w", err)
+	}
+	return items, err
+}
+
+const randomMessageSQL = `select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = $1
+and ($2 = 0 or m.board_id = $2)
+order by random()
+limit 1;`
+
+type RandomMessageRow struct {
+	MessageID          int64  `json:"message_id"`
+	ChannelID          int64  `json:"channel_id"`
+	GuildID            int64  `json:"guild_id"`
+	StarboardID        int64  `json:"starboard_id"`
+	BoardID            int    `json:"board_id"`
+	StarboardChannelID int64  `json:"starboard_channel_id"`
+	AuthorID           int64  `json:"author_id"`
+	StarCount          int    `json:"star_count"`
+	Locked             bool   `json:"locked"`
+	BoardName          string `json:"board_name"`
+}
+
+// RandomMessage implements Querier.RandomMessage.
+func (q *DBQuerier) RandomMessage(ctx context.Context, guildID int64, boardID int) (RandomMessageRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RandomMessage")
+	row := q.conn.QueryRow(ctx, randomMessageSQL, guildID, boardID)
+	var item RandomMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+		return item, fmt.Errorf("query RandomMessage: %w", err)
+	}
+	return item, nil
+}
+
+// RandomMessageBatch implements Querier.RandomMessageBatch.
+func (q *DBQuerier) RandomMessageBatch(batch genericBatch, guildID int64, boardID int) {
+	batch.Queue(randomMessageSQL, guildID, boardID)
+}
+
+// RandomMessageScan implements Querier.RandomMessageScan.
+func (q *DBQuerier) RandomMessageScan(results pgx.BatchResults) (RandomMessageRow, error) {
+	row := results.QueryRow()
+	var item RandomMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+		return item, fmt.Errorf("scan RandomMessageBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const userTopMessageSQL = `select m.*, b.name as board_name from starboard_messages m
+join starboards b on b.id = m.board_id
+where m.guild_id = $1 and m.author_id = $2
+order by m.star_count desc, m.message_id desc
+limit 1;`
+
+type UserTopMessageRow struct {
+	MessageID          int64  `json:"message_id"`
+	ChannelID          int64  `json:"channel_id"`
+	GuildID            int64  `json:"guild_id"`
+	StarboardID        int64  `json:"starboard_id"`
+	BoardID            int    `json:"board_id"`
+	StarboardChannelID int64  `json:"starboard_channel_id"`
+	AuthorID           int64  `json:"author_id"`
+	StarCount          int    `json:"star_count"`
+	Locked             bool   `json:"locked"`
+	BoardName          string `json:"board_name"`
+}
+
+// UserTopMessage implements Querier.UserTopMessage.
+func (q *DBQuerier) UserTopMessage(ctx context.Context, guildID int64, authorID int64) (UserTopMessageRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "UserTopMessage")
+	row := q.conn.QueryRow(ctx, userTopMessageSQL, guildID, authorID)
+	var item UserTopMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+		return item, fmt.Errorf("query UserTopMessage: %w", err)
+	}
+	return item, nil
+}
+
+// UserTopMessageBatch implements Querier.UserTopMessageBatch.
+func (q *DBQuerier) UserTopMessageBatch(batch genericBatch, guildID int64, authorID int64) {
+	batch.Queue(userTopMessageSQL, guildID, authorID)
+}
+
+// UserTopMessageScan implements Querier.UserTopMessageScan.
+func (q *DBQuerier) UserTopMessageScan(results pgx.BatchResults) (UserTopMessageRow, error) {
+	row := results.QueryRow()
+	var item UserTopMessageRow
+	if err := row.Scan(&item.MessageID, &item.ChannelID, &item.GuildID, &item.StarboardID, &item.BoardID, &item.StarboardChannelID, &item.AuthorID, &item.StarCount, &item.Locked, &item.BoardName); err != nil {
+		return item, fmt.Errorf("scan UserTopMessageBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const starsReceivedSQL = `select count(*) as messages, coalesce(sum(stars), 0)::bigint as stars from (
+    select max(star_count) as stars from starboard_messages
+    where guild_id = $1 and author_id = $2
+    group by message_id
+) s;`
+
+type StarsReceivedRow struct {
+	Messages int64 `json:"messages"`
+	Stars    int64 `json:"stars"`
+}
+
+// StarsReceived implements Querier.StarsReceived.
+func (q *DBQuerier) StarsReceived(ctx context.Context, guildID int64, authorID int64) (StarsReceivedRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "StarsReceived")
+	row := q.conn.QueryRow(ctx, starsReceivedSQL, guildID, authorID)
+	var item StarsReceivedRow
+	if err := row.Scan(&item.Messages, &item.Stars); err != nil {
+		return item, fmt.Errorf("query StarsReceived: %w", err)
+	}
+	return item, nil
+}
+
+// StarsReceivedBatch implements Querier.StarsReceivedBatch.
+func (q *DBQuerier) StarsReceivedBatch(batch genericBatch, guildID int64, authorID int64) {
+	batch.Queue(starsReceivedSQL, guildID, authorID)
+}
+
+// StarsReceivedScan implements Querier.StarsReceivedScan.
+func (q *DBQuerier) StarsReceivedScan(results pgx.BatchResults) (StarsReceivedRow, error) {
+	row := results.QueryRow()
+	var item StarsReceivedRow
+	if err := row.Scan(&item.Messages, &item.Stars); err != nil {
+		return item, fmt.Errorf("scan StarsReceivedBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const starsGivenSQL = `select count(*) from starboard_reactions where guild_id = $1 and user_id = $2;`
+
+// StarsGiven implements Querier.StarsGiven.
+func (q *DBQuerier) StarsGiven(ctx context.Context, guildID int64, userID int64) (int64, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "StarsGiven")
+	row := q.conn.QueryRow(ctx, starsGivenSQL, guildID, userID)
+	var item int64
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("query StarsGiven: %w", err)
+	}
+	return item, nil
+}
+
+// StarsGivenBatch implements Querier.StarsGivenBatch.
+func (q *DBQuerier) StarsGivenBatch(batch genericBatch, guildID int64, userID int64) {
+	batch.Queue(starsGivenSQL, guildID, userID)
+}
+
+// StarsGivenScan implements Querier.StarsGivenScan.
+func (q *DBQuerier) StarsGivenScan(results pgx.BatchResults) (int64, error) {
+	row := results.QueryRow()
+	var item int64
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("scan StarsGivenBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const starGiversSQL = `select user_id, count(*) as stars from starboard_reactions
+where guild_id = $1
+group by user_id
+order by stars desc, user_id
+limit 1000;`
+
+type StarGiversRow struct {
+	UserID int64 `json:"user_id"`
+	Stars  int64 `json:"stars"`
+}
+
+// StarGivers implements Querier.StarGivers.
+func (q *DBQuerier) StarGivers(ctx context.Context, guildID int64) ([]StarGiversRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "StarGivers")
+	rows, err := q.conn.Query(ctx, starGiversSQL, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("query StarGivers: %w", err)
+	}
+	defer rows.Close()
+	items := []StarGiversRow{}
+	for rows.Next() {
+		var item StarGiversRow
+		if err := rows.Scan(&item.UserID, &item.Stars); err != nil {
+			return nil, fmt.Errorf("scan StarGivers row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close StarGivers rows: %w", err)
+	}
+	return items, err
+}
+
+// StarGiversBatch implements Querier.StarGiversBatch.
+func (q *DBQuerier) StarGiversBatch(batch genericBatch, guildID int64) {
+	batch.Queue(starGiversSQL, guildID)
+}
+
+// StarGiversScan implements Querier.StarGiversScan.
+func (q *DBQuerier) StarGiversScan(results pgx.BatchResults) ([]StarGiversRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query StarGiversBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []StarGiversRow{}
+	for rows.Next() {
+		var item StarGiversRow
+		if err := rows.Scan(&item.UserID, &item.Stars); err != nil {
+			return nil, fmt.Errorf("scan StarGiversBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close StarGiversBatch rows: %w", err)
+	}
+	return items, err
+}
+
+const setLockedSQL = `update starboard_messages set locked = $1
+where message_id = $2 or starboard_id = $2;`
+
+// SetLocked implements Querier.SetLocked.
+func (q *DBQuerier) SetLocked(ctx context.Context, locked bool, id int64) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "SetLocked")
+	cmdTag, err := q.conn.Exec(ctx, setLockedSQL, locked, id)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query SetLocked: %w", err)
+	}
+	return cmdTag, err
+}
+
+// SetLockedBatch implements Querier.SetLockedBatch.
+func (q *DBQuerier) SetLockedBatch(batch genericBatch, locked bool, id int64) {
+	batch.Queue(setLockedSQL, locked, id)
+}
+
+// SetLockedScan implements Querier.SetLockedScan.
+func (q *DBQuerier) SetLockedScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec SetLockedBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const isTrashedSQL = `select exists(select 1 from starboard_trash where message_id = $1);`
+
+// IsTrashed implements Querier.IsTrashed.
+func (q *DBQuerier) IsTrashed(ctx context.Context, messageID int64) (bool, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "IsTrashed")
+	row := q.conn.QueryRow(ctx, isTrashedSQL, messageID)
+	var item bool
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("query IsTrashed: %w", err)
+	}
+	return item, nil
+}
+
+// IsTrashedBatch implements Querier.IsTrashedBatch.
+func (q *DBQuerier) IsTrashedBatch(batch genericBatch, messageID int64) {
+	batch.Queue(isTrashedSQL, messageID)
+}
+
+// IsTrashedScan implements Querier.IsTrashedScan.
+func (q *DBQuerier) IsTrashedScan(results pgx.BatchResults) (bool, error) {
+	row := results.QueryRow()
+	var item bool
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("scan IsTrashedBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const trashMessageSQL = `insert into starboard_trash (message_id, guild_id, moderator_id, reason)
+values ($1, $2, $3, $4)
+on conflict (message_id) do update set moderator_id = $3, reason = $4;`
+
+type TrashMessageParams struct {
+	MessageID   int64
+	GuildID     int64
+	ModeratorID int64
+	Reason      string
+}
+
+// TrashMessage implements Querier.TrashMessage.
+func (q *DBQuerier) TrashMessage(ctx context.Context, params TrashMessageParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "TrashMessage")
+	cmdTag, err := q.conn.Exec(ctx, trashMessageSQL, params.MessageID, params.GuildID, params.ModeratorID, params.Reason)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query TrashMessage: %w", err)
+	}
+	return cmdTag, err
+}
+
+// TrashMessageBatch implements Querier.TrashMessageBatch.
+func (q *DBQuerier) TrashMessageBatch(batch genericBatch, params TrashMessageParams) {
+	batch.Queue(trashMessageSQL, params.MessageID, params.GuildID, params.ModeratorID, params.Reason)
+}
+
+// TrashMessageScan implements Querier.TrashMessageScan.
+func (q *DBQuerier) TrashMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec TrashMessageBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const untrashMessageSQL = `delete from starboard_trash where message_id = $1 and guild_id = $2;`
+
+// UntrashMessage implements Querier.UntrashMessage.
+func (q *DBQuerier) UntrashMessage(ctx context.Context, messageID int64, guildID int64) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "UntrashMessage")
+	cmdTag, err := q.conn.Exec(ctx, untrashMessageSQL, messageID, guildID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query UntrashMessage: %w", err)
+	}
+	return cmdTag, err
+}
+
+// UntrashMessageBatch implements Querier.UntrashMessageBatch.
+func (q *DBQuerier) UntrashMessageBatch(batch genericBatch, messageID int64, guildID int64) {
+	batch.Queue(untrashMessageSQL, messageID, guildID)
+}
+
+// UntrashMessageScan implements Querier.UntrashMessageScan.
+func (q *DBQuerier) UntrashMessageScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec UntrashMessageBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const trashSQL = `select message_id, moderator_id, reason from starboard_trash
+where guild_id = $1 order by created desc;`
+
+type TrashRow struct {
+	MessageID   int64  `json:"message_id"`
+	ModeratorID int64  `json:"moderator_id"`
+	Reason      string `json:"reason"`
+}
+
+// Trash implements Querier.Trash.
+func (q *DBQuerier) Trash(ctx context.Context, guildID int64) ([]TrashRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "Trash")
+	rows, err := q.conn.Query(ctx, trashSQL, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("query Trash: %w", err)
+	}
+	defer rows.Close()
+	items := []TrashRow{}
+	for rows.Next() {
+		var item TrashRow
+		if err := rows.Scan(&item.MessageID, &item.ModeratorID, &item.Reason); err != nil {
+			return nil, fmt.Errorf("scan Trash row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close Trash rows: %w", err)
+	}
+	return items, err
+}
+
+// TrashBatch implements Querier.TrashBatch.
+func (q *DBQuerier) TrashBatch(batch genericBatch, guildID int64) {
+	batch.Queue(trashSQL, guildID)
+}
+
+// TrashScan implements Querier.TrashScan.
+func (q *DBQuerier) TrashScan(results pgx.BatchResults) ([]TrashRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query TrashBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []TrashRow{}
+	for rows.Next() {
+		var item TrashRow
+		if err := rows.Scan(&item.MessageID, &item.ModeratorID, &item.Reason); err != nil {
+			return nil, fmt.Errorf("scan TrashBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close TrashBatch rows: %w", err)
+	}
+	return items, err
+}
+
+const isBlacklistedSQL = `select exists(select 1 from starboard_blacklist where guild_id = $1 and user_id = $2);`
+
+// IsBlacklisted implements Querier.IsBlacklisted.
+func (q *DBQuerier) IsBlacklisted(ctx context.Context, guildID int64, userID int64) (bool, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "IsBlacklisted")
+	row := q.conn.QueryRow(ctx, isBlacklistedSQL, guildID, userID)
+	var item bool
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("query IsBlacklisted: %w", err)
+	}
+	return item, nil
+}
+
+// IsBlacklistedBatch implements Querier.IsBlacklistedBatch.
+func (q *DBQuerier) IsBlacklistedBatch(batch genericBatch, guildID int64, userID int64) {
+	batch.Queue(isBlacklistedSQL, guildID, userID)
+}
+
+// IsBlacklistedScan implements Querier.IsBlacklistedScan.
+func (q *DBQuerier) IsBlacklistedScan(results pgx.BatchResults) (bool, error) {
+	row := results.QueryRow()
+	var item bool
+	if err := row.Scan(&item); err != nil {
+		return item, fmt.Errorf("scan IsBlacklistedBatch row: %w", err)
+	}
+	return item, nil
+}
+
+const addBlacklistSQL = `insert into starboard_blacklist (guild_id, user_id, moderator_id, reason)
+values ($1, $2, $3, $4)
+on conflict (guild_id, user_id) do update set moderator_id = $3, reason = $4;`
+
+type AddBlacklistParams struct {
+	GuildID     int64
+	UserID      int64
+	ModeratorID int64
+	Reason      string
+}
+
+// AddBlacklist implements Querier.AddBlacklist.
+func (q *DBQuerier) AddBlacklist(ctx context.Context, params AddBlacklistParams) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "AddBlacklist")
+	cmdTag, err := q.conn.Exec(ctx, addBlacklistSQL, params.GuildID, params.UserID, params.ModeratorID, params.Reason)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query AddBlacklist: %w", err)
+	}
+	return cmdTag, err
+}
+
+// AddBlacklistBatch implements Querier.AddBlacklistBatch.
+func (q *DBQuerier) AddBlacklistBatch(batch genericBatch, params AddBlacklistParams) {
+	batch.Queue(addBlacklistSQL, params.GuildID, params.UserID, params.ModeratorID, params.Reason)
+}
+
+// AddBlacklistScan implements Querier.AddBlacklistScan.
+func (q *DBQuerier) AddBlacklistScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec AddBlacklistBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const removeBlacklistSQL = `delete from starboard_blacklist where guild_id = $1 and user_id = $2;`
+
+// RemoveBlacklist implements Querier.RemoveBlacklist.
+func (q *DBQuerier) RemoveBlacklist(ctx context.Context, guildID int64, userID int64) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveBlacklist")
+	cmdTag, err := q.conn.Exec(ctx, removeBlacklistSQL, guildID, userID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveBlacklist: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveBlacklistBatch implements Querier.RemoveBlacklistBatch.
+func (q *DBQuerier) RemoveBlacklistBatch(batch genericBatch, guildID int64, userID int64) {
+	batch.Queue(removeBlacklistSQL, guildID, userID)
+}
+
+// RemoveBlacklistScan implements Querier.RemoveBlacklistScan.
+func (q *DBQuerier) RemoveBlacklistScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveBlacklistBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const blacklistSQL = `select user_id, moderator_id, reason from starboard_blacklist
+where guild_id = $1 order by created desc;`
+
+type BlacklistRow struct {
+	UserID      int64  `json:"user_id"`
+	ModeratorID int64  `json:"moderator_id"`
+	Reason      string `json:"reason"`
+}
+
+// Blacklist implements Querier.Blacklist.
+func (q *DBQuerier) Blacklist(ctx context.Context, guildID int64) ([]BlacklistRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "Blacklist")
+	rows, err := q.conn.Query(ctx, blacklistSQL, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("query Blacklist: %w", err)
+	}
+	defer rows.Close()
+	items := []BlacklistRow{}
+	for rows.Next() {
+		var item BlacklistRow
+		if err := rows.Scan(&item.UserID, &item.ModeratorID, &item.Reason); err != nil {
+			return nil, fmt.Errorf("scan Blacklist row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close Blacklist rows: %w", err)
+	}
+	return items, err
+}
+
+// BlacklistBatch implements Querier.BlacklistBatch.
+func (q *DBQuerier) BlacklistBatch(batch genericBatch, guildID int64) {
+	batch.Queue(blacklistSQL, guildID)
+}
+
+// BlacklistScan implements Querier.BlacklistScan.
+func (q *DBQuerier) BlacklistScan(results pgx.BatchResults) ([]BlacklistRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query BlacklistBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []BlacklistRow{}
+	for rows.Next() {
+		var item BlacklistRow
+		if err := rows.Scan(&item.UserID, &item.ModeratorID, &item.Reason); err != nil {
+			return nil, fmt.Errorf("scan BlacklistBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close BlacklistBatch rows: %w", err)
+	}
+	return items, err
+}
+
+const removeUserReactionsSQL = `delete from starboard_reactions where guild_id = $1 and user_id = $2;`
+
+// RemoveUserReactions implements Querier.RemoveUserReactions.
+func (q *DBQuerier) RemoveUserReactions(ctx context.Context, guildID int64, userID int64) (pgconn.CommandTag, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "RemoveUserReactions")
+	cmdTag, err := q.conn.Exec(ctx, removeUserReactionsSQL, guildID, userID)
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec query RemoveUserReactions: %w", err)
+	}
+	return cmdTag, err
+}
+
+// RemoveUserReactionsBatch implements Querier.RemoveUserReactionsBatch.
+func (q *DBQuerier) RemoveUserReactionsBatch(batch genericBatch, guildID int64, userID int64) {
+	batch.Queue(removeUserReactionsSQL, guildID, userID)
+}
+
+// RemoveUserReactionsScan implements Querier.RemoveUserReactionsScan.
+func (q *DBQuerier) RemoveUserReactionsScan(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	cmdTag, err := results.Exec()
+	if err != nil {
+		return cmdTag, fmt.Errorf("exec RemoveUserReactionsBatch: %w", err)
+	}
+	return cmdTag, err
+}
+
+const userReactedPostsSQL = `select distinct r.message_id, m.channel_id from starboard_reactions r
+join starboard_messages m on m.message_id = r.message_id
+where r.guild_id = $1 and r.user_id = $2;`
+
+type UserReactedPostsRow struct {
+	MessageID int64 `json:"message_id"`
+	ChannelID int64 `json:"channel_id"`
+}
+
+// UserReactedPosts implements Querier.UserReactedPosts.
+func (q *DBQuerier) UserReactedPosts(ctx context.Context, guildID int64, userID int64) ([]UserReactedPostsRow, error) {
+	ctx = context.WithValue(ctx, "pggen_query_name", "UserReactedPosts")
+	rows, err := q.conn.Query(ctx, userReactedPostsSQL, guildID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query UserReactedPosts: %w", err)
+	}
+	defer rows.Close()
+	items := []UserReactedPostsRow{}
+	for rows.Next() {
+		var item UserReactedPostsRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID); err != nil {
+			return nil, fmt.Errorf("scan UserReactedPosts row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close UserReactedPosts rows: %w", err)
+	}
+	return items, err
+}
+
+// UserReactedPostsBatch implements Querier.UserReactedPostsBatch.
+func (q *DBQuerier) UserReactedPostsBatch(batch genericBatch, guildID int64, userID int64) {
+	batch.Queue(userReactedPostsSQL, guildID, userID)
+}
+
+// UserReactedPostsScan implements Querier.UserReactedPostsScan.
+func (q *DBQuerier) UserReactedPostsScan(results pgx.BatchResults) ([]UserReactedPostsRow, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("query UserReactedPostsBatch: %w", err)
+	}
+	defer rows.Close()
+	items := []UserReactedPostsRow{}
+	for rows.Next() {
+		var item UserReactedPostsRow
+		if err := rows.Scan(&item.MessageID, &item.ChannelID); err != nil {
+			return nil, fmt.Errorf("scan UserReactedPostsBatch row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("close UserReactedPostsBatch rows: %w", err)
+	}
+	return items, err
+}
+
 // textPreferrer wraps a pgtype.ValueTranscoder and sets the preferred encoding
 // format to text instead binary (the default). pggen uses the text format
 // when the OID is unknownOID because the binary format requires the OID.
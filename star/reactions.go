@@ -3,10 +3,10 @@ package star
 import (
 	"context"
 
-	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
-	"github.com/jackc/pgx"
 	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/star/queries"
 )
 
 func (bot *Bot) reactionAdd(ev *gateway.MessageReactionAddEvent) {
@@ -16,23 +16,16 @@ func (bot *Bot) reactionAdd(ev *gateway.MessageReactionAddEvent) {
 		return
 	}
 
-	cfg, err := bot.override(ev.GuildID, ch.ID, ch.ParentID)
-	if err != nil {
-		if errors.Cause(err) == pgx.ErrNoRows {
-			return
-		}
+	emoji := emojiString(ev.Emoji)
 
-		common.Log.Errorf("getting starboard config: %v", err)
-		return
-	}
-
-	if cfg.Disabled {
-		common.Log.Debugf("channel or category for %v has disabled starboard", ev.ChannelID)
+	boards, err := bot.emojiBoards(ev.GuildID, ch, emoji)
+	if err != nil {
+		common.Log.Errorf("getting starboards: %v", err)
 		return
 	}
 
-	if emojiString(ev.Emoji) != cfg.Emoji {
-		common.Log.Debugf("emoji %v isn't starboard emoji (%v)", emojiString(ev.Emoji), cfg.Emoji)
+	if len(boards) == 0 {
+		common.Log.Debugf("emoji %v isn't used by any starboard for %v", emoji, ev.ChannelID)
 		return
 	}
 
@@ -42,96 +35,107 @@ func (bot *Bot) reactionAdd(ev *gateway.MessageReactionAddEvent) {
 		common.Log.Errorf("error getting message %v: %v", ev.MessageID, err)
 		return
 	}
+	msg.GuildID = ev.GuildID
 
-	if msg.Author.ID == ev.UserID && !cfg.AllowSelfStar {
-		common.Log.Debug("self starring isn't allowed")
+	if bot.blacklisted(ev.GuildID, ev.UserID) || bot.ignored(*msg) {
 		return
 	}
 
-	count, err := bot.addReaction(msg.ID, ev.UserID)
+	// self stars are always stored, boards that don't allow them don't count them
+	_, err = bot.queries.AddReaction(context.Background(), queries.AddReactionParams{
+		UserID:    int64(ev.UserID),
+		MessageID: int64(msg.ID),
+		Emoji:     emoji,
+		GuildID:   int64(ev.GuildID),
+	})
 	if err != nil {
 		common.Log.Error("storing starboard reaction:", err)
 		return
 	}
 
-	err = bot.sendOrUpdateMessage(*msg, cfg, int(count))
-	if err != nil {
-		common.Log.Error("sending or updating starboard message:", err)
-	}
+	bot.updateBoards(*msg, boards)
 }
 
-func (bot *Bot) reactionRemoveAll(ev *gateway.MessageReactionRemoveAllEvent) {
-	ch, err := bot.RootChannel(ev.ChannelID)
-	if err != nil {
-		common.Log.Errorf("getting root channel: %v", err)
-		return
-	}
-
-	cfg, err := bot.override(ev.GuildID, ch.ID, ch.ParentID)
-	if err != nil {
-		if errors.Cause(err) == pgx.ErrNoRows {
-			return
+// updateBoards sends or updates the message on all the given boards.
+func (bot *Bot) updateBoards(msg discord.Message, boards []queries.ChannelBoardsRow) {
+	for _, b := range boards {
+		count, err := bot.reactionCount(msg, b)
+		if err != nil {
+			common.Log.Error("getting starboard reaction count:", err)
+			continue
 		}
 
-		common.Log.Errorf("getting starboard config: %v", err)
-		return
-	}
-
-	if cfg.Disabled {
-		common.Log.Debugf("channel or category for %v has disabled starboard", ev.ChannelID)
-		return
+		err = bot.sendOrUpdateMessage(msg, b, int(count))
+		if err != nil {
+			common.Log.Errorf("sending or updating starboard message on board %v: %v", b.ID, err)
+		}
 	}
+}
 
-	_, err = bot.queries.RemoveAllReactions(context.Background(), int64(ev.MessageID))
+func (bot *Bot) reactionRemoveAll(ev *gateway.MessageReactionRemoveAllEvent) {
+	_, err := bot.queries.RemoveAllReactions(context.Background(), int64(ev.MessageID))
 	if err != nil {
 		common.Log.Errorf("error removing all reactions: %v", err)
 		return
 	}
 
-	sm, err := bot.queries.StarboardMessage(context.Background(), int64(ev.MessageID))
+	bot.deleteAllMessages(ev.MessageID, false)
+}
+
+// deleteAllMessages deletes the message from every board it was posted to.
+// Locked posts are only deleted if force is true.
+func (bot *Bot) deleteAllMessages(id discord.MessageID, force bool) {
+	sms, err := bot.queries.StarboardMessages(context.Background(), int64(id))
 	if err != nil {
-		common.Log.Error("getting starboard db entry:", err)
+		common.Log.Error("getting starboard db entries:", err)
 		return
 	}
 
-	err = bot.deleteMessage(cfg, sm.StarboardID)
-	if err != nil {
-		common.Log.Error("deleting starboard message:", err)
+	for _, sm := range sms {
+		if sm.MessageID != int64(id) || (sm.Locked && !force) {
+			continue
+		}
+
+		err = bot.deleteMessage(sm.StarboardChannelID, sm.StarboardID)
+		if err != nil {
+			common.Log.Error("deleting starboard message:", err)
+		}
 	}
 }
 
 func (bot *Bot) reactionRemoveEmoji(ev *gateway.MessageReactionRemoveEmojiEvent) {
-	ch, err := bot.RootChannel(ev.ChannelID)
+	emoji := emojiString(ev.Emoji)
+
+	_, err := bot.queries.RemoveEmojiReactions(context.Background(), int64(ev.MessageID), emoji)
 	if err != nil {
-		common.Log.Errorf("getting root channel: %v", err)
+		common.Log.Errorf("error removing all reactions: %v", err)
 		return
 	}
 
-	cfg, err := bot.override(ev.GuildID, ch.ID, ch.ParentID)
+	ch, err := bot.RootChannel(ev.ChannelID)
 	if err != nil {
-		if errors.Cause(err) == pgx.ErrNoRows {
-			return
-		}
-
-		common.Log.Errorf("getting starboard config: %v", err)
+		common.Log.Errorf("getting root channel: %v", err)
 		return
 	}
 
-	if cfg.Disabled {
-		common.Log.Debugf("channel or category for %v has disabled starboard", ev.ChannelID)
+	boards, err := bot.emojiBoards(ev.GuildID, ch, emoji)
+	if err != nil {
+		common.Log.Errorf("getting starboards: %v", err)
 		return
 	}
 
-	if emojiString(ev.Emoji) != cfg.Emoji {
-		common.Log.Debugf("emoji %v isn't starboard emoji (%v)", emojiString(ev.Emoji), cfg.Emoji)
+	if len(boards) == 0 {
 		return
 	}
 
-	_, err = bot.queries.RemoveAllReactions(context.Background(), int64(ev.MessageID))
+	msg, err := bot.State.Message(ev.ChannelID, ev.MessageID)
 	if err != nil {
-		common.Log.Errorf("error removing all reactions: %v", err)
+		common.Log.Errorf("error getting message %v: %v", ev.MessageID, err)
 		return
 	}
+
+	// the reaction count is now 0, so this removes the message from the boards
+	bot.updateBoards(*msg, boards)
 }
 
 func (bot *Bot) reactionRemove(ev *gateway.MessageReactionRemoveEvent) {
@@ -141,23 +145,16 @@ func (bot *Bot) reactionRemove(ev *gateway.MessageReactionRemoveEvent) {
 		return
 	}
 
-	cfg, err := bot.override(ev.GuildID, ch.ID, ch.ParentID)
-	if err != nil {
-		if errors.Cause(err) == pgx.ErrNoRows {
-			return
-		}
-
-		common.Log.Errorf("getting starboard config: %v", err)
-		return
-	}
+	emoji := emojiString(ev.Emoji)
 
-	if cfg.Disabled {
-		common.Log.Debugf("channel or category for %v has disabled starboard", ev.ChannelID)
+	boards, err := bot.emojiBoards(ev.GuildID, ch, emoji)
+	if err != nil {
+		common.Log.Errorf("getting starboards: %v", err)
 		return
 	}
 
-	if emojiString(ev.Emoji) != cfg.Emoji {
-		common.Log.Debugf("emoji %v isn't starboard emoji (%v)", emojiString(ev.Emoji), cfg.Emoji)
+	if len(boards) == 0 {
+		common.Log.Debugf("emoji %v isn't used by any starboard for %v", emoji, ev.ChannelID)
 		return
 	}
 
@@ -167,22 +164,23 @@ func (bot *Bot) reactionRemove(ev *gateway.MessageReactionRemoveEvent) {
 		common.Log.Errorf("error getting message %v: %v", ev.MessageID, err)
 		return
 	}
+	msg.GuildID = ev.GuildID
 
-	if msg.Author.ID == ev.UserID && !cfg.AllowSelfStar {
-		common.Log.Debug("self starring isn't allowed")
+	if bot.ignored(*msg) {
 		return
 	}
 
-	count, err := bot.removeReaction(msg.ID, ev.UserID)
+	_, err = bot.queries.RemoveReaction(context.Background(), queries.RemoveReactionParams{
+		UserID:    int64(ev.UserID),
+		MessageID: int64(msg.ID),
+		Emoji:     emoji,
+	})
 	if err != nil {
 		common.Log.Error("storing starboard reaction:", err)
 		return
 	}
 
-	err = bot.sendOrUpdateMessage(*msg, cfg, int(count))
-	if err != nil {
-		common.Log.Error("sending or updating starboard message:", err)
-	}
+	bot.updateBoards(*msg, boards)
 }
 
 func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
@@ -0,0 +1,255 @@
+package star
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/dustin/go-humanize"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/common"
+	"github.com/starshine-sys/oodles/star/queries"
+)
+
+const (
+	// how often recent starboard posts are checked against Discord
+	consistencyInterval = time.Hour
+	// how far back the consistency check looks
+	consistencyPeriod = 24 * time.Hour
+	// the maximum number of messages the rescan command will fetch
+	maxRescanMessages = 5000
+	// unknown message error code
+	errUnknownMessage httputil.ErrorCode = 10008
+)
+
+// rescanMessage rebuilds the stored reactions for a message from Discord,
+// then creates, updates, or deletes its starboard posts to match.
+func (bot *Bot) rescanMessage(m discord.Message, boards []queries.ChannelBoardsRow) error {
+	if bot.ignored(m) {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, b := range boards {
+		if seen[b.Emoji] {
+			continue
+		}
+		seen[b.Emoji] = true
+
+		_, err := bot.queries.RemoveEmojiReactions(context.Background(), int64(m.ID), b.Emoji)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range m.Reactions {
+			if emojiString(r.Emoji) != b.Emoji {
+				continue
+			}
+
+			users, err := bot.State.Reactions(m.ChannelID, m.ID, r.Emoji.APIString(), 0)
+			if err != nil {
+				return err
+			}
+
+			for _, u := range users {
+				if bot.blacklisted(m.GuildID, u.ID) {
+					continue
+				}
+
+				_, err = bot.queries.AddReaction(context.Background(), queries.AddReactionParams{
+					UserID:    int64(u.ID),
+					MessageID: int64(m.ID),
+					Emoji:     b.Emoji,
+					GuildID:   int64(m.GuildID),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	bot.updateBoards(m, boards)
+
+	// remove posts from boards the message can't be posted to anymore,
+	// because they were disabled or no longer include the channel
+	sms, err := bot.queries.StarboardMessages(context.Background(), int64(m.ID))
+	if err != nil {
+		return err
+	}
+
+	for _, sm := range sms {
+		if sm.MessageID != int64(m.ID) || sm.Locked || hasBoard(boards, sm.BoardID) {
+			continue
+		}
+
+		err = bot.deleteMessage(sm.StarboardChannelID, sm.StarboardID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasBoard(boards []queries.ChannelBoardsRow, id int) bool {
+	for _, b := range boards {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// messagesSince returns up to max messages sent in the channel after the given ID, newest first.
+// capped is true if there were more messages than that.
+func (bot *Bot) messagesSince(chID discord.ChannelID, after discord.MessageID, max int) (msgs []discord.Message, capped bool, err error) {
+	var before discord.MessageID
+	for {
+		page, err := bot.State.MessagesBefore(chID, before, 100)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, m := range page {
+			if m.ID <= after {
+				return msgs, false, nil
+			}
+			if len(msgs) >= max {
+				return msgs, true, nil
+			}
+			msgs = append(msgs, m)
+		}
+
+		if len(page) < 100 {
+			return msgs, false, nil
+		}
+		before = page[len(page)-1].ID
+	}
+}
+
+func (bot *Bot) rescan(ctx *bcr.Context) (err error) {
+	ch, err := ctx.ParseChannel(ctx.Args[0])
+	if err != nil || ch.GuildID != ctx.Guild.ID {
+		_, err = ctx.Replyc(bcr.ColourRed, "Channel not found.")
+		return err
+	}
+
+	since, err := parseSince(false, false, strings.Join(ctx.Args[1:], " "))
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+	if since.IsZero() {
+		since = time.Now().UTC().Add(-7 * day)
+	}
+
+	root, err := bot.RootChannel(ch.ID)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	boards, err := bot.activeBoards(ctx.Guild.ID, root)
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	msg, err := ctx.Sendf("Rescanning messages in %v sent since <t:%v>, this might take a while...", ch.Mention(), since.Unix())
+	if err != nil {
+		return err
+	}
+
+	// fetching and rescanning thousands of messages takes a while, so do it in the background
+	go func() {
+		msgs, capped, err := bot.messagesSince(ch.ID, discord.MessageID(discord.NewSnowflake(since)), maxRescanMessages)
+		if err != nil {
+			common.Log.Errorf("getting messages in %v: %v", ch.ID, err)
+			_, err = ctx.Edit(msg, "I couldn't fetch the messages in "+ch.Mention()+".", false)
+			if err != nil {
+				common.Log.Errorf("editing rescan message: %v", err)
+			}
+			return
+		}
+
+		var failed int
+		for _, m := range msgs {
+			// messages from the API don't have a guild ID
+			m.GuildID = ctx.Guild.ID
+
+			err = bot.rescanMessage(m, boards)
+			if err != nil {
+				common.Log.Errorf("rescanning message %v: %v", m.ID, err)
+				failed++
+			}
+		}
+
+		s := "Done! Rescanned " + humanize.Comma(int64(len(msgs))) + " messages"
+		if failed > 0 {
+			s += " (" + humanize.Comma(int64(failed)) + " failed)"
+		}
+		if capped {
+			s += "\nOnly the newest " + humanize.Comma(maxRescanMessages) + " messages were rescanned."
+		}
+
+		_, err = ctx.Edit(msg, s, false)
+		if err != nil {
+			common.Log.Errorf("editing rescan message: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// consistencyLoop periodically rescans messages that were recently posted to a starboard.
+func (bot *Bot) consistencyLoop() {
+	ticker := time.NewTicker(consistencyInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		bot.checkConsistency()
+	}
+}
+
+func (bot *Bot) checkConsistency() {
+	posts, err := bot.queries.RecentPosts(context.Background(), int64(discord.NewSnowflake(time.Now().Add(-consistencyPeriod))))
+	if err != nil {
+		common.Log.Errorf("getting recent starboard posts: %v", err)
+		return
+	}
+
+	for _, p := range posts {
+		// skip the cache, the point is to get up to date reactions
+		m, err := bot.State.Client.Message(discord.ChannelID(p.ChannelID), discord.MessageID(p.MessageID))
+		if err != nil {
+			// the original message was deleted while we weren't looking
+			var httpErr *httputil.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == errUnknownMessage {
+				bot.deleteAllMessages(discord.MessageID(p.MessageID), false)
+				continue
+			}
+
+			common.Log.Errorf("getting message %v: %v", p.MessageID, err)
+			continue
+		}
+
+		ch, err := bot.RootChannel(m.ChannelID)
+		if err != nil {
+			common.Log.Errorf("getting root channel: %v", err)
+			continue
+		}
+
+		boards, err := bot.activeBoards(ch.GuildID, ch)
+		if err != nil {
+			common.Log.Errorf("getting starboards: %v", err)
+			continue
+		}
+
+		m.GuildID = ch.GuildID
+		err = bot.rescanMessage(*m, boards)
+		if err != nil {
+			common.Log.Errorf("rescanning message %v: %v", m.ID, err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package star
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeberg.org/eviedelta/detctime/durationparser"
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/dustin/go-humanize"
+	"github.com/jackc/pgx/v4"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/oodles/star/queries"
+)
+
+const day = 24 * time.Hour
+
+func postLink(guildID, channelID, messageID int64) string {
+	return fmt.Sprintf("https://discord.com/channels/%v/%v/%v", guildID, channelID, messageID)
+}
+
+func plural(n int64, s string) string {
+	if n == 1 {
+		return fmt.Sprintf("%v %v", n, s)
+	}
+	return fmt.Sprintf("%v %vs", humanize.Comma(n), s)
+}
+
+// boardFlag returns the ID of the board given with --board, or 0 for all boards.
+func (bot *Bot) boardFlag(ctx *bcr.Context) (id int, ok bool, err error) {
+	name, _ := ctx.Flags.GetString("board")
+	if name == "" {
+		return 0, true, nil
+	}
+
+	b, ok, err := bot.board(ctx, name)
+	return b.ID, ok, err
+}
+
+// parseSince parses the time filter flags of the top command.
+// since is either a date (YYYY-MM-DD) or a duration before now.
+func parseSince(weekly, monthly bool, since string) (t time.Time, err error) {
+	now := time.Now().UTC()
+
+	switch {
+	case since != "":
+		if t, err := time.Parse("2006-01-02", since); err == nil {
+			return t, nil
+		}
+
+		dur, err := durationparser.Parse(since)
+		if err != nil {
+			return t, fmt.Errorf("I couldn't parse `%v` as a date (YYYY-MM-DD) or duration", since)
+		}
+		return now.Add(-dur), nil
+	case monthly:
+		return now.AddDate(0, -1, 0), nil
+	case weekly:
+		return now.Add(-7 * day), nil
+	}
+	return t, nil
+}
+
+func (bot *Bot) topMessages(ctx *bcr.Context) (err error) {
+	boardID, ok, err := bot.boardFlag(ctx)
+	if !ok {
+		return err
+	}
+
+	weekly, _ := ctx.Flags.GetBool("weekly")
+	monthly, _ := ctx.Flags.GetBool("monthly")
+	sinceStr, _ := ctx.Flags.GetString("since")
+
+	since, err := parseSince(weekly, monthly, sinceStr)
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v", err)
+		return err
+	}
+
+	var minID int64
+	if !since.IsZero() {
+		minID = int64(discord.NewSnowflake(since))
+	}
+
+	msgs, err := bot.queries.TopMessages(context.Background(), queries.TopMessagesParams{
+		GuildID: int64(ctx.Guild.ID),
+		BoardID: boardID,
+		MinID:   minID,
+	})
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(msgs) == 0 {
+		return ctx.SendX("There are no starred messages yet.")
+	}
+
+	var lines []string
+	for i, m := range msgs {
+		s := fmt.Sprintf("`#%v` [%v](%v) in %v", i+1, plural(int64(m.StarCount), "star"), postLink(m.GuildID, m.StarboardChannelID, m.StarboardID), discord.ChannelID(m.ChannelID).Mention())
+		if m.AuthorID != 0 {
+			s += " by " + discord.UserID(m.AuthorID).Mention()
+		}
+		if boardID == 0 {
+			s += fmt.Sprintf(" (%v)", m.BoardName)
+		}
+		lines = append(lines, s+"\n")
+	}
+
+	title := "Most starred messages"
+	if !since.IsZero() {
+		title += " since " + since.Format("2006-01-02")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator(title, bcr.ColourGold, lines, 10),
+		10*time.Minute,
+	)
+	return err
+}
+
+func (bot *Bot) randomMessage(ctx *bcr.Context) (err error) {
+	var boardID int
+	if len(ctx.Args) > 0 {
+		b, ok, err := bot.board(ctx, strings.Join(ctx.Args, " "))
+		if !ok {
+			return err
+		}
+		boardID = b.ID
+	}
+
+	m, err := bot.queries.RandomMessage(context.Background(), int64(ctx.Guild.ID), boardID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ctx.SendX("There are no starred messages yet.")
+		}
+		return bot.Report(ctx, err)
+	}
+
+	link := postLink(m.GuildID, m.StarboardChannelID, m.StarboardID)
+
+	// repost the starboard message itself if we can, otherwise just link to it
+	// (NSFW posts are only ever linked to outside of NSFW channels,
+	// and posts on boards the user can't see are never reposted)
+	perms, err := ctx.State.Permissions(discord.ChannelID(m.StarboardChannelID), ctx.Author.ID)
+	if err != nil || !perms.Has(discord.PermissionViewChannel) {
+		return ctx.SendfX("Random message from the %v starboard: %v", bcr.AsCode(m.BoardName), link)
+	}
+
+	post, err := ctx.State.Message(discord.ChannelID(m.StarboardChannelID), discord.MessageID(m.StarboardID))
+	if err != nil || !bot.canPost(discord.ChannelID(m.StarboardChannelID), ctx.Message.ChannelID) {
+		return ctx.SendfX("Random message from the %v starboard: %v", bcr.AsCode(m.BoardName), link)
+	}
+
+	embeds := post.Embeds
+	if len(embeds) > 0 {
+		embeds[0].URL = link
+	}
+
+	_, err = ctx.State.SendMessageComplex(ctx.Message.ChannelID, api.SendMessageData{
+		Content:         post.Content,
+		Embeds:          embeds,
+		AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+	})
+	return err
+}
+
+func (bot *Bot) userStats(ctx *bcr.Context) (err error) {
+	u := &ctx.Author
+	if len(ctx.Args) > 0 {
+		u, err = ctx.ParseUser(strings.Join(ctx.Args, " "))
+		if err != nil {
+			_, err = ctx.Replyc(bcr.ColourRed, "User not found.")
+			return err
+		}
+	}
+
+	received, err := bot.queries.StarsReceived(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	given, err := bot.queries.StarsGiven(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	e := discord.Embed{
+		Title: "Starboard stats for " + u.Tag(),
+		Thumbnail: &discord.EmbedThumbnail{
+			URL: u.AvatarURL(),
+		},
+		Color: bcr.ColourGold,
+		Fields: []discord.EmbedField{
+			{
+				Name:   "Stars received",
+				Value:  fmt.Sprintf("%v on %v", plural(received.Stars, "star"), plural(received.Messages, "message")),
+				Inline: true,
+			},
+			{
+				Name:   "Stars given",
+				Value:  plural(given, "star"),
+				Inline: true,
+			},
+		},
+	}
+
+	top, err := bot.queries.UserTopMessage(context.Background(), int64(ctx.Guild.ID), int64(u.ID))
+	if err == nil {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Most starred message",
+			Value: fmt.Sprintf("[%v](%v) in %v", plural(int64(top.StarCount), "star"), postLink(top.GuildID, top.StarboardChannelID, top.StarboardID), discord.ChannelID(top.ChannelID).Mention()),
+		})
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return bot.Report(ctx, err)
+	}
+
+	return ctx.SendX("", e)
+}
+
+func (bot *Bot) starGivers(ctx *bcr.Context) (err error) {
+	givers, err := bot.queries.StarGivers(context.Background(), int64(ctx.Guild.ID))
+	if err != nil {
+		return bot.Report(ctx, err)
+	}
+
+	if len(givers) == 0 {
+		return ctx.SendX("Nobody has starred any messages yet.")
+	}
+
+	var lines []string
+	for i, g := range givers {
+		lines = append(lines, fmt.Sprintf("`#%v` %v: %v\n", i+1, discord.UserID(g.UserID).Mention(), plural(g.Stars, "star")))
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Top star givers", bcr.ColourGold, lines, 15),
+		10*time.Minute,
+	)
+	return err
+}